@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRouterMountsUnderConfiguredBasePath(t *testing.T) {
+	tests := []struct {
+		basePath string
+		mounted  []string
+		missing  []string
+	}{
+		{
+			basePath: "/reports/v2",
+			mounted:  []string{"/reports/v2/health", "/reports/v2/version", "/reports/v2/analytics/country-revenue", "/reports/v2/transactions"},
+			missing:  []string{"/api/v1/health", "/api/v1/analytics/country-revenue"},
+		},
+		{
+			basePath: "",
+			mounted:  []string{"/health", "/analytics/country-revenue"},
+			missing:  []string{"/api/v1/health"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("base path "+swaggerBasePath(tt.basePath), func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.APIBasePath = tt.basePath
+			router := newTestRouter(t, cfg)
+
+			for _, path := range tt.mounted {
+				if w := serveRequest(router, http.MethodGet, path, ""); w.Code != http.StatusOK {
+					t.Errorf("GET %s = %d, want 200", path, w.Code)
+				}
+			}
+			for _, path := range tt.missing {
+				if w := serveRequest(router, http.MethodGet, path, ""); w.Code != http.StatusNotFound {
+					t.Errorf("GET %s = %d, want 404", path, w.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestSwaggerBasePath(t *testing.T) {
+	for basePath, want := range map[string]string{"": "/", "/api/v1": "/api/v1", "/reports": "/reports"} {
+		if got := swaggerBasePath(basePath); got != want {
+			t.Errorf("swaggerBasePath(%q) = %q, want %q", basePath, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const ingestRow = `{"transaction_date":"2024-03-01T10:00:00Z","country":"US","region":"Texas","product":"Widget","quantity":1,"revenue":"12.50"}`
+
+func TestBodyLimitGuardsIngestion(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxBodyBytes = 512
+	router := newTestRouter(t, cfg)
+	batch := cfg.APIBasePath + "/transactions/batch"
+
+	// Enough rows to push the batch past the 512 byte limit
+	oversized := "[" + strings.Repeat(ingestRow+",", 8) + ingestRow + "]"
+
+	t.Run("under the limit", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, batch, "["+ingestRow+"]")
+		if w.Code != http.StatusCreated {
+			t.Errorf("status = %d, want 201; body %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("declared length over the limit", func(t *testing.T) {
+		w := serveRequest(router, http.MethodPost, batch, oversized)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want 413; body %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("unknown length over the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, batch, strings.NewReader(oversized))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("status = %d, want 413; body %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("GET routes are not limited", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/transactions", oversized)
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200; body %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+func TestAnalyticsCatalogListsEndpointsWithParams(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Features = map[string]bool{}
+	router := newTestRouter(t, cfg)
+
+	w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var catalog models.AnalyticsCatalog
+	if err := json.Unmarshal(w.Body.Bytes(), &catalog); err != nil {
+		t.Fatalf("decode catalog: %v", err)
+	}
+	listed := map[string]models.AnalyticsEndpoint{}
+	for _, endpoint := range catalog.Endpoints {
+		listed[endpoint.Path] = endpoint
+	}
+
+	// every registered analytics route is listed, and nothing else
+	root := cfg.APIBasePath + "/analytics/"
+	routes := 0
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, root) {
+			routes++
+		}
+	}
+	if len(catalog.Endpoints) != routes {
+		t.Errorf("catalog lists %d endpoints, want the %d registered routes", len(catalog.Endpoints), routes)
+	}
+	if _, ok := listed[root+"growth"]; ok {
+		t.Error("catalog lists growth with the feature disabled")
+	}
+
+	tests := []struct {
+		path   string
+		params map[string]string
+	}{
+		{
+			path: "country-revenue",
+			params: map[string]string{
+				"country": "array", "from": "string", "to": "string", "all": "boolean", "format": "string",
+				"decimal": "string", "currency": "string", "normalize": "boolean",
+			},
+		},
+		{
+			path: "top-products",
+			params: map[string]string{
+				"country": "string", "from": "string", "to": "string", "all": "boolean", "sort": "string",
+				"dir": "string", "limit": "integer", "offset": "integer", "format": "string",
+				"decimal": "string", "currency": "string", "normalize": "boolean",
+			},
+		},
+		{
+			path: "monthly-sales",
+			params: map[string]string{
+				"product": "string", "category": "string", "granularity": "string", "compare": "string",
+				"from": "string", "to": "string", "all": "boolean", "tz": "string", "currency": "string",
+				"normalize": "boolean",
+			},
+		},
+		{
+			path: "top-regions",
+			params: map[string]string{
+				"from": "string", "to": "string", "all": "boolean", "n": "integer", "currency": "string",
+				"normalize": "boolean",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			endpoint, ok := listed[root+tt.path]
+			if !ok {
+				t.Fatalf("catalog does not list %s", root+tt.path)
+			}
+			if endpoint.Method != http.MethodGet || endpoint.Summary == "" {
+				t.Errorf("method = %q, summary = %q; want GET with a summary", endpoint.Method, endpoint.Summary)
+			}
+			got := map[string]string{}
+			for _, param := range endpoint.Params {
+				if param.In != "query" {
+					t.Errorf("param %s is in %s, want only query parameters", param.Name, param.In)
+				}
+				got[param.Name] = param.Type
+			}
+			if len(got) != len(tt.params) {
+				t.Errorf("params = %v, want %v", got, tt.params)
+			}
+			for name, typ := range tt.params {
+				if got[name] != typ {
+					t.Errorf("param %s has type %q, want %q", name, got[name], typ)
+				}
+			}
+		})
+	}
+}
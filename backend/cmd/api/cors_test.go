@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/middleware"
+)
+
+func TestCORSAllowsConfiguredOrigins(t *testing.T) {
+	const allowed = "https://dashboard.example.com"
+	tests := []struct {
+		name        string
+		origins     []string
+		origin      string
+		allow       string
+		credentials string
+	}{
+		{name: "allowed origin", origins: []string{allowed}, origin: allowed, allow: allowed, credentials: "true"},
+		{name: "disallowed origin", origins: []string{allowed}, origin: "https://evil.example.com"},
+		{name: "wildcard without credentials", origins: []string{"*"}, origin: "https://any.example.com", allow: "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.CORSOrigins = tt.origins
+			router := newTestRouter(t, cfg)
+
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/health", "", "Origin", tt.origin)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allow)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+			if tt.allow == "" && w.Code == http.StatusOK {
+				t.Errorf("request from a disallowed origin was served")
+			}
+		})
+	}
+}
+
+func TestCORSConfigFromEnvironment(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		methods []string
+		headers []string
+		maxAge  time.Duration
+	}{
+		{
+			name:    "defaults",
+			methods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			headers: []string{"Origin", "Content-Type", "Authorization", "Accept", "User-Agent", "Cache-Control", "Pragma"},
+			maxAge:  12 * time.Hour,
+		},
+		{
+			name:    "overrides",
+			env:     map[string]string{"CORS_METHODS": "get, post", "CORS_HEADERS": "Content-Type,X-Tenant", "CORS_MAX_AGE": "30m"},
+			methods: []string{"GET", "POST"},
+			headers: []string{"Content-Type", "X-Tenant"},
+			maxAge:  30 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"CORS_METHODS", "CORS_HEADERS", "CORS_MAX_AGE"} {
+				// Setenv restores the variable after the test, even when it is then unset
+				t.Setenv(key, tt.env[key])
+				if _, ok := tt.env[key]; !ok {
+					os.Unsetenv(key)
+				}
+			}
+			cfg := testConfig(t)
+			corsCfg := corsConfig(cfg)
+
+			if !reflect.DeepEqual(corsCfg.AllowMethods, tt.methods) {
+				t.Errorf("AllowMethods = %v, want %v", corsCfg.AllowMethods, tt.methods)
+			}
+			// The headers the API itself reads are always allowed
+			headers := append(tt.headers, middleware.APIKeyHeader, controllers.APIVersionHeader, cfg.RequestIDHeader)
+			if !reflect.DeepEqual(corsCfg.AllowHeaders, headers) {
+				t.Errorf("AllowHeaders = %v, want %v", corsCfg.AllowHeaders, headers)
+			}
+			if corsCfg.MaxAge != tt.maxAge {
+				t.Errorf("MaxAge = %s, want %s", corsCfg.MaxAge, tt.maxAge)
+			}
+		})
+	}
+
+	t.Run("unknown method", func(t *testing.T) {
+		t.Setenv("APP_ENV", config.EnvTest)
+		t.Setenv("CORS_METHODS", "GET,FETCH")
+		if err := config.Load().Validate(); err == nil || !strings.Contains(err.Error(), `CORS_METHODS entry "FETCH"`) {
+			t.Errorf("Validate() = %v, want FETCH reported", err)
+		}
+	})
+}
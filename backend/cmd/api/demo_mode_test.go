@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/metrics"
+	"abt-analytics/internal/middleware"
+	"abt-analytics/internal/services"
+)
+
+func TestDemoModeHeaderMarksSampleData(t *testing.T) {
+	cfg := testConfig(t)
+	demoService := services.NewDemoAnalyticsService(cfg)
+	demoController := controllers.NewAnalyticsController(demoService, cfg, healthCheckers(demoService, nil))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	routers := []struct {
+		name  string
+		want  string
+		serve http.Handler
+	}{
+		{name: "demo data", want: "true", serve: setupRouter(cfg, demoController, true, logger, metrics.New())},
+		{name: "database", want: "", serve: newTestRouter(t, cfg)},
+	}
+	for _, router := range routers {
+		for _, path := range []string{"/health", "/analytics/country-revenue", "/transactions/nope"} {
+			t.Run(router.name+path, func(t *testing.T) {
+				w := serveRequest(router.serve, http.MethodGet, cfg.APIBasePath+path, "")
+				if got := w.Header().Get(middleware.DemoModeHeader); got != router.want {
+					t.Errorf("%s = %q with status %d, want %q", middleware.DemoModeHeader, got, w.Code, router.want)
+				}
+			})
+		}
+	}
+}
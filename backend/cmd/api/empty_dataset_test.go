@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+// nullPaths returns the sorted paths of the null values in a decoded JSON document
+func nullPaths(doc interface{}) []string {
+	var paths []string
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		switch value := value.(type) {
+		case nil:
+			paths = append(paths, path)
+		case map[string]interface{}:
+			for key, child := range value {
+				walk(strings.TrimPrefix(path+"."+key, "."), child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(path+"[]", child)
+			}
+		}
+	}
+	walk("", doc)
+	sort.Strings(paths)
+	return paths
+}
+
+func TestEveryEndpointAnswersAnEmptyDatabase(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.SeedOnStartup = false
+	router := newTestRouter(t, cfg)
+
+	tests := []struct {
+		path string
+		// empty is the expected body for a list, or the nulls of an object
+		empty string
+		nulls []string
+	}{
+		{path: "/analytics/country-revenue", empty: "[]"},
+		{path: "/analytics/category-revenue", empty: "[]"},
+		{path: "/analytics/avg-order-value", empty: "[]"},
+		{path: "/analytics/order-value-percentiles", nulls: []string{"p50", "p90", "p95", "p99"}},
+		{
+			path:  "/analytics/compare?a_from=2023-01-01&a_to=2023-03-31&b_from=2023-04-01&b_to=2023-06-30&metric=aov",
+			nulls: []string{"a.value", "b.value", "difference", "percent_change"},
+		},
+		{path: "/analytics/compare?a_from=2023-01-01&a_to=2023-03-31&b_from=2023-04-01&b_to=2023-06-30", nulls: []string{"percent_change"}},
+		{path: "/analytics/top-products"},
+		{path: "/analytics/top-customers"},
+		{path: "/analytics/revenue-concentration"},
+		{path: "/analytics/monthly-sales", empty: "[]"},
+		{path: "/analytics/growth", empty: "[]"},
+		{path: "/analytics/top-regions", empty: "[]"},
+		{path: "/analytics/country/Germany/regions", empty: "[]"},
+		{path: "/analytics/region-trends", empty: "[]"},
+		{path: "/analytics/summary"},
+		{path: "/analytics/meta", nulls: []string{"default_range", "last_transaction_date"}},
+		{path: "/analytics/dimensions"},
+		{path: "/transactions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+tt.path, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200; body %s", w.Code, w.Body.String())
+			}
+			if tt.empty != "" {
+				if got := strings.TrimSpace(w.Body.String()); got != tt.empty {
+					t.Errorf("body = %s, want %s", got, tt.empty)
+				}
+				return
+			}
+
+			var doc map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil || doc == nil {
+				t.Fatalf("body %s is not an object: %v", w.Body.String(), err)
+			}
+			if got := nullPaths(doc); !reflect.DeepEqual(got, tt.nulls) {
+				t.Errorf("nulls at %v, want only %v; body %s", got, tt.nulls, w.Body.String())
+			}
+		})
+	}
+
+	// A forecast needs history, so it is the one endpoint that refuses an empty table
+	t.Run("forecast reports missing history", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/forecast", "")
+		var body models.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusUnprocessableEntity || body.Code != models.ErrCodeInsufficientData {
+			t.Errorf("status %d, body %s; want 422 %s", w.Code, w.Body.String(), models.ErrCodeInsufficientData)
+		}
+	})
+
+	t.Run("daily revenue zero-fills the window", func(t *testing.T) {
+		w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/daily-revenue?from=2023-01-01&to=2023-01-03", "")
+		var days []struct {
+			Revenue string `json:"revenue"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &days); err != nil || len(days) != 3 {
+			t.Fatalf("body %s, want three days: %v", w.Body.String(), err)
+		}
+		for _, day := range days {
+			if day.Revenue != "0.00" {
+				t.Errorf("revenue = %s, want 0.00", day.Revenue)
+			}
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"abt-analytics/internal/config"
+)
+
+func TestGrowthRouteFollowsFeatureFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		features map[string]bool
+		want     int
+	}{
+		{name: "enabled", features: map[string]bool{config.FeatureGrowth: true}, want: http.StatusOK},
+		{name: "disabled", features: map[string]bool{}, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Features = tt.features
+			router := newTestRouter(t, cfg)
+
+			if w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/growth", ""); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			// routes outside the flag are served either way
+			if w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/monthly-sales", ""); w.Code != http.StatusOK {
+				t.Errorf("monthly sales status = %d, want 200", w.Code)
+			}
+		})
+	}
+}
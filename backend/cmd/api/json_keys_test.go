@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"abt-analytics/internal/config"
+)
+
+// jsonKeys returns the sorted key paths of a decoded JSON document, such as
+// "data[].revenue", merging the keys of every element of an array
+func jsonKeys(doc interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for key, child := range value {
+				path := strings.TrimPrefix(prefix+"."+key, ".")
+				seen[path] = true
+				walk(path, child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(prefix+"[]", child)
+			}
+		}
+	}
+	walk("", doc)
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestAnalyticsResponsesUseExactKeys(t *testing.T) {
+	cfg := testConfig(t)
+	router := newTestRouter(t, cfg)
+
+	period := []string{"[].period", "[].revenue"}
+	regions := []string{"[].order_count", "[].region", "[].revenue"}
+	pageLinks := []string{"links", "links.next", "links.self"}
+	tests := []struct {
+		path string
+		keys []string
+	}{
+		{"/analytics/country-revenue", []string{"[].country", "[].order_count", "[].percentage", "[].revenue"}},
+		{"/analytics/category-revenue", []string{"[].category", "[].revenue"}},
+		{"/analytics/avg-order-value", []string{"[].average_order_value", "[].country", "[].order_count"}},
+		{"/analytics/order-value-percentiles", []string{"order_count", "p50", "p90", "p95", "p99"}},
+		{
+			"/analytics/compare?a_from=2023-01-01&a_to=2023-03-31&b_from=2023-04-01&b_to=2023-06-30",
+			[]string{"a", "a.from", "a.to", "a.value", "b", "b.from", "b.to", "b.value", "difference", "metric", "percent_change"},
+		},
+		{
+			"/analytics/top-products?limit=2",
+			append([]string{"data", "data[].product", "data[].revenue", "data[].units", "limit"}, append(pageLinks, "offset", "total")...),
+		},
+		{
+			"/analytics/top-customers?limit=2",
+			append([]string{"data", "data[].customer_id", "data[].order_count", "data[].revenue", "limit"}, append(pageLinks, "offset", "total")...),
+		},
+		{
+			"/analytics/revenue-concentration",
+			[]string{
+				"products", "products[].cumulative_share", "products[].product", "products[].revenue", "products[].share",
+				"thresholds", "thresholds[].products", "thresholds[].threshold", "total_revenue",
+			},
+		},
+		{"/analytics/monthly-sales", period},
+		{"/analytics/growth", []string{"[].growth_pct", "[].period", "[].revenue"}},
+		{"/analytics/forecast", []string{"[].forecast", "[].period", "[].revenue"}},
+		{"/analytics/daily-revenue?from=2023-01-01&to=2023-01-03", []string{"[].date", "[].revenue"}},
+		{"/analytics/top-regions", regions},
+		{"/analytics/country/Germany/regions", regions},
+		{"/analytics/region-trends", []string{"[].region", "[].revenue", "[].series", "[].series[].period", "[].series[].revenue"}},
+		{
+			"/analytics/summary",
+			[]string{
+				"country_revenue", "country_revenue[].country", "country_revenue[].order_count", "country_revenue[].percentage", "country_revenue[].revenue",
+				"monthly_sales", "monthly_sales[].period", "monthly_sales[].revenue",
+				"top_products", "top_products.data", "top_products.data[].product", "top_products.data[].revenue", "top_products.data[].units",
+				"top_products.limit", "top_products.offset", "top_products.total",
+				"top_regions", "top_regions[].order_count", "top_regions[].region", "top_regions[].revenue",
+			},
+		},
+		{"/analytics/meta", []string{"default_range", "last_transaction_date", "total_transactions"}},
+		{"/analytics/dimensions", []string{"categories", "countries", "products", "regions"}},
+		{
+			"/transactions/1",
+			[]string{
+				"category", "country", "created_at", "customer_id", "id", "order_id", "product",
+				"quantity", "region", "revenue", "transaction_date", "updated_at",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+tt.path, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200; body %s", w.Code, w.Body.String())
+			}
+			var doc interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("decode %s: %v", w.Body.String(), err)
+			}
+			want := append([]string(nil), tt.keys...)
+			sort.Strings(want)
+			if got := jsonKeys(doc); !reflect.DeepEqual(got, want) {
+				t.Errorf("keys = %v\nwant %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCamelCaseResponsesRenameEveryKey(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.JSONNaming = config.JSONNamingCamelCase
+	router := newTestRouter(t, cfg)
+
+	tests := []struct {
+		path string
+		keys []string
+	}{
+		{"/analytics/avg-order-value", []string{"[].averageOrderValue", "[].country", "[].orderCount"}},
+		{"/analytics/meta", []string{"defaultRange", "lastTransactionDate", "totalTransactions"}},
+		{
+			"/analytics/top-customers?limit=2",
+			[]string{"data", "data[].customerId", "data[].orderCount", "data[].revenue", "limit", "links", "links.next", "links.self", "offset", "total"},
+		},
+		{
+			"/transactions/1",
+			[]string{
+				"category", "country", "createdAt", "customerId", "id", "orderId", "product",
+				"quantity", "region", "revenue", "transactionDate", "updatedAt",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+tt.path, "")
+			var doc interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("decode %s: %v", w.Body.String(), err)
+			}
+			if got := jsonKeys(doc); !reflect.DeepEqual(got, tt.keys) {
+				t.Errorf("keys = %v\nwant %v", got, tt.keys)
+			}
+		})
+	}
+}
@@ -1,24 +1,36 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
-	"gorm.io/driver/mysql"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
+	"abt-analytics/docs"
+	"abt-analytics/internal/buildinfo"
+	"abt-analytics/internal/cache"
 	"abt-analytics/internal/config"
 	"abt-analytics/internal/controllers"
-	"abt-analytics/internal/models"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/health"
+	"abt-analytics/internal/metrics"
+	"abt-analytics/internal/middleware"
 	"abt-analytics/internal/repository"
 	"abt-analytics/internal/services"
-	_ "abt-analytics/docs" // Import generated docs
+	"abt-analytics/internal/tracing"
 )
 
 // @title ABT Analytics API
@@ -33,103 +45,360 @@ import (
 // @host localhost:8080
 // @BasePath /api/v1
 // @schemes http https
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	logger := newLogger(cfg)
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded", "profile", cfg.Env)
 
-	// Try to connect to database (optional for demo)
-	db, err := connectDatabase(cfg)
-	var analyticsController *controllers.AnalyticsController
-	
+	// Tracing is a no-op unless an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
 	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	// Connect to the database unless demo mode was requested
+	var db *gorm.DB
+	var analyticsController *controllers.AnalyticsController
+	// background jobs run until shutdown starts
+	var background []func(ctx context.Context)
+	appMetrics := metrics.New()
+
+	if cfg.DemoMode {
+		log.Println("DEMO_MODE is set, skipping the database")
+	} else if db, err = database.Connect(cfg); err != nil {
+		if cfg.DBRequired {
+			log.Fatalf("Could not connect to database: %v", err)
+		}
 		log.Printf("Warning: Could not connect to database: %v", err)
-		log.Println("Running in demo mode without database functionality")
-		// Create a mock controller for demo purposes
-		analyticsController = controllers.NewAnalyticsController(nil)
+	}
+
+	if db == nil {
+		// Serve the built-in sample data so the dashboard stays usable
+		log.Println("Running in demo mode with built-in sample data")
+		demoService := services.NewDemoAnalyticsService(cfg)
+		analyticsController = controllers.NewAnalyticsController(demoService, cfg, healthCheckers(demoService, nil))
 	} else {
 		// Auto migrate
-		if err := db.AutoMigrate(&models.Transaction{}); err != nil {
+		if err := database.Migrate(db); err != nil {
 			log.Printf("Warning: Failed to migrate database: %v", err)
 		}
 
 		// Seed data
-		if err := seedData(db); err != nil {
-			log.Printf("Warning: Failed to seed data: %v", err)
+		if cfg.SeedOnStartup {
+			if err := services.NewDataSeeder(db, cfg).Run(context.Background()); err != nil {
+				log.Printf("Warning: Failed to seed data: %v", err)
+			}
+		}
+
+		// Export connection pool stats
+		if sqlDB, err := db.DB(); err == nil {
+			appMetrics.RegisterDBStats(sqlDB)
 		}
 
 		// Initialize repository, services and controllers
-		analyticsRepo := repository.NewAnalyticsRepository(db)
-		analyticsService := services.NewAnalyticsService(analyticsRepo)
-		analyticsController = controllers.NewAnalyticsController(analyticsService)
+		analyticsRepo := repository.NewAnalyticsRepository(db, cfg.Rates)
+		appCache := newCache(cfg)
+		analyticsService := services.NewAnalyticsService(analyticsRepo, appCache, cfg)
+		analyticsController = controllers.NewAnalyticsController(analyticsService, cfg, healthCheckers(analyticsService, appCache))
+
+		if cfg.PrecomputeInterval > 0 {
+			productLimit, regionCount := controllers.SummarySizes(cfg)
+			background = append(background, func(ctx context.Context) {
+				analyticsService.Precompute(ctx, cfg.PrecomputeInterval, productLimit, regionCount)
+			})
+		}
 	}
 
 	// Setup router
-	router := setupRouter(analyticsController)
+	router := setupRouter(cfg, analyticsController, db == nil, logger, appMetrics)
 
 	// Start server
-	log.Printf("🚀 Server starting on port %s", cfg.Port)
-	log.Printf("📚 Swagger documentation: http://localhost:%s/swagger/index.html", cfg.Port)
-	log.Printf("🏥 Health check: http://localhost:%s/api/v1/health", cfg.Port)
-	log.Printf("📊 Analytics API: http://localhost:%s/api/v1/analytics/", cfg.Port)
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, router))
+	build := buildinfo.Get()
+	log.Printf("🚀 Server starting on port %s (commit %s, built %s)", cfg.Port, build.Commit, build.BuildTime)
+	log.Printf("📈 Metrics: http://localhost:%s/metrics", cfg.Port)
+	if cfg.SwaggerEnabled {
+		log.Printf("📚 Swagger documentation: http://localhost:%s/swagger/index.html", cfg.Port)
+	}
+	log.Printf("📄 OpenAPI spec: http://localhost:%s%s/openapi.json", cfg.Port, cfg.APIBasePath)
+	log.Printf("🏥 Health check: http://localhost:%s%s/health", cfg.Port, cfg.APIBasePath)
+	log.Printf("✅ Readiness check: http://localhost:%s%s/ready", cfg.Port, cfg.APIBasePath)
+	log.Printf("🏷️ Version: http://localhost:%s%s/version", cfg.Port, cfg.APIBasePath)
+	log.Printf("📊 Analytics API: http://localhost:%s%s/analytics/", cfg.Port, cfg.APIBasePath)
+	log.Printf("🧾 Transactions API: http://localhost:%s%s/transactions", cfg.Port, cfg.APIBasePath)
+
+	// Stop on SIGINT/SIGTERM, letting in-flight requests finish first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var jobs sync.WaitGroup
+	for _, job := range background {
+		jobs.Add(1)
+		go func(job func(ctx context.Context)) {
+			defer jobs.Done()
+			job(ctx)
+		}(job)
+	}
+
+	server := newHTTPServer(cfg, router)
+	if err := serve(ctx, server, cfg.ShutdownTimeout); err != nil {
+		log.Printf("Server error: %v", err)
+	}
+
+	// The server may also have stopped on an error; either way the jobs stop
+	// before the database they query is closed
+	stop()
+	jobs.Wait()
+
+	database.Close(db)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Printf("Warning: failed to flush traces: %v", err)
+	}
+
+	log.Println("👋 Server stopped cleanly")
+}
+
+// healthCheckers lists the dependencies probed by /health. The database is
+// critical; a Redis cache is not, since a cache failure only costs extra queries.
+// In demo mode the missing database is reported as a non-critical failure, so
+// the API shows as degraded while still serving the sample data.
+func healthCheckers(service controllers.AnalyticsService, appCache cache.Cache) []health.Checker {
+	if _, demo := service.(*services.DemoAnalyticsService); demo {
+		return []health.Checker{health.NewChecker("database", false, func(context.Context) error {
+			return errors.New("database not configured, serving demo data")
+		})}
+	}
+
+	checkers := []health.Checker{health.NewChecker("database", true, service.Ping)}
+	if redisCache, ok := appCache.(*cache.RedisCache); ok {
+		checkers = append(checkers, health.NewChecker("cache", false, redisCache.Ping))
+	}
+	return checkers
 }
 
-func connectDatabase(cfg *config.Config) (*gorm.DB, error) {
-	dsn := cfg.GetDSN()
-	
-	// Try to connect to database with shorter timeout for demo
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+// swaggerBasePath is the base path advertised by the API docs, which must not be empty
+func swaggerBasePath(apiBasePath string) string {
+	if apiBasePath == "" {
+		return "/"
+	}
+	return apiBasePath
+}
+
+// newCache builds the configured cache backend, falling back to memory when Redis is unreachable
+func newCache(cfg *config.Config) cache.Cache {
+	if cfg.CacheBackend != config.CacheBackendRedis {
+		return cache.NewMemoryCache()
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
 	})
-	
-	return db, err
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Warning: Could not connect to Redis at %s: %v", cfg.RedisAddr, err)
+		log.Println("Falling back to in-memory cache")
+		_ = client.Close()
+		return cache.NewMemoryCache()
+	}
+
+	log.Printf("Using Redis cache at %s", cfg.RedisAddr)
+	return cache.NewRedisCache(client, "abt-analytics:")
 }
 
-func setupRouter(analyticsController *controllers.AnalyticsController) *gin.Engine {
-	router := gin.Default()
+func newLogger(cfg *config.Config) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	if cfg.LogFormat == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, opts))
+}
+
+// corsConfig allows the configured origins with credentials. A "*" entry allows
+// every origin instead, without credentials, since browsers refuse that combination.
+func corsConfig(cfg *config.Config) cors.Config {
+	corsCfg := cors.Config{
+		AllowMethods:  cfg.CORSMethods,
+		AllowHeaders:  append(append([]string(nil), cfg.CORSHeaders...), middleware.APIKeyHeader, controllers.APIVersionHeader, cfg.RequestIDHeader),
+		ExposeHeaders: []string{"Content-Length", "ETag", "X-Cache", "Retry-After", controllers.TotalCountHeader, controllers.APIVersionHeader, controllers.DefaultRangeFromHeader, controllers.DefaultRangeToHeader, middleware.DemoModeHeader, cfg.RequestIDHeader},
+		MaxAge:        cfg.CORSMaxAge,
+	}
+
+	for _, origin := range cfg.CORSOrigins {
+		if origin == "*" {
+			log.Println("Warning: CORS_ORIGINS contains *, allowing every origin without credentials")
+			corsCfg.AllowAllOrigins = true
+			return corsCfg
+		}
+	}
+
+	corsCfg.AllowOrigins = cfg.CORSOrigins
+	corsCfg.AllowCredentials = true
+	return corsCfg
+}
+
+// setupRouter builds the router serving analyticsController; demo marks every
+// response as coming from the built-in sample data
+func setupRouter(cfg *config.Config, analyticsController *controllers.AnalyticsController, demo bool, logger *slog.Logger, appMetrics *metrics.Metrics) *gin.Engine {
+	router := gin.New()
+
+	// Only believe X-Forwarded-For from known proxies, so clients cannot pick the
+	// IP they are rate limited and logged under
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Tracing outermost so the server span covers every other middleware
+	router.Use(otelgin.Middleware(cfg.ServiceName))
+
+	// Request IDs first so every later middleware and handler can use them
+	router.Use(middleware.RequestID(cfg.RequestIDHeader))
+
+	// Structured request logging, then panic recovery inside it so that the
+	// request line records the 500 a recovered panic is answered with
+	router.Use(middleware.RequestLogger(logger, cfg.LogSampleRate))
+	router.Use(middleware.Recovery(logger))
+
+	// Query durations in a Server-Timing header, for performance tuning
+	if cfg.DebugTiming {
+		router.Use(middleware.ServerTiming())
+	}
+
+	// Per-request deadline, propagated to database queries through the request context
+	if cfg.RequestTimeout > 0 {
+		router.Use(middleware.Timeout(cfg.RequestTimeout))
+	}
+
+	// Prometheus request metrics, scraped from /metrics outside the versioned API
+	router.Use(appMetrics.Middleware())
+	router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+
+	// Compress API and export responses; /metrics above negotiates its own encoding
+	if cfg.GzipEnabled {
+		router.Use(middleware.Gzip(cfg.GzipMinSize))
+	}
 
 	// CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:4200", "http://frontend", "*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "User-Agent", "Cache-Control", "Pragma"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// API routes
-	v1 := router.Group("/api/v1")
+	router.Use(cors.New(corsConfig(cfg)))
+
+	// Flag sample data so the dashboard can show it is not looking at real transactions
+	if demo {
+		router.Use(middleware.DemoMode())
+	}
+
+	// Swagger documentation, kept out of production so the API surface is not advertised
+	docs.SwaggerInfo.BasePath = swaggerBasePath(cfg.APIBasePath)
+	if cfg.SwaggerEnabled {
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	// The raw API docs, for tooling; outside the API group so field names are never camel-cased
+	openAPISpec, err := controllers.OpenAPISpec(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		log.Fatalf("Failed to load the API docs: %v", err)
+	}
+	router.GET(cfg.APIBasePath+"/openapi.json", openAPISpec)
+
+	// API routes, under the configured base path
+	v1 := router.Group(cfg.APIBasePath)
 	{
+		// camelCase JSON for clients that expect it; the API itself is written in snake_case
+		if cfg.JSONNaming == config.JSONNamingCamelCase {
+			v1.Use(middleware.CamelCaseJSON())
+		}
+
 		v1.GET("/health", analyticsController.HealthCheck)
-		
-		analytics := v1.Group("/analytics")
+		v1.GET("/health/migrations", analyticsController.MigrationStatus)
+		v1.GET("/ready", analyticsController.Readiness)
+		v1.GET("/version", analyticsController.Version)
+
+		// Data routes share one rate limiter and every configured authentication check
+		var guards []gin.HandlerFunc
+		if cfg.RateLimitRPS > 0 {
+			guards = append(guards, middleware.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst).Middleware())
+		}
+		if len(cfg.APIKeys) > 0 {
+			guards = append(guards, middleware.APIKeyAuth(cfg.APIKeys))
+		}
+		if cfg.JWTSecret != "" {
+			guards = append(guards, middleware.JWTAuth([]byte(cfg.JWTSecret), middleware.RoleAnalyst, middleware.RoleAdmin))
+		}
+		if len(cfg.APIKeys) == 0 && cfg.JWTSecret == "" {
+			log.Println("Warning: neither API_KEYS nor JWT_SECRET is set, data endpoints are unauthenticated")
+		}
+
+		analytics := v1.Group("/analytics", guards...)
 		{
+			analytics.GET("", analyticsController.GetAnalyticsCatalog)
 			analytics.GET("/country-revenue", analyticsController.GetCountryRevenue)
+			analytics.GET("/category-revenue", analyticsController.GetCategoryRevenue)
+			analytics.GET("/avg-order-value", analyticsController.GetAverageOrderValue)
+			analytics.GET("/order-value-percentiles", analyticsController.GetOrderValuePercentiles)
+			analytics.GET("/compare", analyticsController.ComparePeriods)
 			analytics.GET("/top-products", analyticsController.GetTopProducts)
+			analytics.GET("/top-customers", analyticsController.GetTopCustomers)
+			analytics.GET("/revenue-concentration", analyticsController.GetRevenueConcentration)
 			analytics.GET("/monthly-sales", analyticsController.GetMonthlySales)
+			if cfg.Feature(config.FeatureGrowth) {
+				analytics.GET("/growth", analyticsController.GetRevenueGrowth)
+			}
+			analytics.GET("/forecast", analyticsController.GetRevenueForecast)
+			analytics.GET("/daily-revenue", analyticsController.GetDailyRevenue)
 			analytics.GET("/top-regions", analyticsController.GetTopRegions)
+			analytics.GET("/country/:country/regions", analyticsController.GetCountryRegions)
+			analytics.GET("/region-trends", analyticsController.GetRegionTrends)
+			analytics.GET("/summary", analyticsController.GetSummary)
+			analytics.GET("/meta", analyticsController.GetDataMeta)
+			analytics.GET("/dimensions", analyticsController.GetDimensions)
 		}
-	}
 
-	return router
-}
+		// The catalog describes the analytics routes just registered from the API docs
+		if err := analyticsController.DescribeAnalytics(docs.SwaggerInfo.ReadDoc(), router.Routes()); err != nil {
+			log.Fatalf("Failed to build the analytics catalog: %v", err)
+		}
 
-func seedData(db *gorm.DB) error {
-	// Check if data already exists
-	var count int64
-	db.Model(&models.Transaction{}).Count(&count)
-	if count > 0 {
-		log.Println("Data already seeded, skipping...")
-		return nil
+		// Writes and admin routes are limited to admins when bearer tokens are in use
+		var writeGuards []gin.HandlerFunc
+		if cfg.JWTSecret != "" {
+			writeGuards = append(writeGuards, middleware.RequireRole(middleware.RoleAdmin))
+		}
+
+		transactions := v1.Group("/transactions", guards...)
+		{
+			transactions.GET("", analyticsController.ListTransactions)
+			transactions.GET("/export", analyticsController.ExportTransactions)
+			transactions.GET("/:id", analyticsController.GetTransaction)
+			transactions.POST("/batch", append(writeGuards, middleware.BodyLimit(int64(cfg.MaxBodyBytes)), analyticsController.IngestTransactions)...)
+			transactions.DELETE("/:id", append(writeGuards, analyticsController.DeleteTransaction)...)
+		}
+
+		admin := v1.Group("/admin", guards...)
+		admin.Use(writeGuards...)
+		{
+			admin.POST("/cache/flush", analyticsController.FlushCache)
+		}
 	}
 
-	log.Println("Seeding database with sample data...")
-	
-	// Create sample data seeder
-	seeder := services.NewDataSeeder(db)
-	return seeder.SeedData()
+	return router
 }
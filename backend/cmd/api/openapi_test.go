@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"abt-analytics/internal/config"
+)
+
+func TestOpenAPISpecIsServedWithoutSwaggerUI(t *testing.T) {
+	tests := []struct {
+		name      string
+		swagger   bool
+		naming    string
+		swaggerUI int
+	}{
+		{name: "swagger UI enabled", swagger: true, naming: config.JSONNamingSnakeCase, swaggerUI: http.StatusOK},
+		{name: "swagger UI disabled", naming: config.JSONNamingSnakeCase, swaggerUI: http.StatusNotFound},
+		{name: "camelCase responses", naming: config.JSONNamingCamelCase, swaggerUI: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.SwaggerEnabled = tt.swagger
+			cfg.JSONNaming = tt.naming
+			router := newTestRouter(t, cfg)
+
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/openapi.json", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var spec struct {
+				Info struct {
+					Title string `json:"title"`
+				} `json:"info"`
+				BasePath    string                     `json:"basePath"`
+				Paths       map[string]json.RawMessage `json:"paths"`
+				Definitions map[string]struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"definitions"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+				t.Fatalf("spec is not valid JSON: %v", err)
+			}
+			if spec.Info.Title != "ABT Analytics API" {
+				t.Errorf("info.title = %q, want ABT Analytics API", spec.Info.Title)
+			}
+			if spec.BasePath != cfg.APIBasePath {
+				t.Errorf("basePath = %q, want %q", spec.BasePath, cfg.APIBasePath)
+			}
+			if _, ok := spec.Paths["/analytics/country-revenue"]; !ok {
+				t.Error("spec does not describe /analytics/country-revenue")
+			}
+			// the spec documents the API as written, whatever naming responses use
+			if _, ok := spec.Definitions["models.RevenueConcentration"].Properties["total_revenue"]; !ok {
+				t.Error("spec property total_revenue was renamed")
+			}
+
+			if w := serveRequest(router, http.MethodGet, "/swagger/index.html", ""); w.Code != tt.swaggerUI {
+				t.Errorf("swagger UI status = %d, want %d", w.Code, tt.swaggerUI)
+			}
+		})
+	}
+}
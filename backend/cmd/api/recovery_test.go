@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+func TestRecoveryAnswersPanicsThroughTheRouter(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.GzipEnabled = true
+	cfg.GzipMinSize = 64
+	router := newTestRouter(t, cfg)
+	router.GET("/panic", func(c *gin.Context) {
+		// Still buffered by the gzip writer, so the 500 can replace it
+		c.Writer.WriteString("partial")
+		panic("boom")
+	})
+
+	w := serveRequest(router, http.MethodGet, "/panic", "", "Accept-Encoding", "gzip", "X-Request-ID", "req-66")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want an uncompressed error body", got)
+	}
+	var body models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q is not an ErrorResponse: %v", w.Body.String(), err)
+	}
+	if body.Code != models.ErrCodeInternal || body.RequestID != "req-66" {
+		t.Errorf("body = %+v, want %s with request ID req-66", body, models.ErrCodeInternal)
+	}
+	if strings.Contains(w.Body.String(), "boom") || strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("body %q leaks the panic", w.Body.String())
+	}
+
+	// The router keeps serving after a panic
+	if w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/health", ""); w.Code != http.StatusOK {
+		t.Errorf("health after a panic = %d, want 200", w.Code)
+	}
+}
+
+func TestRecoveryAbortsCommittedResponses(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.GzipEnabled = true
+	cfg.GzipMinSize = 64
+	router := newTestRouter(t, cfg)
+	router.GET("/panic", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv")
+		c.Writer.WriteString(strings.Repeat("row,12345\n", 20))
+		c.Writer.Flush()
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler so the connection is dropped", recovered)
+		}
+		if w.Code != http.StatusOK || strings.Contains(w.Body.String(), models.ErrCodeInternal) {
+			t.Errorf("status %d, body %q: the committed response was rewritten", w.Code, w.Body.String())
+		}
+	}()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/metrics"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/services"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testConfig loads the test profile with a SQLite database file of the test's own
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	t.Setenv("APP_ENV", config.EnvTest)
+	t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "analytics.db"))
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("test configuration: %v", err)
+	}
+	return cfg
+}
+
+// newTestRouter wires the API as main does, over cfg's database migrated and,
+// unless cfg.SeedOnStartup was cleared, seeded with the sample set
+func newTestRouter(t *testing.T, cfg *config.Config) *gin.Engine {
+	t.Helper()
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if cfg.SeedOnStartup {
+		if err := services.NewDataSeeder(db, cfg).Run(context.Background()); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	appCache := newCache(cfg)
+	service := services.NewAnalyticsService(repository.NewAnalyticsRepository(db, cfg.Rates), appCache, cfg)
+	controller := controllers.NewAnalyticsController(service, cfg, healthCheckers(service, appCache))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return setupRouter(cfg, controller, false, logger, metrics.New())
+}
+
+// serveRequest sends one request through router; headers alternate names and values
+func serveRequest(router http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouterServesEveryAnalyticsEndpointOnSQLite(t *testing.T) {
+	cfg := testConfig(t)
+	router := newTestRouter(t, cfg)
+
+	for _, path := range []string{
+		"/health",
+		"/ready",
+		"/analytics",
+		"/analytics/country-revenue",
+		"/analytics/category-revenue",
+		"/analytics/avg-order-value",
+		"/analytics/order-value-percentiles",
+		"/analytics/compare?a_from=2023-01-01&a_to=2023-03-31&b_from=2023-04-01&b_to=2023-06-30",
+		"/analytics/top-products",
+		"/analytics/top-customers",
+		"/analytics/revenue-concentration",
+		"/analytics/monthly-sales",
+		"/analytics/growth",
+		"/analytics/forecast",
+		"/analytics/daily-revenue?from=2023-01-01&to=2023-01-31",
+		"/analytics/top-regions",
+		"/analytics/country/Germany/regions",
+		"/analytics/region-trends",
+		"/analytics/summary",
+		"/analytics/meta",
+		"/analytics/dimensions",
+		"/transactions",
+		"/transactions/1",
+		"/transactions/export",
+	} {
+		t.Run(path, func(t *testing.T) {
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+path, "")
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200; body %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/country-revenue", "")
+	var rows []struct {
+		Country string `json:"country"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &rows); err != nil || len(rows) != 7 {
+		t.Errorf("country revenue = %s, want the 7 seeded countries", w.Body.String())
+	}
+}
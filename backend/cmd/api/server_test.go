@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeServer blocks in ListenAndServe until Shutdown is called or listenErr is sent
+type fakeServer struct {
+	listenErr   chan error
+	shutdownErr error
+
+	shutdownCalled   bool
+	shutdownDeadline time.Time
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{listenErr: make(chan error, 1)}
+}
+
+func (f *fakeServer) ListenAndServe() error {
+	return <-f.listenErr
+}
+
+func (f *fakeServer) Shutdown(ctx context.Context) error {
+	f.shutdownCalled = true
+	f.shutdownDeadline, _ = ctx.Deadline()
+	f.listenErr <- http.ErrServerClosed
+	return f.shutdownErr
+}
+
+func TestServeShutsDownWhenContextIsCanceled(t *testing.T) {
+	server := newFakeServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := serve(ctx, server, 5*time.Second); err != nil {
+		t.Fatalf("serve = %v, want nil", err)
+	}
+	if !server.shutdownCalled {
+		t.Fatal("Shutdown was not called")
+	}
+	if grace := server.shutdownDeadline.Sub(start); grace < 4*time.Second || grace > 6*time.Second {
+		t.Errorf("shutdown deadline %s after start, want about 5s", grace)
+	}
+}
+
+func TestServeReturnsShutdownError(t *testing.T) {
+	server := newFakeServer()
+	server.shutdownErr = context.DeadlineExceeded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := serve(ctx, server, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("serve = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServeReturnsListenError(t *testing.T) {
+	server := newFakeServer()
+	listenErr := errors.New("address already in use")
+	server.listenErr <- listenErr
+
+	if err := serve(context.Background(), server, time.Second); !errors.Is(err, listenErr) {
+		t.Errorf("serve = %v, want %v", err, listenErr)
+	}
+	if server.shutdownCalled {
+		t.Error("Shutdown was called after the server failed")
+	}
+}
+
+func TestServeTreatsServerClosedAsClean(t *testing.T) {
+	server := newFakeServer()
+	server.listenErr <- http.ErrServerClosed
+
+	if err := serve(context.Background(), server, time.Second); err != nil {
+		t.Errorf("serve = %v, want nil", err)
+	}
+}
+
+func TestNewHTTPServerTimeouts(t *testing.T) {
+	tests := []struct {
+		name              string
+		env               map[string]string
+		read, write, idle time.Duration
+	}{
+		{name: "defaults", read: 15 * time.Second, write: 60 * time.Second, idle: 120 * time.Second},
+		{
+			name:  "configured",
+			env:   map[string]string{"SERVER_READ_TIMEOUT": "5s", "SERVER_WRITE_TIMEOUT": "45s", "SERVER_IDLE_TIMEOUT": "2m"},
+			read:  5 * time.Second,
+			write: 45 * time.Second,
+			idle:  2 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			cfg := testConfig(t)
+			server := newHTTPServer(cfg, http.NotFoundHandler())
+
+			if server.Addr != ":"+cfg.Port {
+				t.Errorf("Addr = %q, want :%s", server.Addr, cfg.Port)
+			}
+			if server.ReadTimeout != tt.read || server.WriteTimeout != tt.write || server.IdleTimeout != tt.idle {
+				t.Errorf("timeouts read=%s write=%s idle=%s, want %s, %s and %s",
+					server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, tt.read, tt.write, tt.idle)
+			}
+		})
+	}
+}
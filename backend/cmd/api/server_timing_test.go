@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestServerTimingFollowsDebugFlag(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run("DEBUG_TIMING="+strconv.FormatBool(enabled), func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.DebugTiming = enabled
+			router := newTestRouter(t, cfg)
+
+			// the summary runs several queries, each reported on its own
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/analytics/summary", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			header := w.Header().Get("Server-Timing")
+			if !enabled {
+				if header != "" {
+					t.Errorf("Server-Timing = %q with the flag off", header)
+				}
+				return
+			}
+
+			metrics := strings.Split(header, ", ")
+			if len(metrics) < 2 {
+				t.Fatalf("Server-Timing = %q, want one entry per query", header)
+			}
+			for _, metric := range metrics {
+				name, dur, ok := strings.Cut(metric, ";dur=")
+				if !ok || name == "" {
+					t.Errorf("entry %q is not name;dur=ms", metric)
+					continue
+				}
+				if ms, err := strconv.ParseFloat(dur, 64); err != nil || ms < 0 {
+					t.Errorf("entry %q duration does not parse as milliseconds: %v", metric, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSwaggerFollowsConfigFlag(t *testing.T) {
+	tests := []struct {
+		enabled bool
+		want    int
+	}{
+		{enabled: true, want: http.StatusOK},
+		{enabled: false, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		name := "disabled"
+		if tt.enabled {
+			name = "enabled"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.SwaggerEnabled = tt.enabled
+			router := newTestRouter(t, cfg)
+
+			if w := serveRequest(router, http.MethodGet, "/swagger/index.html", ""); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"abt-analytics/internal/database"
+)
+
+func TestTablePrefixAppliesToMigrationsAndQueries(t *testing.T) {
+	t.Setenv("DB_TABLE_PREFIX", "abt_")
+	cfg := testConfig(t)
+	router := newTestRouter(t, cfg)
+
+	if w := serveRequest(router, http.MethodPost, cfg.APIBasePath+"/transactions/batch", "["+ingestRow+"]"); w.Code != http.StatusCreated {
+		t.Fatalf("ingest status = %d, want 201; body %s", w.Code, w.Body.String())
+	}
+	for _, path := range []string{"/analytics/country-revenue", "/analytics/top-regions", "/transactions"} {
+		w := serveRequest(router, http.MethodGet, cfg.APIBasePath+path, "")
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200; body %s", path, w.Code, w.Body.String())
+			continue
+		}
+		var rows []json.RawMessage
+		if path == "/transactions" {
+			var page struct {
+				Data []json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatalf("%s: decode: %v", path, err)
+			}
+			rows = page.Data
+		} else if err := json.Unmarshal(w.Body.Bytes(), &rows); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+		if len(rows) == 0 {
+			t.Errorf("%s returned no rows from the prefixed table", path)
+		}
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer database.Close(db)
+	var tables []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name").Scan(&tables).Error; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"abt_transactions"}; !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables = %v, want %v", tables, want)
+	}
+}
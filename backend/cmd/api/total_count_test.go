@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"abt-analytics/internal/controllers"
+)
+
+func TestTotalCountHeaderMatchesBodyTotal(t *testing.T) {
+	const origin = "https://dashboard.example.com"
+	cfg := testConfig(t)
+	cfg.CORSOrigins = []string{origin}
+	router := newTestRouter(t, cfg)
+
+	for _, path := range []string{
+		"/transactions?limit=5&offset=5",
+		"/analytics/top-products?limit=2",
+		"/analytics/top-customers?limit=3&offset=1",
+	} {
+		t.Run(path, func(t *testing.T) {
+			w := serveRequest(router, http.MethodGet, cfg.APIBasePath+path, "", "Origin", origin)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200; body %s", w.Code, w.Body.String())
+			}
+			var page struct {
+				Data  []json.RawMessage `json:"data"`
+				Total int64             `json:"total"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatalf("decode %s: %v", w.Body.String(), err)
+			}
+			if int64(len(page.Data)) >= page.Total {
+				t.Fatalf("page holds %d of %d rows, want a partial page", len(page.Data), page.Total)
+			}
+			if got := w.Header().Get(controllers.TotalCountHeader); got != strconv.FormatInt(page.Total, 10) {
+				t.Errorf("%s = %q, want the body total %d", controllers.TotalCountHeader, got, page.Total)
+			}
+			if exposed := w.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(exposed, controllers.TotalCountHeader) {
+				t.Errorf("Access-Control-Expose-Headers = %q, want it to include %s", exposed, controllers.TotalCountHeader)
+			}
+		})
+	}
+}
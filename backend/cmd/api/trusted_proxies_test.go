@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientIPTrustsConfiguredProxiesOnly(t *testing.T) {
+	const client = "203.0.113.9"
+	tests := []struct {
+		name   string
+		env    string
+		setEnv bool
+		remote string
+		want   string
+	}{
+		{name: "loopback proxy by default", remote: "127.0.0.1:40000", want: client},
+		{name: "other proxies not by default", remote: "192.0.2.1:40000", want: "192.0.2.1"},
+		{name: "configured proxy", env: "10.0.0.0/8, 192.0.2.0/24", setEnv: true, remote: "192.0.2.1:40000", want: client},
+		{name: "unlisted proxy", env: "10.0.0.0/8", setEnv: true, remote: "192.0.2.1:40000", want: "192.0.2.1"},
+		{name: "no proxies trusted", setEnv: true, remote: "127.0.0.1:40000", want: "127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				t.Setenv("TRUSTED_PROXIES", tt.env)
+			}
+			cfg := testConfig(t)
+			router := newTestRouter(t, cfg)
+			router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", client)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"testing"
+)
+
+func TestVersionWithoutLdflags(t *testing.T) {
+	cfg := testConfig(t)
+	router := newTestRouter(t, cfg)
+
+	w := serveRequest(router, http.MethodGet, cfg.APIBasePath+"/version", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", w.Code, w.Body.String())
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &fields); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	// test binaries carry no ldflags and usually no VCS stamp, so the build
+	// fields fall back to a placeholder rather than going missing
+	for _, name := range []string{"commit", "build_time", "go_version"} {
+		if fields[name] == "" {
+			t.Errorf("%s is missing or empty in %s", name, w.Body.String())
+		}
+	}
+	if fields["go_version"] != runtime.Version() {
+		t.Errorf("go_version = %q, want %q", fields["go_version"], runtime.Version())
+	}
+}
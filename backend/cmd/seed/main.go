@@ -0,0 +1,48 @@
+// Command seed connects to the configured database, runs migrations, seeds it
+// and exits without starting the HTTP server. It reads the same environment as
+// the API, including RESEED, SEED_FILE, SEED_STRICT and SEED_COUNT.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/services"
+)
+
+func main() {
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		stop()
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	log.Println("🌱 Database seeded")
+}
+
+// run migrates and seeds the database, closing the connection before returning
+func run(ctx context.Context, cfg *config.Config) error {
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+
+	return services.NewDataSeeder(db, cfg).Run(ctx)
+}
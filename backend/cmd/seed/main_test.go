@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+)
+
+func TestRunSeedsSQLite(t *testing.T) {
+	t.Setenv("APP_ENV", config.EnvTest)
+	t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "analytics.db"))
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("test configuration: %v", err)
+	}
+
+	// a second run upserts the same orders instead of adding to them
+	var counts []int64
+	for i := 0; i < 2; i++ {
+		if err := run(context.Background(), cfg); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+
+		db, err := database.Connect(cfg)
+		if err != nil {
+			t.Fatalf("reconnect: %v", err)
+		}
+		var count int64
+		err = db.Model(&models.Transaction{}).Count(&count).Error
+		database.Close(db)
+		if err != nil {
+			t.Fatalf("count transactions: %v", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if counts[0] == 0 {
+		t.Fatal("run left the database empty")
+	}
+	if counts[1] != counts[0] {
+		t.Errorf("second run changed the row count from %d to %d", counts[0], counts[1])
+	}
+}
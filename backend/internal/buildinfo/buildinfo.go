@@ -0,0 +1,66 @@
+// Package buildinfo reports which build of the API is running. Commit and
+// BuildTime are meant to be injected at link time:
+//
+//	go build -ldflags "-X abt-analytics/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X abt-analytics/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"abt-analytics/internal/models"
+)
+
+// unknown is reported for any field neither ldflags nor the embedded build info provide
+const unknown = "unknown"
+
+var (
+	// Commit is the git revision the binary was built from
+	Commit string
+	// BuildTime is when the binary was built, preferably RFC3339 in UTC
+	BuildTime string
+)
+
+// Get returns the build details, using the VCS stamp Go embeds in the binary
+// for any value that was not set through ldflags
+func Get() models.VersionResponse {
+	version := models.VersionResponse{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, revisionTime string
+		modified := false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.time":
+				revisionTime = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+
+		if version.Commit == "" && revision != "" {
+			version.Commit = revision
+			if modified {
+				version.Commit += "-dirty"
+			}
+		}
+		if version.BuildTime == "" {
+			version.BuildTime = revisionTime
+		}
+	}
+
+	if version.Commit == "" {
+		version.Commit = unknown
+	}
+	if version.BuildTime == "" {
+		version.BuildTime = unknown
+	}
+	return version
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores serialized values under string keys.
+// Get reports a miss with found == false rather than an error.
+// Clear removes every entry whose key starts with prefix, all of them for an
+// empty prefix, and returns how many live entries it evicted.
+type Cache interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Clear(ctx context.Context, prefix string) (int, error)
+}
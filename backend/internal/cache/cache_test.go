@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var ctx = context.Background()
+
+// testCache runs the behaviour every Cache implementation must provide against
+// the empty cache returned by newCache
+func testCache(t *testing.T, newCache func(t *testing.T) Cache) {
+	t.Run("miss", func(t *testing.T) {
+		c := newCache(t)
+		if value, found, err := c.Get(ctx, "absent"); err != nil || found || value != nil {
+			t.Errorf("Get = %q, %v, %v; want a miss", value, found, err)
+		}
+	})
+
+	t.Run("set then get", func(t *testing.T) {
+		c := newCache(t)
+		if err := c.Set(ctx, "key", []byte(`[{"country":"US"}]`), time.Minute); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+		value, found, err := c.Get(ctx, "key")
+		if err != nil || !found || string(value) != `[{"country":"US"}]` {
+			t.Errorf("Get = %q, %v, %v; want the stored value", value, found, err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c := newCache(t)
+		c.Set(ctx, "key", []byte("value"), time.Minute)
+		if err := c.Delete(ctx, "key"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, found, _ := c.Get(ctx, "key"); found {
+			t.Error("deleted key is still found")
+		}
+		if err := c.Delete(ctx, "key"); err != nil {
+			t.Errorf("deleting a missing key: %v", err)
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		c := newCache(t)
+		c.Set(ctx, "key", []byte("value"), 50*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		if _, found, _ := c.Get(ctx, "key"); found {
+			t.Error("expired key is still found")
+		}
+	})
+
+	t.Run("clear prefix", func(t *testing.T) {
+		c := newCache(t)
+		for _, key := range []string{"country-revenue:a", "country-revenue:b", "top-products:a"} {
+			c.Set(ctx, key, []byte("value"), time.Minute)
+		}
+		evicted, err := c.Clear(ctx, "country-revenue:")
+		if err != nil || evicted != 2 {
+			t.Errorf("Clear = %d, %v; want 2 evicted", evicted, err)
+		}
+		if _, found, _ := c.Get(ctx, "country-revenue:a"); found {
+			t.Error("cleared key is still found")
+		}
+		if _, found, _ := c.Get(ctx, "top-products:a"); !found {
+			t.Error("key outside the prefix was cleared")
+		}
+
+		if evicted, err := c.Clear(ctx, ""); err != nil || evicted != 1 {
+			t.Errorf("Clear all = %d, %v; want 1 evicted", evicted, err)
+		}
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCache(t, func(t *testing.T) Cache { return NewMemoryCache() })
+}
+
+func TestMemoryCacheStoresACopy(t *testing.T) {
+	c := NewMemoryCache()
+	value := []byte("value")
+	c.Set(ctx, "key", value, time.Minute)
+	value[0] = 'X'
+
+	if got, _, _ := c.Get(ctx, "key"); string(got) != "value" {
+		t.Errorf("Get = %q after the caller reused its buffer, want value", got)
+	}
+}
+
+func TestMemoryCacheClearSkipsExpiredInCount(t *testing.T) {
+	c := NewMemoryCache()
+	c.Set(ctx, "stale", []byte("value"), time.Nanosecond)
+	c.Set(ctx, "live", []byte("value"), time.Minute)
+	time.Sleep(time.Millisecond)
+
+	if evicted, _ := c.Clear(ctx, ""); evicted != 1 {
+		t.Errorf("Clear = %d, want only the live entry counted", evicted)
+	}
+}
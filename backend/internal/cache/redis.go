@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var _ Cache = (*RedisCache)(nil)
+
+// RedisCache is a Cache shared between API replicas through Redis.
+// Keys are namespaced with a prefix so the instance can be shared with other apps.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache creates a cache storing keys under prefix in the given Redis client
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+// Get returns the value stored under key
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores value under key, letting Redis expire it after ttl
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete removes key from the cache
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+// clearBatchSize is how many keys Clear asks SCAN for per round trip
+const clearBatchSize = 500
+
+// Clear scans for the keys starting with prefix under this cache's namespace and
+// deletes them batch by batch. Keys written while the scan runs may survive it.
+func (c *RedisCache) Clear(ctx context.Context, prefix string) (int, error) {
+	pattern := globEscaper.Replace(c.prefix+prefix) + "*"
+	evicted := 0
+
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, pattern, clearBatchSize).Result()
+		if err != nil {
+			return evicted, err
+		}
+		if len(keys) > 0 {
+			deleted, err := c.client.Del(ctx, keys...).Result()
+			evicted += int(deleted)
+			if err != nil {
+				return evicted, err
+			}
+		}
+		if next == 0 {
+			return evicted, nil
+		}
+		cursor = next
+	}
+}
+
+// globEscaper quotes the characters SCAN MATCH patterns treat specially
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// Ping checks that Redis is reachable
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
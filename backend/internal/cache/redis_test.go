@@ -0,0 +1,31 @@
+//go:build integration
+
+package cache
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// TestRedisCache runs the Cache conformance tests against the Redis at
+// REDIS_ADDR, each in a namespace of its own: go test -tags integration ./internal/cache
+func TestRedisCache(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("Redis unavailable at %s: %v", addr, err)
+	}
+
+	testCache(t, func(t *testing.T) Cache {
+		c := NewRedisCache(client, "abt-analytics-test:"+uuid.NewString()+":")
+		t.Cleanup(func() { c.Clear(ctx, "") })
+		return c
+	})
+}
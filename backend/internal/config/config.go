@@ -0,0 +1,478 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Supported cache backends
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+)
+
+// Supported database drivers
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Key naming conventions of JSON bodies selectable with JSON_NAMING
+const (
+	JSONNamingSnakeCase = "snake_case"
+	JSONNamingCamelCase = "camelCase"
+)
+
+// Decimal separators of CSV and TSV exports selectable with CSV_DECIMAL
+const (
+	DecimalDot   = "dot"
+	DecimalComma = "comma"
+)
+
+// Levels of the SQL query log selectable with DB_LOG_LEVEL
+const (
+	DBLogSilent = "silent"
+	DBLogError  = "error"
+	DBLogWarn   = "warn"
+	DBLogInfo   = "info"
+)
+
+// Config holds the application configuration
+type Config struct {
+	// Env is the APP_ENV profile the defaults were taken from
+	Env string
+
+	Port       string
+	Driver     string
+	DBHost     string
+	DBPort     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+	DBSSLMode  string
+	// DBTablePrefix is prepended to every table name, so abt_ maps transactions to
+	// abt_transactions, for databases shared with other applications
+	DBTablePrefix string
+	// DBReplicas lists the DSNs of read replicas of the primary, using the same
+	// driver; reads are spread over them while writes stay on the primary
+	DBReplicas []string
+
+	// Connection pool limits applied to the underlying sql.DB
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+
+	// DBLogLevel is the level of the SQL query log: silent, error, warn or info.
+	// At warn only slow queries and errors are logged; info logs every query.
+	DBLogLevel string
+	// DBSlowThreshold is how long a query may run before it is logged as slow;
+	// zero disables slow-query logging
+	DBSlowThreshold time.Duration
+
+	// DBConnectAttempts and DBConnectBackoff control retries of the initial
+	// connection; the delay doubles after every failed attempt
+	DBConnectAttempts int
+	DBConnectBackoff  time.Duration
+	// DBRequired makes startup fail instead of falling back to demo mode
+	DBRequired bool
+	// DemoMode serves the built-in sample data without connecting to a database
+	DemoMode bool
+
+	// MaxPageLimit caps the page size clients may request on paginated endpoints
+	// without a maximum of their own in PageLimits
+	MaxPageLimit int
+	// PageLimits overrides the page sizes of individual paginated endpoints;
+	// read them with PageLimitsFor
+	PageLimits map[string]PageLimits
+	// MaxQueryDays caps the from/to window of endpoints scanning individual
+	// transactions and makes both bounds required there; zero disables the cap
+	MaxQueryDays int
+	// DefaultRangeDays is the trailing window, in UTC days, aggregates cover when a
+	// request gives neither from nor to, unless it asks for all=true; zero leaves
+	// such requests covering the full history
+	DefaultRangeDays int
+	// MaxBodyBytes caps the size of the request body accepted by ingestion endpoints
+	MaxBodyBytes int
+
+	// ShutdownTimeout is how long in-flight requests get to finish on shutdown
+	ShutdownTimeout time.Duration
+	// RequestTimeout is the deadline given to each request; zero disables it
+	RequestTimeout time.Duration
+	// ExportTimeout bounds how long a transaction export may keep streaming rows
+	// from the database; REQUEST_TIMEOUT still applies on top. Zero disables it.
+	ExportTimeout time.Duration
+
+	// ServerReadTimeout bounds reading a request, headers and body included, so
+	// slow clients cannot hold connections open. Defaults to 15s.
+	ServerReadTimeout time.Duration
+	// ServerWriteTimeout bounds the time from the end of the request headers to the
+	// end of the response. Defaults to 60s, leaving room past RequestTimeout to
+	// answer a timed-out request with 504.
+	ServerWriteTimeout time.Duration
+	// ServerIdleTimeout is how long a keep-alive connection may wait for its next
+	// request. Defaults to 120s.
+	ServerIdleTimeout time.Duration
+
+	// APIBasePath is the prefix the API routes are mounted under, without a
+	// trailing slash, so "/" mounts them at the root. Defaults to /api/v1.
+	APIBasePath string
+
+	// LogFormat selects the log output format: text or json
+	LogFormat string
+	LogLevel  string
+	// LogSampleRate is the share, from 0 to 1, of 2xx responses that get a request
+	// log line; other statuses are always logged. Defaults to 1.
+	LogSampleRate float64
+
+	// DebugTiming reports the duration of each repository call made for a request
+	// in a Server-Timing response header; keep it off in production
+	DebugTiming bool
+
+	// RequestIDHeader is the header used to read and echo request IDs
+	RequestIDHeader string
+
+	// CORSOrigins lists the browser origins allowed to call the API; "*" allows any origin without credentials
+	CORSOrigins []string
+	// CORSMethods lists the HTTP methods allowed in cross-origin requests
+	CORSMethods []string
+	// CORSHeaders lists the request headers cross-origin requests may send, on top
+	// of the API key, API version and request ID headers the API always allows
+	CORSHeaders []string
+	// CORSMaxAge is how long browsers may cache the answer to a preflight request
+	CORSMaxAge time.Duration
+	// JSONNaming is the key convention of JSON request and response bodies.
+	// The API is written in snake_case; camelCase renames keys on the way in and out.
+	JSONNaming string
+	// TrustedProxies lists the proxy addresses or CIDR ranges whose X-Forwarded-For
+	// header is believed when resolving client IPs; empty trusts no proxy
+	TrustedProxies []string
+
+	// GzipEnabled compresses responses of at least GzipMinSize bytes for clients accepting gzip
+	GzipEnabled bool
+	GzipMinSize int
+
+	// CSVDecimal is the decimal separator of CSV and TSV exports, dot or comma,
+	// unless a request picks one with ?decimal=. Comma exports of CSV separate
+	// fields with semicolons, as spreadsheets in comma-decimal locales expect.
+	CSVDecimal string
+
+	// SwaggerEnabled serves the API docs under /swagger; it defaults to off in the
+	// prod and test profiles and when GIN_MODE=release
+	SwaggerEnabled bool
+
+	// OTLPEndpoint is the host:port of an OTLP/HTTP trace collector; empty disables tracing
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+
+	// CacheTTL is how long analytics aggregates are cached; zero disables caching
+	CacheTTL time.Duration
+	// PrecomputeInterval is how often the heaviest default aggregates are recomputed
+	// in the background and written to the cache, so dashboard requests find them
+	// warm; zero disables precomputing
+	PrecomputeInterval time.Duration
+	// CacheBackend selects where aggregates are cached: memory or redis
+	CacheBackend  string
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+
+	// APIKeys lists the keys accepted on the analytics routes; empty disables the check
+	APIKeys []string
+
+	// JWTSecret is the HMAC secret for bearer tokens; empty disables JWT authentication
+	JWTSecret string
+
+	// RateLimitRPS is the sustained per-client request rate; zero disables rate limiting
+	RateLimitRPS   float64
+	RateLimitBurst int
+
+	// Rates maps upper-case currency codes to multipliers from the base currency
+	Rates map[string]float64
+	// BaseCurrency is the code revenue is summed and reported in. Transactions
+	// recorded in other currencies are converted into it with Rates.
+	BaseCurrency string
+
+	// Features is the set of enabled feature flags; check it with Feature
+	Features map[string]bool
+
+	// SeedOnStartup seeds the database when the API server starts; disable it
+	// when seeding is run separately with cmd/seed
+	SeedOnStartup bool
+	// Reseed wipes the transactions table before seeding it again
+	Reseed bool
+	// SeedFile is an optional CSV of transactions to seed instead of the built-in sample set
+	SeedFile string
+	// SeedStrict aborts seeding on the first malformed CSV row instead of skipping it
+	SeedStrict bool
+	// SeedCount replaces the built-in sample set with that many generated
+	// transactions, for load testing; zero keeps the sample set
+	SeedCount int
+
+	// envProblems lists the environment variables Load could not parse and
+	// replaced with their defaults, for Validate to report
+	envProblems []string
+}
+
+// Load reads the configuration from environment variables, falling back to the
+// defaults of the APP_ENV profile (dev when unset)
+func Load() *Config {
+	env := getEnv("APP_ENV", EnvDev)
+	defaults := profileFor(env)
+	driver := getEnv("DB_DRIVER", defaults.driver)
+	p := &envParser{}
+
+	cfg := &Config{
+		Env: env,
+
+		Port:       getEnv("PORT", "8080"),
+		Driver:     driver,
+		DBHost:     getEnv("DB_HOST", "localhost"),
+		DBPort:     getEnv("DB_PORT", defaultDBPort(driver)),
+		DBUser:     getEnv("DB_USER", "abt_user"),
+		DBPassword: getEnv("DB_PASSWORD", "abt_password"),
+		DBName:     getEnv("DB_NAME", defaults.dbName),
+		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		DBTablePrefix: getEnv("DB_TABLE_PREFIX", ""),
+		DBReplicas:    getEnvList("DB_REPLICAS", nil),
+
+		DBMaxOpenConns:    p.getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:    p.getEnvInt("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetime: p.getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+		DBLogLevel:      getEnv("DB_LOG_LEVEL", DBLogWarn),
+		DBSlowThreshold: p.getEnvDuration("DB_SLOW_THRESHOLD", 200*time.Millisecond),
+
+		DBConnectAttempts: p.getEnvInt("DB_CONNECT_ATTEMPTS", 5),
+		DBConnectBackoff:  p.getEnvDuration("DB_CONNECT_BACKOFF", time.Second),
+		DBRequired:        p.getEnvBool("DB_REQUIRED", defaults.dbRequired),
+		DemoMode:          p.getEnvBool("DEMO_MODE", false),
+
+		MaxPageLimit: p.getEnvInt("MAX_PAGE_LIMIT", 100),
+		PageLimits:   parsePageLimits(getEnvList("PAGE_LIMITS", nil)),
+		MaxQueryDays: p.getEnvInt("MAX_QUERY_DAYS", 0),
+		MaxBodyBytes: p.getEnvInt("MAX_BODY_BYTES", 10<<20),
+
+		DefaultRangeDays: p.getEnvInt("DEFAULT_RANGE_DAYS", 0),
+
+		ShutdownTimeout: p.getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		RequestTimeout:  p.getEnvDuration("REQUEST_TIMEOUT", 30*time.Second),
+		ExportTimeout:   p.getEnvDuration("EXPORT_TIMEOUT", 30*time.Second),
+
+		ServerReadTimeout:  p.getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ServerWriteTimeout: p.getEnvDuration("SERVER_WRITE_TIMEOUT", 60*time.Second),
+		ServerIdleTimeout:  p.getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+
+		APIBasePath: strings.TrimRight(getEnv("API_BASE_PATH", "/api/v1"), "/"),
+
+		LogFormat:     getEnv("LOG_FORMAT", defaults.logFormat),
+		LogLevel:      getEnv("LOG_LEVEL", defaults.logLevel),
+		LogSampleRate: p.getEnvFloat("LOG_SAMPLE_RATE", 1),
+
+		DebugTiming: p.getEnvBool("DEBUG_TIMING", false),
+
+		RequestIDHeader: getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+
+		CORSOrigins:    getEnvList("CORS_ORIGINS", []string{"http://localhost:4200"}),
+		CORSMethods:    upperAll(getEnvList("CORS_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})),
+		CORSHeaders:    getEnvList("CORS_HEADERS", []string{"Origin", "Content-Type", "Authorization", "Accept", "User-Agent", "Cache-Control", "Pragma"}),
+		CORSMaxAge:     p.getEnvDuration("CORS_MAX_AGE", 12*time.Hour),
+		JSONNaming:     getEnv("JSON_NAMING", JSONNamingSnakeCase),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES", []string{"127.0.0.1/32", "::1/128"}),
+
+		GzipEnabled: p.getEnvBool("GZIP_ENABLED", true),
+		GzipMinSize: p.getEnvInt("GZIP_MIN_SIZE", 1024),
+
+		CSVDecimal: getEnv("CSV_DECIMAL", DecimalDot),
+
+		SwaggerEnabled: p.getEnvBool("SWAGGER_ENABLED", defaults.swaggerEnabled && getEnv("GIN_MODE", "debug") != "release"),
+
+		OTLPEndpoint: getEnv("OTLP_ENDPOINT", ""),
+		OTLPInsecure: p.getEnvBool("OTLP_INSECURE", false),
+		ServiceName:  getEnv("OTEL_SERVICE_NAME", "abt-analytics"),
+
+		CacheTTL:           p.getEnvDuration("CACHE_TTL", 5*time.Minute),
+		PrecomputeInterval: p.getEnvDuration("PRECOMPUTE_INTERVAL", 0),
+		CacheBackend:       getEnv("CACHE_BACKEND", CacheBackendMemory),
+		RedisAddr:          getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:      getEnv("REDIS_PASSWORD", ""),
+		RedisDB:            p.getEnvInt("REDIS_DB", 0),
+
+		APIKeys: getEnvList("API_KEYS", nil),
+
+		JWTSecret: getEnv("JWT_SECRET", ""),
+
+		RateLimitRPS:   p.getEnvFloat("RATE_LIMIT_RPS", defaults.rateLimitRPS),
+		RateLimitBurst: p.getEnvInt("RATE_LIMIT_BURST", 20),
+
+		Rates:        parseRates(getEnv("RATES", "USD:1,EUR:0.92,GBP:0.79")),
+		BaseCurrency: strings.ToUpper(getEnv("BASE_CURRENCY", "USD")),
+
+		Features: parseFeatures(getEnvList("FEATURES", defaults.features)),
+
+		SeedOnStartup: p.getEnvBool("SEED_ON_STARTUP", defaults.seedOnStartup),
+		Reseed:        p.getEnvBool("RESEED", false),
+		SeedFile:      getEnv("SEED_FILE", ""),
+		SeedStrict:    p.getEnvBool("SEED_STRICT", false),
+		SeedCount:     p.getEnvInt("SEED_COUNT", 0),
+	}
+	cfg.envProblems = p.problems
+	return cfg
+}
+
+// GetDSN returns the data source name for the configured driver.
+// Credentials are escaped so passwords may contain any character.
+func (c *Config) GetDSN() string {
+	switch c.Driver {
+	case DriverPostgres:
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.DBUser, c.DBPassword),
+			Host:     net.JoinHostPort(c.DBHost, c.DBPort),
+			Path:     "/" + c.DBName,
+			RawQuery: url.Values{"sslmode": {c.DBSSLMode}}.Encode(),
+		}
+		return dsn.String()
+	case DriverSQLite:
+		// DB_NAME is the database file; ":memory:" keeps a database shared by every pooled connection
+		if c.DBName == ":memory:" {
+			return "file::memory:?cache=shared"
+		}
+		return c.DBName
+	default:
+		dsn := mysql.NewConfig()
+		dsn.User = c.DBUser
+		dsn.Passwd = c.DBPassword
+		dsn.Net = "tcp"
+		dsn.Addr = net.JoinHostPort(c.DBHost, c.DBPort)
+		dsn.DBName = c.DBName
+		dsn.ParseTime = true
+		dsn.Loc = time.Local
+		dsn.Params = map[string]string{"charset": "utf8mb4"}
+		return dsn.FormatDSN()
+	}
+}
+
+func defaultDBPort(driver string) string {
+	switch driver {
+	case DriverPostgres:
+		return "5432"
+	case DriverSQLite:
+		return ""
+	default:
+		return "3306"
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// envParser reads typed environment variables. A value that does not parse is
+// recorded as a problem and replaced with the default, so Validate can report
+// every bad variable at once instead of the typo going unnoticed.
+type envParser struct {
+	problems []string
+}
+
+func (p *envParser) invalid(key, value, kind string) {
+	p.problems = append(p.problems, fmt.Sprintf("%s %q is not a valid %s", key, value, kind))
+}
+
+func (p *envParser) getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		parsed, err := strconv.Atoi(value)
+		if err == nil {
+			return parsed
+		}
+		p.invalid(key, value, "integer")
+	}
+	return defaultValue
+}
+
+func (p *envParser) getEnvBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		parsed, err := strconv.ParseBool(value)
+		if err == nil {
+			return parsed
+		}
+		p.invalid(key, value, "boolean")
+	}
+	return defaultValue
+}
+
+func (p *envParser) getEnvFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return parsed
+		}
+		p.invalid(key, value, "number")
+	}
+	return defaultValue
+}
+
+func (p *envParser) getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		parsed, err := time.ParseDuration(value)
+		if err == nil {
+			return parsed
+		}
+		p.invalid(key, value, "duration such as 30s or 5m")
+	}
+	return defaultValue
+}
+
+// upperAll upper-cases every item of values in place
+func upperAll(values []string) []string {
+	for i, value := range values {
+		values[i] = strings.ToUpper(value)
+	}
+	return values
+}
+
+// getEnvList reads a comma-separated list, trimming whitespace and dropping empty items
+func getEnvList(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseRates parses "CODE:rate" pairs such as "USD:1,EUR:0.92", skipping malformed
+// or non-positive entries
+func parseRates(value string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		rates[strings.ToUpper(strings.TrimSpace(parts[0]))] = rate
+	}
+	return rates
+}
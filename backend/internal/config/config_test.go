@@ -0,0 +1,132 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// unsetenv clears keys for the duration of the test, restoring their values after it
+func unsetenv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			os.Unsetenv(key)
+			t.Cleanup(func() { os.Setenv(key, value) })
+		}
+	}
+}
+
+func TestLoadDefaultsToMySQL(t *testing.T) {
+	unsetenv(t, "APP_ENV", "DB_DRIVER", "DB_PORT")
+
+	cfg := Load()
+	if cfg.Driver != DriverMySQL {
+		t.Errorf("Driver = %q, want %q", cfg.Driver, DriverMySQL)
+	}
+	if cfg.DBPort != "3306" {
+		t.Errorf("DBPort = %q, want 3306", cfg.DBPort)
+	}
+}
+
+func TestLoadDefaultPortFollowsDriver(t *testing.T) {
+	unsetenv(t, "DB_PORT")
+	t.Setenv("DB_DRIVER", DriverPostgres)
+
+	if port := Load().DBPort; port != "5432" {
+		t.Errorf("DBPort = %q, want 5432", port)
+	}
+}
+
+const specialPassword = `p@ss:w/rd?#%&=+ "'`
+
+func TestGetDSNMySQL(t *testing.T) {
+	cfg := &Config{Driver: DriverMySQL, DBHost: "db.internal", DBPort: "3306", DBUser: "abt", DBPassword: specialPassword, DBName: "abt_analytics"}
+
+	parsed, err := mysql.ParseDSN(cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("ParseDSN(%q): %v", cfg.GetDSN(), err)
+	}
+	if parsed.User != "abt" || parsed.Passwd != specialPassword {
+		t.Errorf("credentials = %q/%q, want abt/%q", parsed.User, parsed.Passwd, specialPassword)
+	}
+	if parsed.Addr != "db.internal:3306" || parsed.Net != "tcp" {
+		t.Errorf("address = %s(%s), want tcp(db.internal:3306)", parsed.Net, parsed.Addr)
+	}
+	if parsed.DBName != "abt_analytics" {
+		t.Errorf("DBName = %q, want abt_analytics", parsed.DBName)
+	}
+	if !parsed.ParseTime {
+		t.Error("parseTime is off, want on so DATETIME columns scan as time.Time")
+	}
+}
+
+func TestGetDSNPostgres(t *testing.T) {
+	cfg := &Config{Driver: DriverPostgres, DBHost: "db.internal", DBPort: "5432", DBUser: "abt", DBPassword: specialPassword, DBName: "abt_analytics", DBSSLMode: "require"}
+
+	parsed, err := url.Parse(cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", cfg.GetDSN(), err)
+	}
+	password, _ := parsed.User.Password()
+	if parsed.User.Username() != "abt" || password != specialPassword {
+		t.Errorf("credentials = %q/%q, want abt/%q", parsed.User.Username(), password, specialPassword)
+	}
+	if parsed.Scheme != "postgres" || parsed.Host != "db.internal:5432" || parsed.Path != "/abt_analytics" {
+		t.Errorf("DSN = %s, want postgres://db.internal:5432/abt_analytics", parsed.Redacted())
+	}
+	if mode := parsed.Query().Get("sslmode"); mode != "require" {
+		t.Errorf("sslmode = %q, want require", mode)
+	}
+}
+
+func TestGetDSNIPv6Host(t *testing.T) {
+	cfg := &Config{Driver: DriverPostgres, DBHost: "::1", DBPort: "5432", DBUser: "abt", DBName: "abt"}
+
+	parsed, err := url.Parse(cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", cfg.GetDSN(), err)
+	}
+	if parsed.Hostname() != "::1" || parsed.Port() != "5432" {
+		t.Errorf("host = %q port %q, want ::1 port 5432", parsed.Hostname(), parsed.Port())
+	}
+}
+
+func TestGetDSNSQLite(t *testing.T) {
+	tests := []struct {
+		name, dbName, want string
+	}{
+		{"file", "abt.db", "abt.db"},
+		{"shared in-memory database", ":memory:", "file::memory:?cache=shared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Driver: DriverSQLite, DBName: tt.dbName, DBPassword: specialPassword}
+			if got := cfg.GetDSN(); got != tt.want {
+				t.Errorf("GetDSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPoolSettings(t *testing.T) {
+	unsetenv(t, "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME")
+
+	cfg := Load()
+	if cfg.DBMaxOpenConns != 25 || cfg.DBMaxIdleConns != 10 || cfg.DBConnMaxLifetime != 5*time.Minute {
+		t.Errorf("defaults = %d open, %d idle, %s lifetime; want 25, 10, 5m",
+			cfg.DBMaxOpenConns, cfg.DBMaxIdleConns, cfg.DBConnMaxLifetime)
+	}
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "40")
+	t.Setenv("DB_MAX_IDLE_CONNS", "4")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "90s")
+	cfg = Load()
+	if cfg.DBMaxOpenConns != 40 || cfg.DBMaxIdleConns != 4 || cfg.DBConnMaxLifetime != 90*time.Second {
+		t.Errorf("from the environment = %d open, %d idle, %s lifetime; want 40, 4, 1m30s",
+			cfg.DBMaxOpenConns, cfg.DBMaxIdleConns, cfg.DBConnMaxLifetime)
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Feature flags accepted in FEATURES, each gating an experimental endpoint
+const (
+	// FeatureGrowth serves GET /analytics/growth
+	FeatureGrowth = "growth"
+)
+
+var knownFeatures = []string{FeatureGrowth}
+
+// Feature reports whether the named feature flag is enabled
+func (c *Config) Feature(name string) bool {
+	return c.Features[name]
+}
+
+// parseFeatures turns flag names into a set, ignoring case
+func parseFeatures(names []string) map[string]bool {
+	features := make(map[string]bool, len(names))
+	for _, name := range names {
+		features[strings.ToLower(name)] = true
+	}
+	return features
+}
+
+// unknownFeatures returns the enabled flags that no code checks, in sorted order
+func unknownFeatures(features map[string]bool) []string {
+	var unknown []string
+	for name := range features {
+		known := false
+		for _, candidate := range knownFeatures {
+			known = known || name == candidate
+		}
+		if !known {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
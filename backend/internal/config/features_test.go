@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestLoadFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		set      bool
+		profile  string
+		growth   bool
+		problems int
+	}{
+		{name: "dev profile default", profile: EnvDev, growth: true},
+		{name: "prod profile default", profile: EnvProd},
+		{name: "enabled ignoring case and spaces", env: " Growth ,", set: true, profile: EnvProd, growth: true},
+		{name: "explicitly empty", env: "", set: true, profile: EnvDev},
+		{name: "unknown flag", env: "growth,teleport", set: true, profile: EnvDev, growth: true, problems: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetenv(t, profileVars...)
+			t.Setenv("APP_ENV", tt.profile)
+			if tt.set {
+				t.Setenv("FEATURES", tt.env)
+			}
+
+			cfg := Load()
+			if got := cfg.Feature(FeatureGrowth); got != tt.growth {
+				t.Errorf("Feature(%q) = %v, want %v", FeatureGrowth, got, tt.growth)
+			}
+			if cfg.Feature("teleport") != (tt.problems > 0) {
+				t.Errorf("Feature(teleport) = %v", cfg.Feature("teleport"))
+			}
+			if got := problemsMentioning(t, cfg.Validate(), "FEATURES"); len(got) != tt.problems {
+				t.Errorf("FEATURES problems = %v, want %d", got, tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Paginated endpoints whose page sizes PAGE_LIMITS can set
+const (
+	PageTopProducts  = "top-products"
+	PageTopCustomers = "top-customers"
+	PageTransactions = "transactions"
+)
+
+// defaultPageSizes are the page sizes paginated endpoints use when the request
+// sets no limit and PAGE_LIMITS has no entry for them
+var defaultPageSizes = map[string]int{
+	PageTopProducts:  10,
+	PageTopCustomers: 10,
+	PageTransactions: 50,
+}
+
+// PageLimits are the page sizes of one paginated endpoint: the one used when a
+// request sets no limit and the largest one a request may ask for
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// PageLimitsFor returns the page sizes of the named paginated endpoint. Without a
+// PAGE_LIMITS entry, or where the entry leaves the maximum out, the maximum is
+// MaxPageLimit.
+func (c *Config) PageLimitsFor(endpoint string) PageLimits {
+	limits, ok := c.PageLimits[endpoint]
+	if !ok {
+		limits.Default = defaultPageSizes[endpoint]
+		if limits.Default > c.MaxPageLimit {
+			limits.Default = c.MaxPageLimit
+		}
+	}
+	if limits.Max == 0 {
+		limits.Max = c.MaxPageLimit
+	}
+	return limits
+}
+
+// parsePageLimits parses "endpoint:default[:max]" entries such as
+// "transactions:50:500". Malformed entries are kept with a zero default so
+// validation reports them instead of them being dropped.
+func parsePageLimits(entries []string) map[string]PageLimits {
+	limits := make(map[string]PageLimits, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		var sizes PageLimits
+		if len(parts) == 2 || len(parts) == 3 {
+			sizes.Default, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+		}
+		if len(parts) == 3 {
+			max, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || max < 1 {
+				sizes.Default = 0
+			}
+			sizes.Max = max
+		}
+		limits[strings.ToLower(strings.TrimSpace(parts[0]))] = sizes
+	}
+	return limits
+}
+
+// unknownPageLimits returns the PAGE_LIMITS endpoints that are not paginated, in sorted order
+func unknownPageLimits(limits map[string]PageLimits) []string {
+	var unknown []string
+	for name := range limits {
+		if _, ok := defaultPageSizes[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// paginatedEndpoints returns the names PAGE_LIMITS accepts, in sorted order
+func paginatedEndpoints() []string {
+	names := make([]string, 0, len(defaultPageSizes))
+	for name := range defaultPageSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPageLimitsFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		maxLimit  string
+		endpoint  string
+		want      PageLimits
+		wantError string
+	}{
+		{name: "top products default", endpoint: PageTopProducts, want: PageLimits{Default: 10, Max: 100}},
+		{name: "top customers default", endpoint: PageTopCustomers, want: PageLimits{Default: 10, Max: 100}},
+		{name: "transactions default", endpoint: PageTransactions, want: PageLimits{Default: 50, Max: 100}},
+		{name: "defaults never exceed MAX_PAGE_LIMIT", maxLimit: "20", endpoint: PageTransactions, want: PageLimits{Default: 20, Max: 20}},
+		{name: "overridden default", env: "transactions:25", endpoint: PageTransactions, want: PageLimits{Default: 25, Max: 100}},
+		{name: "overridden default and max", env: "Transactions:200:500", endpoint: PageTransactions, want: PageLimits{Default: 200, Max: 500}},
+		{name: "other endpoints keep theirs", env: "transactions:200:500", endpoint: PageTopProducts, want: PageLimits{Default: 10, Max: 100}},
+		{name: "default above the max", env: "top-products:50:20", endpoint: PageTopProducts, wantError: "PAGE_LIMITS entry for top-products"},
+		{name: "malformed entry", env: "top-products:many", endpoint: PageTopProducts, wantError: "PAGE_LIMITS entry for top-products"},
+		{name: "unknown endpoint", env: "orders:10", endpoint: PageTopProducts, wantError: `unknown endpoint "orders"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", EnvTest)
+			t.Setenv("PAGE_LIMITS", tt.env)
+			if tt.maxLimit != "" {
+				t.Setenv("MAX_PAGE_LIMIT", tt.maxLimit)
+			} else {
+				unsetenv(t, "MAX_PAGE_LIMIT")
+			}
+
+			cfg := Load()
+			err := cfg.Validate()
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Validate() = %v, want an error about %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if got := cfg.PageLimitsFor(tt.endpoint); got != tt.want {
+				t.Errorf("PageLimitsFor(%s) = %+v, want %+v", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+// Application environments selectable with APP_ENV
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
+)
+
+// profile holds the defaults that differ between environments. Every field can
+// still be overridden by its own environment variable.
+type profile struct {
+	driver         string
+	dbName         string
+	dbRequired     bool
+	logFormat      string
+	logLevel       string
+	swaggerEnabled bool
+	rateLimitRPS   float64
+	seedOnStartup  bool
+	features       []string
+}
+
+// profiles maps each APP_ENV to its defaults. Production refuses to start in demo
+// mode and keeps the docs and experimental features private; tests run against an
+// in-memory SQLite database.
+var profiles = map[string]profile{
+	EnvDev: {
+		driver:         DriverMySQL,
+		dbName:         "abt_analytics",
+		logFormat:      "text",
+		logLevel:       "debug",
+		swaggerEnabled: true,
+		rateLimitRPS:   10,
+		seedOnStartup:  true,
+		features:       []string{FeatureGrowth},
+	},
+	EnvTest: {
+		driver:        DriverSQLite,
+		dbName:        ":memory:",
+		dbRequired:    true,
+		logFormat:     "text",
+		logLevel:      "warn",
+		seedOnStartup: true,
+		features:      []string{FeatureGrowth},
+	},
+	EnvProd: {
+		driver:       DriverMySQL,
+		dbName:       "abt_analytics",
+		dbRequired:   true,
+		logFormat:    "json",
+		logLevel:     "info",
+		rateLimitRPS: 10,
+	},
+}
+
+// profileFor returns the defaults for env, using the dev profile for an unknown
+// environment (Validate reports it)
+func profileFor(env string) profile {
+	if p, ok := profiles[env]; ok {
+		return p
+	}
+	return profiles[EnvDev]
+}
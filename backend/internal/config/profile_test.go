@@ -0,0 +1,88 @@
+package config
+
+import "testing"
+
+// profileVars are the environment variables that override profile defaults
+var profileVars = []string{
+	"APP_ENV", "DB_DRIVER", "DB_PORT", "DB_NAME", "DB_REQUIRED", "LOG_FORMAT", "LOG_LEVEL",
+	"SWAGGER_ENABLED", "GIN_MODE", "RATE_LIMIT_RPS", "SEED_ON_STARTUP", "FEATURES",
+}
+
+func TestLoadProfiles(t *testing.T) {
+	tests := []struct {
+		env            string
+		driver         string
+		dbName         string
+		dbRequired     bool
+		logFormat      string
+		logLevel       string
+		swaggerEnabled bool
+		rateLimitRPS   float64
+		seedOnStartup  bool
+		growth         bool
+	}{
+		{env: "", driver: DriverMySQL, dbName: "abt_analytics", logFormat: "text", logLevel: "debug", swaggerEnabled: true, rateLimitRPS: 10, seedOnStartup: true, growth: true},
+		{env: EnvDev, driver: DriverMySQL, dbName: "abt_analytics", logFormat: "text", logLevel: "debug", swaggerEnabled: true, rateLimitRPS: 10, seedOnStartup: true, growth: true},
+		{env: EnvTest, driver: DriverSQLite, dbName: ":memory:", dbRequired: true, logFormat: "text", logLevel: "warn", seedOnStartup: true, growth: true},
+		{env: EnvProd, driver: DriverMySQL, dbName: "abt_analytics", dbRequired: true, logFormat: "json", logLevel: "info", rateLimitRPS: 10},
+	}
+	for _, tt := range tests {
+		name := tt.env
+		if name == "" {
+			name = "unset"
+		}
+		t.Run(name, func(t *testing.T) {
+			unsetenv(t, profileVars...)
+			if tt.env != "" {
+				t.Setenv("APP_ENV", tt.env)
+			}
+
+			cfg := Load()
+			if cfg.Driver != tt.driver || cfg.DBName != tt.dbName || cfg.DBRequired != tt.dbRequired {
+				t.Errorf("database = %s %q required=%v, want %s %q required=%v",
+					cfg.Driver, cfg.DBName, cfg.DBRequired, tt.driver, tt.dbName, tt.dbRequired)
+			}
+			if cfg.LogFormat != tt.logFormat || cfg.LogLevel != tt.logLevel {
+				t.Errorf("logging = %s at %s, want %s at %s", cfg.LogFormat, cfg.LogLevel, tt.logFormat, tt.logLevel)
+			}
+			if cfg.SwaggerEnabled != tt.swaggerEnabled {
+				t.Errorf("SwaggerEnabled = %v, want %v", cfg.SwaggerEnabled, tt.swaggerEnabled)
+			}
+			if cfg.RateLimitRPS != tt.rateLimitRPS {
+				t.Errorf("RateLimitRPS = %v, want %v", cfg.RateLimitRPS, tt.rateLimitRPS)
+			}
+			if cfg.SeedOnStartup != tt.seedOnStartup {
+				t.Errorf("SeedOnStartup = %v, want %v", cfg.SeedOnStartup, tt.seedOnStartup)
+			}
+			if cfg.Feature(FeatureGrowth) != tt.growth {
+				t.Errorf("growth feature = %v, want %v", cfg.Feature(FeatureGrowth), tt.growth)
+			}
+		})
+	}
+}
+
+func TestLoadEnvironmentOverridesProfile(t *testing.T) {
+	unsetenv(t, profileVars...)
+	t.Setenv("APP_ENV", EnvProd)
+	t.Setenv("DB_DRIVER", DriverPostgres)
+	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("SWAGGER_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RPS", "0")
+	t.Setenv("DB_REQUIRED", "false")
+
+	cfg := Load()
+	if cfg.Env != EnvProd {
+		t.Errorf("Env = %q, want %q", cfg.Env, EnvProd)
+	}
+	if cfg.Driver != DriverPostgres || cfg.DBPort != "5432" {
+		t.Errorf("database = %s on %s, want postgres on 5432", cfg.Driver, cfg.DBPort)
+	}
+	if cfg.LogFormat != "text" || cfg.LogLevel != "debug" {
+		t.Errorf("logging = %s at %s, want text at debug", cfg.LogFormat, cfg.LogLevel)
+	}
+	if !cfg.SwaggerEnabled || cfg.RateLimitRPS != 0 || cfg.DBRequired {
+		t.Errorf("swagger=%v rps=%v required=%v, want the environment's true, 0 and false",
+			cfg.SwaggerEnabled, cfg.RateLimitRPS, cfg.DBRequired)
+	}
+}
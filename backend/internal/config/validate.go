@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpMethods are the request methods CORS_METHODS may list
+var httpMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// ValidationError lists every problem found in a Config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the loaded settings and returns a *ValidationError naming
+// every invalid value, or nil when the configuration is usable
+func (c *Config) Validate() error {
+	problems := append([]string(nil), c.envProblems...)
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, ok := profiles[c.Env]; !ok {
+		addf("APP_ENV %q must be %q, %q or %q", c.Env, EnvDev, EnvTest, EnvProd)
+	}
+	if !validPort(c.Port) {
+		addf("PORT %q must be a number between 1 and 65535", c.Port)
+	}
+
+	if c.DemoMode && c.DBRequired {
+		addf("DEMO_MODE and DB_REQUIRED cannot both be set")
+	}
+	if c.DBRequired && c.Driver == DriverSQLite {
+		if c.DBName == "" {
+			addf("DB_NAME is required when DB_REQUIRED is set")
+		}
+	} else if c.DBRequired {
+		if c.Driver != DriverMySQL && c.Driver != DriverPostgres {
+			addf("DB_DRIVER %q must be %q, %q or %q", c.Driver, DriverMySQL, DriverPostgres, DriverSQLite)
+		}
+		if c.DBHost == "" {
+			addf("DB_HOST is required when DB_REQUIRED is set")
+		}
+		if c.DBUser == "" {
+			addf("DB_USER is required when DB_REQUIRED is set")
+		}
+		if c.DBName == "" {
+			addf("DB_NAME is required when DB_REQUIRED is set")
+		}
+		if !validPort(c.DBPort) {
+			addf("DB_PORT %q must be a number between 1 and 65535", c.DBPort)
+		}
+	}
+
+	if !validIdentifier(c.DBTablePrefix) {
+		addf("DB_TABLE_PREFIX %q may only contain letters, digits and underscores", c.DBTablePrefix)
+	}
+	if c.DBMaxOpenConns < 0 {
+		addf("DB_MAX_OPEN_CONNS must not be negative")
+	}
+	if c.DBMaxIdleConns < 0 {
+		addf("DB_MAX_IDLE_CONNS must not be negative")
+	}
+	switch c.DBLogLevel {
+	case DBLogSilent, DBLogError, DBLogWarn, DBLogInfo:
+	default:
+		addf("DB_LOG_LEVEL %q must be %q, %q, %q or %q", c.DBLogLevel, DBLogSilent, DBLogError, DBLogWarn, DBLogInfo)
+	}
+	if c.DBConnectAttempts < 1 {
+		addf("DB_CONNECT_ATTEMPTS must be at least 1")
+	}
+	if c.JWTSecret != "" && len(c.JWTSecret) < 32 {
+		addf("JWT_SECRET must be at least 32 bytes")
+	}
+	if len(c.CORSOrigins) == 0 {
+		addf("CORS_ORIGINS must list at least one origin")
+	}
+	for _, method := range c.CORSMethods {
+		if !httpMethods[method] {
+			addf("CORS_METHODS entry %q is not an HTTP method", method)
+		}
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			addf("TRUSTED_PROXIES entry %q must be an IP address or CIDR range", proxy)
+		}
+	}
+	if c.GzipMinSize < 0 {
+		addf("GZIP_MIN_SIZE must not be negative")
+	}
+	if c.MaxPageLimit < 1 {
+		addf("MAX_PAGE_LIMIT must be at least 1")
+	}
+	for _, name := range unknownPageLimits(c.PageLimits) {
+		addf("PAGE_LIMITS names unknown endpoint %q, expected one of %s", name, strings.Join(paginatedEndpoints(), ", "))
+	}
+	for _, name := range paginatedEndpoints() {
+		if _, ok := c.PageLimits[name]; !ok {
+			continue
+		}
+		if limits := c.PageLimitsFor(name); limits.Default < 1 || limits.Default > limits.Max {
+			addf("PAGE_LIMITS entry for %s must be %s:default[:max] with 1 <= default <= max (MAX_PAGE_LIMIT when left out)", name, name)
+		}
+	}
+	if c.SeedCount < 0 {
+		addf("SEED_COUNT must not be negative")
+	}
+	if c.MaxQueryDays < 0 {
+		addf("MAX_QUERY_DAYS must not be negative")
+	}
+	if c.DefaultRangeDays < 0 {
+		addf("DEFAULT_RANGE_DAYS must not be negative")
+	}
+	if c.MaxBodyBytes < 1 {
+		addf("MAX_BODY_BYTES must be at least 1")
+	}
+	if rate, ok := c.Rates[c.BaseCurrency]; c.BaseCurrency == "" || (ok && rate != 1) {
+		addf("BASE_CURRENCY %q must be set and, when listed in RATES, have a rate of 1", c.BaseCurrency)
+	}
+	if c.RateLimitRPS < 0 || c.RateLimitBurst < 0 {
+		addf("RATE_LIMIT_RPS and RATE_LIMIT_BURST must not be negative")
+	}
+	if c.RateLimitRPS > 0 && c.RateLimitBurst < 1 {
+		addf("RATE_LIMIT_BURST must be at least 1 when RATE_LIMIT_RPS is set, or every request is rejected")
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime},
+		{"DB_SLOW_THRESHOLD", c.DBSlowThreshold},
+		{"DB_CONNECT_BACKOFF", c.DBConnectBackoff},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+		{"REQUEST_TIMEOUT", c.RequestTimeout},
+		{"EXPORT_TIMEOUT", c.ExportTimeout},
+		{"SERVER_READ_TIMEOUT", c.ServerReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.ServerWriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.ServerIdleTimeout},
+		{"CORS_MAX_AGE", c.CORSMaxAge},
+		{"CACHE_TTL", c.CacheTTL},
+		{"PRECOMPUTE_INTERVAL", c.PrecomputeInterval},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			addf("%s must not be negative", d.name)
+		}
+	}
+	if c.PrecomputeInterval > 0 && c.PrecomputeInterval >= c.CacheTTL {
+		addf("PRECOMPUTE_INTERVAL (%s) must be shorter than CACHE_TTL (%s) so precomputed results do not expire between runs",
+			c.PrecomputeInterval, c.CacheTTL)
+	}
+	if c.ServerWriteTimeout > 0 && c.RequestTimeout > 0 && c.ServerWriteTimeout <= c.RequestTimeout {
+		addf("SERVER_WRITE_TIMEOUT (%s) must exceed REQUEST_TIMEOUT (%s) so timed-out requests still get a response",
+			c.ServerWriteTimeout, c.RequestTimeout)
+	}
+
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		addf("LOG_FORMAT %q must be \"text\" or \"json\"", c.LogFormat)
+	}
+	if c.LogSampleRate < 0 || c.LogSampleRate > 1 {
+		addf("LOG_SAMPLE_RATE %v must be between 0 and 1", c.LogSampleRate)
+	}
+	if c.JSONNaming != JSONNamingSnakeCase && c.JSONNaming != JSONNamingCamelCase {
+		addf("JSON_NAMING %q must be %q or %q", c.JSONNaming, JSONNamingSnakeCase, JSONNamingCamelCase)
+	}
+	if c.CSVDecimal != DecimalDot && c.CSVDecimal != DecimalComma {
+		addf("CSV_DECIMAL %q must be %q or %q", c.CSVDecimal, DecimalDot, DecimalComma)
+	}
+	if c.CacheBackend != CacheBackendMemory && c.CacheBackend != CacheBackendRedis {
+		addf("CACHE_BACKEND %q must be %q or %q", c.CacheBackend, CacheBackendMemory, CacheBackendRedis)
+	}
+
+	for _, name := range unknownFeatures(c.Features) {
+		addf("FEATURES contains unknown flag %q, expected one of %s", name, strings.Join(knownFeatures, ", "))
+	}
+
+	if c.APIBasePath != "" && (!strings.HasPrefix(c.APIBasePath, "/") || strings.ContainsAny(c.APIBasePath, ":*?# ")) {
+		addf("API_BASE_PATH %q must be a path starting with / and without wildcards, queries or spaces", c.APIBasePath)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+// validIdentifier reports whether value is safe to use in an SQL identifier
+// unquoted: letters, digits and underscores only
+func validIdentifier(value string) bool {
+	for _, r := range value {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadValid returns the configuration Load builds from the defaults, failing the
+// test if it does not validate
+func loadValid(t *testing.T) *Config {
+	t.Helper()
+	unsetenv(t, "APP_ENV", "DB_REQUIRED", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST")
+
+	cfg := Load()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("defaults do not validate: %v", err)
+	}
+	return cfg
+}
+
+// problemsMentioning returns the validation problems in err that mention name
+func problemsMentioning(t *testing.T, err error, name string) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate = %v, want a *ValidationError", err)
+	}
+	var matching []string
+	for _, problem := range validationErr.Problems {
+		if strings.Contains(problem, name) {
+			matching = append(matching, problem)
+		}
+	}
+	return matching
+}
+
+func TestValidateRateLimitBurst(t *testing.T) {
+	tests := []struct {
+		name    string
+		rps     float64
+		burst   int
+		invalid bool
+	}{
+		{name: "limiting with a burst", rps: 10, burst: 1},
+		{name: "limiting without a burst", rps: 10, burst: 0, invalid: true},
+		{name: "fractional rate without a burst", rps: 0.5, burst: 0, invalid: true},
+		{name: "disabled without a burst", rps: 0, burst: 0},
+		{name: "negative burst", rps: 0, burst: -1, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadValid(t)
+			cfg.RateLimitRPS = tt.rps
+			cfg.RateLimitBurst = tt.burst
+
+			problems := problemsMentioning(t, cfg.Validate(), "RATE_LIMIT_BURST")
+			if invalid := len(problems) > 0; invalid != tt.invalid {
+				t.Errorf("RATE_LIMIT_BURST problems = %v, want invalid = %v", problems, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	for _, env := range []string{EnvDev, EnvTest} {
+		t.Run(env, func(t *testing.T) {
+			loadValid(t)
+			t.Setenv("APP_ENV", env)
+			if err := Load().Validate(); err != nil {
+				t.Errorf("Validate = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidPermutations(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   []string
+	}{
+		{name: "port not numeric", mutate: func(c *Config) { c.Port = "80a" }, want: []string{"PORT"}},
+		{name: "port out of range", mutate: func(c *Config) { c.Port = "70000" }, want: []string{"PORT"}},
+		{
+			name: "required database without fields",
+			mutate: func(c *Config) {
+				c.DBRequired, c.Driver, c.DBHost, c.DBUser, c.DBName = true, DriverPostgres, "", "", ""
+			},
+			want: []string{"DB_HOST", "DB_USER", "DB_NAME"},
+		},
+		{name: "negative TTL", mutate: func(c *Config) { c.CacheTTL = -1 }, want: []string{"CACHE_TTL"}},
+		{name: "negative timeout", mutate: func(c *Config) { c.RequestTimeout = -1 }, want: []string{"REQUEST_TIMEOUT"}},
+		{
+			name: "several problems at once",
+			mutate: func(c *Config) {
+				c.Port = "http"
+				c.ShutdownTimeout = -1
+				c.LogFormat = "xml"
+			},
+			want: []string{"PORT", "SHUTDOWN_TIMEOUT", "LOG_FORMAT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadValid(t)
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Validate = nil, want an error")
+			}
+			for _, name := range tt.want {
+				if len(problemsMentioning(t, err, name)) == 0 {
+					t.Errorf("%v does not mention %s", err, name)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReportsUnparsableVariables(t *testing.T) {
+	loadValid(t)
+	t.Setenv("DB_MAX_OPEN_CONNS", "abc")
+	t.Setenv("REQUEST_TIMEOUT", "5x")
+	t.Setenv("CACHE_TTL", "bogus")
+	t.Setenv("DB_REQUIRED", "maybe")
+	t.Setenv("RATE_LIMIT_RPS", "ten")
+
+	cfg := Load()
+	if cfg.DBMaxOpenConns != 25 || cfg.RequestTimeout != 30*time.Second {
+		t.Errorf("unparsable values did not fall back to the defaults: %d, %s", cfg.DBMaxOpenConns, cfg.RequestTimeout)
+	}
+
+	err := cfg.Validate()
+	for name, want := range map[string]string{
+		"DB_MAX_OPEN_CONNS": `DB_MAX_OPEN_CONNS "abc" is not a valid integer`,
+		"REQUEST_TIMEOUT":   `REQUEST_TIMEOUT "5x" is not a valid duration`,
+		"CACHE_TTL":         `CACHE_TTL "bogus" is not a valid duration`,
+		"DB_REQUIRED":       `DB_REQUIRED "maybe" is not a valid boolean`,
+		"RATE_LIMIT_RPS":    `RATE_LIMIT_RPS "ten" is not a valid number`,
+	} {
+		problems := problemsMentioning(t, err, name)
+		if len(problems) != 1 || !strings.HasPrefix(problems[0], want) {
+			t.Errorf("%s problems = %v, want %q", name, problems, want)
+		}
+	}
+}
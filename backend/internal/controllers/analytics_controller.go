@@ -0,0 +1,1533 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/buildinfo"
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/health"
+	"abt-analytics/internal/middleware"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/services"
+)
+
+// AnalyticsController handles the analytics HTTP endpoints.
+// Successful responses carry the resource itself, as documented per handler;
+// every error response is a models.ErrorResponse, except that the health check
+// reports through a models.HealthResponse whatever its status.
+type AnalyticsController struct {
+	service  AnalyticsService
+	cfg      *config.Config
+	checkers []health.Checker
+	// catalog is set by DescribeAnalytics once the routes are registered
+	catalog *models.AnalyticsCatalog
+}
+
+// NewAnalyticsController creates a new analytics controller.
+// A nil service makes every data endpoint report 503.
+// The checkers are the dependencies probed by the health check.
+func NewAnalyticsController(service AnalyticsService, cfg *config.Config, checkers []health.Checker) *AnalyticsController {
+	return &AnalyticsController{service: service, cfg: cfg, checkers: checkers}
+}
+
+// HealthCheck godoc
+// @Summary Health check
+// @Description Probes each dependency and reports its status and latency. The API is degraded, but still
+// @Description answers 200, when only non-critical dependencies such as the cache are down, and unhealthy
+// @Description with 503 when the database is down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /health [get]
+func (ac *AnalyticsController) HealthCheck(c *gin.Context) {
+	status, checks := health.Run(c.Request.Context(), ac.checkers, healthCheckTimeout)
+
+	code, message := http.StatusOK, "ABT Analytics API is running"
+	switch status {
+	case health.StatusDegraded:
+		message = "ABT Analytics API is running with degraded dependencies"
+	case health.StatusUnhealthy:
+		code, message = http.StatusServiceUnavailable, "ABT Analytics API is unhealthy"
+	}
+
+	c.JSON(code, models.HealthResponse{
+		Status:    status,
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Checks:    checks,
+	})
+}
+
+// Readiness godoc
+// @Summary Readiness check
+// @Description Reports whether the API can serve traffic by pinging the database
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /ready [get]
+func (ac *AnalyticsController) Readiness(c *gin.Context) {
+	var dependencyErr string
+	if ac.service == nil {
+		dependencyErr = "database not configured"
+	} else if err := ac.service.Ping(c.Request.Context()); err != nil {
+		dependencyErr = err.Error()
+	}
+
+	if dependencyErr != "" {
+		respondErrorDetails(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Not ready",
+			models.DependencyError{Dependency: "database", Error: dependencyErr})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReadinessResponse{Status: "ready"})
+}
+
+// MigrationStatus godoc
+// @Summary Migration status
+// @Description Reports whether the database schema is current, listing the missing tables and the missing
+// @Description columns of existing tables when it is not, e.g. after a deploy whose migration failed.
+// @Description Answers 503 with the same body while anything is missing.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.MigrationStatus
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.MigrationStatus
+// @Router /health/migrations [get]
+func (ac *AnalyticsController) MigrationStatus(c *gin.Context) {
+	if ac.service == nil {
+		respondErrorDetails(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Migration status unavailable",
+			models.DependencyError{Dependency: "database", Error: "database not configured"})
+		return
+	}
+
+	status, err := ac.service.GetMigrationStatus(c.Request.Context())
+	switch {
+	case errors.Is(err, services.ErrDemoNoDatabase):
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Migration status is not available in demo mode")
+	case err != nil:
+		respondInternalError(c, err, "Failed to check migration status")
+	case !status.Migrated:
+		c.JSON(http.StatusServiceUnavailable, status)
+	default:
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// Version godoc
+// @Summary Build information
+// @Description Returns the git commit, build time and Go version of the running binary
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.VersionResponse
+// @Router /version [get]
+func (ac *AnalyticsController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
+
+// GetCountryRevenue godoc
+// @Summary Get revenue by country
+// @Description Returns the total revenue per country, highest first. The optional from/to bounds are inclusive.
+// @Description Repeat country to restrict the breakdown to a watchlist; percentages are then shares of its total.
+// @Description Send Accept: text/csv or text/tab-separated-values, or format=csv or format=tsv, to download
+// @Description the data as CSV or TSV instead of JSON.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Produce text/tab-separated-values
+// @Param country query []string false "Countries to include, repeatable, matched ignoring case and surrounding spaces" collectionFormat(multi)
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param format query string false "Response format" Enums(json, csv, tsv)
+// @Param decimal query string false "Decimal separator of CSV and TSV (default CSV_DECIMAL); comma CSV uses semicolon fields" Enums(dot, comma)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param X-API-Version header string false "Wrap the JSON body in this response envelope version; omitted, the body is bare" Enums(1)
+// @Success 200 {array} models.CountryRevenue
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/country-revenue [get]
+func (ac *AnalyticsController) GetCountryRevenue(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+	version, ok := envelopeVersion(c)
+	if !ok {
+		return
+	}
+
+	params := newQueryParams(c)
+	filter := models.CountryFilter{
+		Countries: params.parseFilterListParam("country"),
+		DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays),
+	}
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	decimal := params.parseEnumParam("decimal", ac.cfg.CSVDecimal, config.DecimalDot, config.DecimalComma)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetCountryRevenue(c.Request.Context(), filter, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load country revenue")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	render(c, "country-revenue", envelop(c, version, data), data, decimal)
+}
+
+// GetCategoryRevenue godoc
+// @Summary Get revenue by product category
+// @Description Returns the total revenue per product category, highest first. The optional from/to bounds are inclusive.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.CategoryRevenue
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/category-revenue [get]
+func (ac *AnalyticsController) GetCategoryRevenue(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetCategoryRevenue(c.Request.Context(), dateRange, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load category revenue")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetAverageOrderValue godoc
+// @Summary Get average order value by country
+// @Description Returns the average revenue per transaction for each country, rounded to two decimals, highest first.
+// @Description The optional from/to bounds are inclusive.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.CountryOrderValue
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/avg-order-value [get]
+func (ac *AnalyticsController) GetAverageOrderValue(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetAverageOrderValue(c.Request.Context(), dateRange, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load average order value")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetOrderValuePercentiles godoc
+// @Summary Get order value percentiles
+// @Description Returns the 50th, 90th, 95th and 99th percentiles of the revenue per transaction, using the
+// @Description nearest-rank method, along with the number of transactions. The percentiles are null when the
+// @Description window holds no transactions. When MAX_QUERY_DAYS is set, from and to are required and may span
+// @Description at most that many days.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.OrderValuePercentiles
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/order-value-percentiles [get]
+func (ac *AnalyticsController) GetOrderValuePercentiles(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseWindowParams(ac.maxWindowDays(0), ac.cfg.DefaultRangeDays)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetOrderValuePercentiles(c.Request.Context(), dateRange, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load order value percentiles")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// ComparePeriods godoc
+// @Summary Compare two periods
+// @Description Compares revenue, the number of orders or the average order value (aov) over two inclusive
+// @Description periods, a and b, returning both values, the difference b - a and that difference as a
+// @Description percentage of a. Values are decimal strings; the average order value of a period without
+// @Description orders is null, as are the difference and percentage that depend on it. The percentage is
+// @Description also null when a is zero.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param a_from query string true "Start of period a (RFC3339 or YYYY-MM-DD)"
+// @Param a_to query string true "End of period a (RFC3339 or YYYY-MM-DD)"
+// @Param b_from query string true "Start of period b (RFC3339 or YYYY-MM-DD)"
+// @Param b_to query string true "End of period b (RFC3339 or YYYY-MM-DD)"
+// @Param metric query string false "Metric to compare (default revenue)" Enums(revenue, orders, aov)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.PeriodComparison
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/compare [get]
+func (ac *AnalyticsController) ComparePeriods(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	a := params.parsePeriodParams("a_")
+	b := params.parsePeriodParams("b_")
+	metric := params.parseEnumParam("metric", services.MetricRevenue, services.MetricRevenue, services.MetricOrders, services.MetricAOV)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.ComparePeriods(c.Request.Context(), a, b, metric, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to compare periods")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetTopProducts godoc
+// @Summary Get top products
+// @Description Returns one page of products ranked by total revenue or units sold, with the total product count
+// @Description and links to the current, next and previous pages.
+// @Description The ranking covers every country unless country is given; the optional from/to bounds are inclusive.
+// @Description Send Accept: text/csv or text/tab-separated-values, or format=csv or format=tsv, to download
+// @Description the current page of products as CSV or TSV instead of JSON.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Produce text/tab-separated-values
+// @Param country query string false "Rank products sold in this country only (name matched ignoring case and surrounding spaces)"
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param sort query string false "Ranking key (default revenue)" Enums(revenue, units)
+// @Param dir query string false "Ranking direction (default desc)" Enums(asc, desc)
+// @Param limit query int false "Page size (default 10; PAGE_LIMITS sets the default and maximum)"
+// @Param offset query int false "Number of products to skip (default 0)"
+// @Param format query string false "Response format" Enums(json, csv, tsv)
+// @Param decimal query string false "Decimal separator of CSV and TSV (default CSV_DECIMAL); comma CSV uses semicolon fields" Enums(dot, comma)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param X-API-Version header string false "Wrap the JSON body in this response envelope version; omitted, the body is bare" Enums(1)
+// @Success 200 {object} models.ProductRevenuePage
+// @Header 200 {integer} X-Total-Count "Number of matching rows across all pages"
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/top-products [get]
+func (ac *AnalyticsController) GetTopProducts(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+	version, ok := envelopeVersion(c)
+	if !ok {
+		return
+	}
+
+	params := newQueryParams(c)
+	sort := models.ProductSort{
+		By:        params.parseEnumParam("sort", models.ProductSortRevenue, models.ProductSortRevenue, models.ProductSortUnits),
+		Ascending: params.parseEnumParam("dir", "desc", "asc", "desc") == "asc",
+	}
+	limit, offset := params.parsePaginationParams(ac.cfg.PageLimitsFor(config.PageTopProducts))
+	filter := models.ProductFilter{
+		Country:   params.parseFilterParam("country"),
+		DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays),
+	}
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	decimal := params.parseEnumParam("decimal", ac.cfg.CSVDecimal, config.DecimalDot, config.DecimalComma)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	page, cacheHit, err := ac.service.GetTopProducts(c.Request.Context(), filter, sort, limit, offset, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load top products")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+	page.Links = offsetPageLinks(c, limit, offset, page.Total)
+	setTotalCountHeader(c, page.Total)
+
+	render(c, "top-products", envelop(c, version, page), page.Data, decimal)
+}
+
+// GetRevenueConcentration godoc
+// @Summary Get revenue concentration across products
+// @Description Ranks the products by revenue, highest first, with each one's share of the total revenue and the
+// @Description cumulative share of it and the products above it, in percent. For each threshold, ascending, it counts
+// @Description the top products whose cumulative revenue reaches that share, answering questions such as how many
+// @Description products make up 80% of revenue; counts are 0 when nothing was sold. The optional from/to bounds
+// @Description are inclusive.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param threshold query []number false "Revenue share in percent, above 0 and at most 100; repeat for several, up to 10 (default 50, 80 and 95)" collectionFormat(multi)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.RevenueConcentration
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/revenue-concentration [get]
+func (ac *AnalyticsController) GetRevenueConcentration(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	thresholds := params.parsePercentagesParam("threshold", defaultConcentrationThresholds, maxConcentrationThresholds)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetRevenueConcentration(c.Request.Context(), dateRange, thresholds, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load revenue concentration")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetTopCustomers godoc
+// @Summary Get top customers
+// @Description Returns one page of customers ranked by total revenue, highest first, with their order counts.
+// @Description Transactions without a customer ID are not counted. The optional from/to bounds are inclusive.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param limit query int false "Page size (default 10; PAGE_LIMITS sets the default and maximum)"
+// @Param offset query int false "Number of customers to skip (default 0)"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.CustomerRevenuePage
+// @Header 200 {integer} X-Total-Count "Number of matching rows across all pages"
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/top-customers [get]
+func (ac *AnalyticsController) GetTopCustomers(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	limit, offset := params.parsePaginationParams(ac.cfg.PageLimitsFor(config.PageTopCustomers))
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	page, cacheHit, err := ac.service.GetTopCustomers(c.Request.Context(), dateRange, limit, offset, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load top customers")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+	page.Links = offsetPageLinks(c, limit, offset, page.Total)
+	setTotalCountHeader(c, page.Total)
+
+	respondJSONWithETag(c, page)
+}
+
+// GetMonthlySales godoc
+// @Summary Get monthly sales
+// @Description Returns the total revenue per month (YYYY-MM) or quarter (YYYY-Qn) in chronological order.
+// @Description With compare=yoy each period also carries the prior-year revenue and the percent change.
+// @Description product and category narrow the trend to one product line and combine with each other.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param product query string false "Product name, matched ignoring case and surrounding spaces"
+// @Param category query string false "Product category, matched ignoring case and surrounding spaces"
+// @Param granularity query string false "Grouping period" Enums(month, quarter)
+// @Param compare query string false "Add a year-over-year comparison" Enums(yoy)
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param tz query string false "IANA time zone whose calendar months the sales are grouped into" default(UTC)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param X-API-Version header string false "Wrap the JSON body in this response envelope version; omitted, the body is bare" Enums(1)
+// @Success 200 {array} models.MonthlySales
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/monthly-sales [get]
+func (ac *AnalyticsController) GetMonthlySales(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+	version, ok := envelopeVersion(c)
+	if !ok {
+		return
+	}
+
+	params := newQueryParams(c)
+	granularity := params.parseEnumParam("granularity", services.GranularityMonth, services.GranularityMonth, services.GranularityQuarter)
+	compare := params.parseEnumParam("compare", "", compareYoY)
+	filter := models.SalesFilter{
+		Product:   params.parseFilterParam("product"),
+		Category:  params.parseFilterParam("category"),
+		DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays),
+		Location:  params.parseTimeZoneParam(),
+	}
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetMonthlySales(c.Request.Context(), filter, granularity, compare == compareYoY, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load monthly sales")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, envelop(c, version, data))
+}
+
+// GetRevenueGrowth godoc
+// @Summary Get month-over-month revenue growth
+// @Description Returns the revenue per month (YYYY-MM) in chronological order with its percentage change
+// @Description from the previous month. Months without sales inside the series are zero-filled, not skipped.
+// @Description growth_pct is null for the first month and for any month following one without revenue.
+// @Description product and category narrow the series to one product line and combine with each other.
+// @Description Experimental: only served while the growth feature flag is enabled (see FEATURES).
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param product query string false "Product name, matched ignoring case and surrounding spaces"
+// @Param category query string false "Product category, matched ignoring case and surrounding spaces"
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param tz query string false "IANA time zone whose calendar months the sales are grouped into" default(UTC)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.RevenueGrowth
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/growth [get]
+func (ac *AnalyticsController) GetRevenueGrowth(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	filter := models.SalesFilter{
+		Product:   params.parseFilterParam("product"),
+		Category:  params.parseFilterParam("category"),
+		DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays),
+		Location:  params.parseTimeZoneParam(),
+	}
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetRevenueGrowth(c.Request.Context(), filter, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load revenue growth")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetRevenueForecast godoc
+// @Summary Get a monthly revenue forecast
+// @Description Projects the revenue per month (YYYY-MM) months ahead by fitting a straight line through the last
+// @Description window calendar months up to the last month with sales, months without sales counting as zero, and
+// @Description extending it. Those window months are returned as recorded with forecast false, followed by the
+// @Description projected months with forecast true; projections below zero are reported as zero. A month still in
+// @Description progress counts as recorded so far, so end to at the last complete month to keep it out of the trend.
+// @Description Answers 422 with code insufficient_data when fewer than window months of sales are available.
+// @Description product and category narrow the series to one product line and combine with each other.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param months query int false "Number of months to project, up to 24" default(3)
+// @Param window query int false "Number of recorded months the trend is fitted through, from 2 to 36" default(6)
+// @Param product query string false "Product name, matched ignoring case and surrounding spaces"
+// @Param category query string false "Product category, matched ignoring case and surrounding spaces"
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param tz query string false "IANA time zone whose calendar months the sales are grouped into" default(UTC)
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.RevenueForecast
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/forecast [get]
+func (ac *AnalyticsController) GetRevenueForecast(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	months := params.parseIntParam("months", defaultForecastMonths, 1, maxForecastMonths)
+	window := params.parseIntParam("window", defaultForecastWindow, minForecastWindow, maxForecastWindow)
+	filter := models.SalesFilter{
+		Product:   params.parseFilterParam("product"),
+		Category:  params.parseFilterParam("category"),
+		DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays),
+		Location:  params.parseTimeZoneParam(),
+	}
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetRevenueForecast(c.Request.Context(), filter, months, window, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load revenue forecast")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetDailyRevenue godoc
+// @Summary Get daily revenue
+// @Description Returns the revenue per UTC day (YYYY-MM-DD) in chronological order, meant for sparklines.
+// @Description from and to are required and may span at most 366 days, or MAX_QUERY_DAYS when lower. Every day of the window is
+// @Description present; days without sales carry zero revenue.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string true "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string true "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.DailyRevenue
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/daily-revenue [get]
+func (ac *AnalyticsController) GetDailyRevenue(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseWindowParams(ac.maxWindowDays(maxDailyRevenueDays), 0)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetDailyRevenue(c.Request.Context(), dateRange, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load daily revenue")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetTopRegions godoc
+// @Summary Get top regions
+// @Description Returns the n regions with the highest total revenue in the window (default 30, capped at 100)
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param n query int false "Number of regions to return"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param X-API-Version header string false "Wrap the JSON body in this response envelope version; omitted, the body is bare" Enums(1)
+// @Success 200 {array} models.RegionRevenue
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/top-regions [get]
+func (ac *AnalyticsController) GetTopRegions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+	version, ok := envelopeVersion(c)
+	if !ok {
+		return
+	}
+
+	params := newQueryParams(c)
+	filter := models.RegionFilter{DateRange: params.parseDateRangeParams(ac.cfg.DefaultRangeDays)}
+	n := params.parseTopNParam("n", defaultTopRegions, maxTopRegions)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetTopRegions(c.Request.Context(), filter, n, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load top regions")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, envelop(c, version, data))
+}
+
+// GetCountryRegions godoc
+// @Summary Get regions within a country
+// @Description Returns the n regions of one country with the highest total revenue in the window (default 30,
+// @Description capped at 100). An unknown country yields an empty list.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param country path string true "Country name, URL-encoded, matched ignoring case and surrounding spaces"
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param n query int false "Number of regions to return"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.RegionRevenue
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/country/{country}/regions [get]
+func (ac *AnalyticsController) GetCountryRegions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	filter := models.RegionFilter{Country: foldFilter(c.Param("country"))}
+	if filter.Country == "" {
+		params.addError("country", "must not be empty")
+	}
+	filter.DateRange = params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	n := params.parseTopNParam("n", defaultTopRegions, maxTopRegions)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetTopRegions(c.Request.Context(), filter, n, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load country regions")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// GetRegionTrends godoc
+// @Summary Get monthly revenue per region
+// @Description Returns the monthly revenue of the n regions with the highest revenue in the window (default 5,
+// @Description capped at 20), highest first. Months are UTC calendar months. Every series covers the same months,
+// @Description from the month of from to the month of to, or of the first and last sale when a bound is omitted,
+// @Description with zero revenue where a region sold nothing.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param n query int false "Number of regions to return"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {array} models.RegionTrend
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/region-trends [get]
+func (ac *AnalyticsController) GetRegionTrends(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	n := params.parseTopNParam("n", defaultTrendRegions, maxTrendRegions)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	data, cacheHit, err := ac.service.GetRegionTrends(c.Request.Context(), dateRange, n, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load region trends")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, data)
+}
+
+// SummarySizes returns how many products and regions the dashboard summary carries
+func SummarySizes(cfg *config.Config) (int, int) {
+	return cfg.PageLimitsFor(config.PageTopProducts).Default, defaultTopRegions
+}
+
+// GetSummary godoc
+// @Summary Get the dashboard summary
+// @Description Returns country revenue, the first page of top products, monthly sales and the top 30 regions in one response,
+// @Description all over the same window. The sections load in parallel; a section that fails is left empty and listed under errors.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the reporting window (RFC3339 or YYYY-MM-DD)"
+// @Param all query bool false "With from and to omitted, cover the full history instead of the trailing DEFAULT_RANGE_DAYS"
+// @Param currency query string false "Convert revenue into this currency code (see RATES)"
+// @Param normalize query bool false "Convert transactions recorded in other currencies to the base currency before summing them"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.DashboardSummary
+// @Header 200 {string} X-Default-Range-From "Start of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Header 200 {string} X-Default-Range-To "End of the trailing DEFAULT_RANGE_DAYS window applied because from and to were omitted"
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/summary [get]
+func (ac *AnalyticsController) GetSummary(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	dateRange := params.parseDateRangeParams(ac.cfg.DefaultRangeDays)
+	conversion := params.parseConversionParams(ac.cfg.Rates)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	productLimit, regionCount := SummarySizes(ac.cfg)
+	summary, cacheHit, err := ac.service.GetSummary(c.Request.Context(), dateRange, productLimit, regionCount, conversion)
+	if err != nil {
+		respondServiceError(c, err, "Failed to load dashboard summary")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, summary)
+}
+
+// GetDataMeta godoc
+// @Summary Get data freshness
+// @Description Returns the date of the newest transaction and the number of transactions, for showing "data as of" on the dashboard
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.DataMeta
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/meta [get]
+func (ac *AnalyticsController) GetDataMeta(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	meta, cacheHit, err := ac.service.GetDataMeta(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err, "Failed to load data freshness")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, meta)
+}
+
+// GetDimensions godoc
+// @Summary Get filter values
+// @Description Returns the distinct countries, regions, products and categories in the data, each sorted
+// @Description alphabetically, for populating filter dropdowns
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.Dimensions
+// @Success 304 "Not modified since the ETag in If-None-Match"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /analytics/dimensions [get]
+func (ac *AnalyticsController) GetDimensions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	dimensions, cacheHit, err := ac.service.GetDimensions(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, err, "Failed to load filter values")
+		return
+	}
+	setCacheHeader(c, cacheHit)
+
+	respondJSONWithETag(c, dimensions)
+}
+
+// ListTransactions godoc
+// @Summary List transactions
+// @Description Returns one page of raw transactions, newest first, optionally filtered by country, product, region and an inclusive date range.
+// @Description With updated_since only transactions stored or updated after it are listed, ordered by updated_at and then id,
+// @Description so incremental syncs can pass the greatest updated_at they have seen; rows stored before updated_at was recorded never match.
+// @Description Pass the returned next_cursor as cursor to fetch the following page without offset scans; it is empty on the last page.
+// @Description links holds the current, next and previous page URLs; pages fetched by cursor only link forward.
+// @Description When MAX_QUERY_DAYS is set, from and to are required and may span at most that many days.
+// @Tags transactions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param country query string false "Country name, matched ignoring case and surrounding spaces"
+// @Param product query string false "Product name, matched ignoring case and surrounding spaces"
+// @Param region query string false "Region name, matched ignoring case and surrounding spaces"
+// @Param from query string false "Start of the window (RFC3339 or YYYY-MM-DD); required when MAX_QUERY_DAYS is set"
+// @Param to query string false "End of the window (RFC3339 or YYYY-MM-DD); required when MAX_QUERY_DAYS is set"
+// @Param updated_since query string false "Only list transactions stored or updated after this time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Page size (default 50; PAGE_LIMITS sets the default and maximum)"
+// @Param offset query int false "Number of rows to skip (default 0)"
+// @Param cursor query string false "next_cursor from the previous page; cannot be combined with offset"
+// @Success 200 {object} models.TransactionPage
+// @Header 200 {integer} X-Total-Count "Number of matching rows across all pages"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /transactions [get]
+func (ac *AnalyticsController) ListTransactions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	params.rejectUnknownParams(transactionListParams)
+	filter := models.TransactionFilter{
+		Country:      params.parseFilterParam("country"),
+		Product:      params.parseFilterParam("product"),
+		Region:       params.parseFilterParam("region"),
+		DateRange:    params.parseWindowParams(ac.maxWindowDays(0), 0),
+		UpdatedSince: params.parseDateParam("updated_since", false),
+	}
+	limit, offset := params.parsePaginationParams(ac.cfg.PageLimitsFor(config.PageTransactions))
+
+	var after *models.TransactionCursor
+	if token := c.Query("cursor"); token != "" {
+		// A cursor only continues listings in the order it was issued for
+		if cursor, err := services.DecodeCursor(token); err != nil || cursor.UpdatedAt.IsZero() != (filter.UpdatedSince == nil) {
+			params.addError("cursor", "is not a cursor returned as next_cursor")
+		} else {
+			after = &cursor
+		}
+		if c.Query("offset") != "" {
+			params.addError("offset", "cannot be combined with cursor")
+		}
+	}
+	if params.respondIfInvalid() {
+		return
+	}
+
+	page, err := ac.service.ListTransactions(c.Request.Context(), filter, after, limit, offset)
+	if err != nil {
+		respondInternalError(c, err, "Failed to load transactions")
+		return
+	}
+	if after != nil {
+		page.Links = cursorPageLinks(c, limit, page.NextCursor)
+	} else {
+		page.Links = offsetPageLinks(c, limit, offset, page.Total)
+	}
+	setTotalCountHeader(c, page.Total)
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetTransaction godoc
+// @Summary Get a transaction
+// @Description Returns one transaction by its ID. Voided transactions are not found.
+// @Tags transactions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} models.TransactionResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /transactions/{id} [get]
+func (ac *AnalyticsController) GetTransaction(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	id := params.parseIDParam("id")
+	if params.respondIfInvalid() {
+		return
+	}
+
+	transaction, err := ac.service.GetTransaction(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, models.ErrTransactionNotFound):
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound, "Transaction not found")
+	case err != nil:
+		respondInternalError(c, err, "Failed to load transaction")
+	default:
+		c.JSON(http.StatusOK, transaction)
+	}
+}
+
+// ExportTransactions godoc
+// @Summary Export transactions
+// @Description Downloads every transaction matching the filters, newest first, as CSV (default) or TSV.
+// @Description Rows are streamed as they are read, so exports of any size use constant memory; an export
+// @Description still running after EXPORT_TIMEOUT is cut off. When MAX_QUERY_DAYS is set, from and to are
+// @Description required and may span at most that many days.
+// @Tags transactions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce text/tab-separated-values
+// @Param country query string false "Country name, matched ignoring case and surrounding spaces"
+// @Param product query string false "Product name, matched ignoring case and surrounding spaces"
+// @Param region query string false "Region name, matched ignoring case and surrounding spaces"
+// @Param from query string false "Start of the window (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the window (RFC3339 or YYYY-MM-DD)"
+// @Param format query string false "File format (default csv)" Enums(csv, tsv)
+// @Param decimal query string false "Decimal separator (default CSV_DECIMAL); comma CSV uses semicolon fields" Enums(dot, comma)
+// @Success 200 {file} file
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /transactions/export [get]
+func (ac *AnalyticsController) ExportTransactions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	params.rejectUnknownParams(transactionExportParams)
+	filter := models.TransactionFilter{
+		Country:   params.parseFilterParam("country"),
+		Product:   params.parseFilterParam("product"),
+		Region:    params.parseFilterParam("region"),
+		DateRange: params.parseWindowParams(ac.maxWindowDays(0), 0),
+	}
+	formatName := params.parseEnumParam("format", "csv", "csv", "tsv")
+	decimal := params.parseEnumParam("decimal", ac.cfg.CSVDecimal, config.DecimalDot, config.DecimalComma)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	format, _ := exportFormatNamed(formatName)
+	table, err := newTableWriter(c, "transactions", format.withDecimal(decimal), reflect.TypeOf(models.Transaction{}))
+	if err != nil {
+		respondInternalError(c, err, "Failed to export transactions")
+		return
+	}
+
+	err = ac.service.ExportTransactions(c.Request.Context(), filter, func(transaction models.Transaction) error {
+		return table.write(transaction)
+	})
+	switch {
+	case err != nil && !table.started():
+		respondInternalError(c, err, "Failed to export transactions")
+	case err != nil:
+		// The rows sent so far cannot be taken back; cut the download short so
+		// the client sees an incomplete transfer rather than a complete file
+		_ = c.Error(err)
+		c.Abort()
+		panic(http.ErrAbortHandler)
+	default:
+		if err := table.close(); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// IngestTransactions godoc
+// @Summary Ingest a batch of transactions
+// @Description Validates and stores up to 1000 transactions atomically. Invalid rows are reported together
+// @Description with their indices and nothing is stored. Cached analytics are flushed, so they reflect the new rows right away.
+// @Description Bodies larger than MAX_BODY_BYTES are rejected with 413.
+// @Description Ingestion is unavailable (503) while the API serves demo data.
+// @Tags transactions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param transactions body []models.TransactionInput true "Transactions to store"
+// @Success 201 {object} models.BatchInsertResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.RowError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /transactions/batch [post]
+func (ac *AnalyticsController) IngestTransactions(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	var inputs []models.TransactionInput
+	switch err := json.NewDecoder(c.Request.Body).Decode(&inputs); {
+	case errors.Is(err, middleware.ErrBodyTooLarge):
+		respondError(c, http.StatusRequestEntityTooLarge, models.ErrCodePayloadTooLarge,
+			fmt.Sprintf("Request body must not exceed %d bytes", ac.cfg.MaxBodyBytes))
+		return
+	case err != nil:
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidParameter, "invalid request body: "+err.Error())
+		return
+	}
+	if len(inputs) == 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidParameter, "batch must contain at least one transaction")
+		return
+	}
+	if len(inputs) > maxBatchSize {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidParameter, fmt.Sprintf("batch must not exceed %d transactions", maxBatchSize))
+		return
+	}
+
+	inserted, err := ac.service.IngestTransactions(c.Request.Context(), inputs)
+	var validationErr *services.BatchValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		respondErrorDetails(c, http.StatusUnprocessableEntity, models.ErrCodeValidationFailed, validationErr.Error(), validationErr.Rows)
+	case errors.Is(err, models.ErrDuplicateOrder):
+		_ = c.Error(err)
+		respondError(c, http.StatusConflict, models.ErrCodeConflict, "One or more order IDs already exist")
+	case errors.Is(err, services.ErrDemoReadOnly):
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Ingestion is not available in demo mode")
+	case err != nil:
+		respondInternalError(c, err, "Failed to store transactions")
+	default:
+		c.JSON(http.StatusCreated, models.BatchInsertResult{Inserted: inserted})
+	}
+}
+
+// DeleteTransaction godoc
+// @Summary Void a transaction
+// @Description Soft-deletes a transaction: it stays stored for auditing but no longer appears in listings
+// @Description or analytics, whose cached results are flushed. Unavailable (503) while the API serves demo data.
+// @Tags transactions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 204 "Transaction deleted"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /transactions/{id} [delete]
+func (ac *AnalyticsController) DeleteTransaction(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	id := params.parseIDParam("id")
+	if params.respondIfInvalid() {
+		return
+	}
+
+	err := ac.service.DeleteTransaction(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, models.ErrTransactionNotFound):
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound, "Transaction not found")
+	case errors.Is(err, services.ErrDemoReadOnly):
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Deleting transactions is not available in demo mode")
+	case err != nil:
+		respondInternalError(c, err, "Failed to delete transaction")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// FlushCache godoc
+// @Summary Flush cached analytics
+// @Description Evicts cached analytics results so the next requests read fresh data, e.g. after a backfill
+// @Description made outside the API. scope limits the flush to the results of one endpoint.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Param scope query string false "Endpoint whose results to evict" Enums(country-revenue, category-revenue, avg-order-value, order-value-percentiles, top-products, top-customers, monthly-sales, growth, daily-revenue, top-regions, region-trends, compare, meta, dimensions, revenue-concentration, forecast)
+// @Success 200 {object} models.CacheFlushResult
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse{details=[]models.FieldError}
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /admin/cache/flush [post]
+func (ac *AnalyticsController) FlushCache(c *gin.Context) {
+	if !ac.requireService(c) {
+		return
+	}
+
+	params := newQueryParams(c)
+	scope := params.parseEnumParam("scope", "", services.CacheScopes...)
+	if params.respondIfInvalid() {
+		return
+	}
+
+	evicted, err := ac.service.FlushCache(c.Request.Context(), scope)
+	if err != nil {
+		respondInternalError(c, err, "Failed to flush the cache")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CacheFlushResult{Evicted: evicted, Scope: scope})
+}
+
+// requireService writes a 503 response when the controller has no service
+func (ac *AnalyticsController) requireService(c *gin.Context) bool {
+	if ac.service == nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Database not available")
+		return false
+	}
+	return true
+}
+
+// maxWindowDays returns the widest from/to window, in days, an endpoint scanning
+// individual transactions accepts: limit, tightened to MAX_QUERY_DAYS when that is
+// configured and lower. Zero means any window, including an open-ended one.
+func (ac *AnalyticsController) maxWindowDays(limit int) int {
+	if days := ac.cfg.MaxQueryDays; days > 0 && (limit <= 0 || days < limit) {
+		return days
+	}
+	return limit
+}
+
+// currencyParam returns the requested currency code in upper case, or empty for the base currency
+func currencyParam(c *gin.Context) string {
+	return strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+}
+
+// setCacheHeader reports through X-Cache whether the service cache served the response
+func setCacheHeader(c *gin.Context, hit bool) {
+	if hit {
+		c.Header("X-Cache", "HIT")
+	} else {
+		c.Header("X-Cache", "MISS")
+	}
+}
+
+// respondError writes the standard error envelope tagged with the request ID
+func respondError(c *gin.Context, status int, code, message string) {
+	respondErrorDetails(c, status, code, message, nil)
+}
+
+// respondErrorDetails writes the standard error envelope with extra details
+func respondErrorDetails(c *gin.Context, status int, code, message string, details interface{}) {
+	c.AbortWithStatusJSON(status, models.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: middleware.GetRequestID(c),
+	})
+}
+
+// respondServiceError maps service errors caused by bad input to 400, revenue that
+// cannot be summed as requested to 409 and everything else to 500
+func respondServiceError(c *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrUnknownCurrency) || errors.Is(err, models.ErrUnknownSortField) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidParameter, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrMixedCurrencies) {
+		respondError(c, http.StatusConflict, models.ErrCodeConflict, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientHistory) {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeInsufficientData, err.Error())
+		return
+	}
+	respondInternalError(c, err, message)
+}
+
+// respondInternalError records err for the request log and answers with a
+// generic 500 so database errors are not leaked to clients. Errors caused by
+// the request deadline passing answer 504 instead.
+func respondInternalError(c *gin.Context, err error, message string) {
+	_ = c.Error(err)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		respondError(c, http.StatusGatewayTimeout, models.ErrCodeTimeout, "Request timed out")
+		return
+	}
+	respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, message)
+}
@@ -0,0 +1,103 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"abt-analytics/docs"
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/services"
+)
+
+func TestFlushCacheEvictsAndCounts(t *testing.T) {
+	db := newSQLiteDB(t, models.Transaction{
+		TransactionDate: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Country:         "US",
+		Region:          "Texas",
+		Product:         "Widget",
+		Quantity:        1,
+		Revenue:         money("10"),
+	})
+	cfg := testConfig()
+	cfg.CacheTTL = time.Minute
+	service := services.NewAnalyticsService(repository.NewAnalyticsRepository(db, cfg.Rates), cache.NewMemoryCache(), cfg)
+	controller := controllers.NewAnalyticsController(service, cfg, nil)
+
+	countryRevenue := func() string {
+		return get(controller.GetCountryRevenue, "/country-revenue", "/country-revenue").Header().Get("X-Cache")
+	}
+	topProducts := func() string {
+		return get(controller.GetTopProducts, "/top-products", "/top-products").Header().Get("X-Cache")
+	}
+	flush := func(query string) *httptest.ResponseRecorder {
+		return serve(controller.FlushCache, http.MethodPost, "/admin/cache/flush", "/admin/cache/flush"+query, "")
+	}
+	evicted := func(query string) models.CacheFlushResult {
+		t.Helper()
+		w := flush(query)
+		assertStatus(t, w, http.StatusOK)
+		var result models.CacheFlushResult
+		decodeJSON(t, w, &result)
+		return result
+	}
+
+	countryRevenue()
+	topProducts()
+	if countryRevenue() != "HIT" || topProducts() != "HIT" {
+		t.Fatal("repeated requests were not served from the cache")
+	}
+
+	if result := evicted("?scope=country-revenue"); result.Evicted != 1 || result.Scope != "country-revenue" {
+		t.Errorf("scoped flush = %+v, want 1 country-revenue entry evicted", result)
+	}
+	if got := countryRevenue(); got != "MISS" {
+		t.Errorf("country revenue after its flush: X-Cache = %s, want MISS", got)
+	}
+	if got := topProducts(); got != "HIT" {
+		t.Errorf("top products after another scope's flush: X-Cache = %s, want HIT", got)
+	}
+
+	// the recorded currencies, cached for the conversion check, go as well
+	if result := evicted(""); result.Evicted != 3 || result.Scope != "" {
+		t.Errorf("full flush = %+v, want country revenue, top products and currencies evicted", result)
+	}
+	if result := evicted("?scope=top-products"); result.Evicted != 0 {
+		t.Errorf("flushing an emptied scope evicted %d entries", result.Evicted)
+	}
+	if countryRevenue() != "MISS" || topProducts() != "MISS" {
+		t.Error("entries survived a full flush")
+	}
+
+	assertStatus(t, flush("?scope=bogus"), http.StatusUnprocessableEntity)
+}
+
+func TestFlushCacheDocsListEveryScope(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name string   `json:"name"`
+				Enum []string `json:"enum"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec); err != nil {
+		t.Fatalf("parse swagger: %v", err)
+	}
+	for _, param := range spec.Paths["/admin/cache/flush"]["post"].Parameters {
+		if param.Name != "scope" {
+			continue
+		}
+		if got, want := strings.Join(param.Enum, ","), strings.Join(services.CacheScopes, ","); got != want {
+			t.Errorf("documented scopes = %s, want services.CacheScopes %s", got, want)
+		}
+		return
+	}
+	t.Error("scope parameter is not documented")
+}
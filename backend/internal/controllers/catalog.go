@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// swaggerSpec is the part of the generated API docs the analytics catalog is built from
+type swaggerSpec struct {
+	Paths map[string]map[string]struct {
+		Summary    string `json:"summary"`
+		Parameters []struct {
+			Name        string        `json:"name"`
+			In          string        `json:"in"`
+			Type        string        `json:"type"`
+			Required    bool          `json:"required"`
+			Description string        `json:"description"`
+			Enum        []interface{} `json:"enum"`
+			Items       *struct {
+				Type string `json:"type"`
+			} `json:"items"`
+		} `json:"parameters"`
+	} `json:"paths"`
+}
+
+// DescribeAnalytics builds the catalog GetAnalyticsCatalog serves from the
+// registered routes under the analytics group, so features switched off are left
+// out, and describes each one from spec, the swagger document generated from the
+// handler annotations. Paths are listed in swagger form, with {name} for path
+// parameters; header parameters are not listed.
+func (ac *AnalyticsController) DescribeAnalytics(spec string, routes gin.RoutesInfo) error {
+	var doc swaggerSpec
+	if err := json.Unmarshal([]byte(spec), &doc); err != nil {
+		return fmt.Errorf("parse API docs: %w", err)
+	}
+
+	root := ac.cfg.APIBasePath + "/analytics"
+	endpoints := []models.AnalyticsEndpoint{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, root+"/") {
+			continue
+		}
+		path := swaggerPath(route.Path)
+		endpoint := models.AnalyticsEndpoint{Method: route.Method, Path: path, Params: []models.EndpointParam{}}
+		operation, ok := doc.Paths[strings.TrimPrefix(path, ac.cfg.APIBasePath)][strings.ToLower(route.Method)]
+		if ok {
+			endpoint.Summary = operation.Summary
+			for _, param := range operation.Parameters {
+				if param.In != "query" && param.In != "path" {
+					continue
+				}
+				described := models.EndpointParam{
+					Name:        param.Name,
+					In:          param.In,
+					Type:        param.Type,
+					Required:    param.Required,
+					Description: param.Description,
+					Enum:        param.Enum,
+				}
+				if param.Items != nil {
+					described.Items = param.Items.Type
+				}
+				endpoint.Params = append(endpoint.Params, described)
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Path < endpoints[j].Path })
+
+	ac.catalog = &models.AnalyticsCatalog{Endpoints: endpoints}
+	return nil
+}
+
+// swaggerPath turns a gin route path such as /country/:country/regions into the
+// swagger form /country/{country}/regions
+func swaggerPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// GetAnalyticsCatalog godoc
+// @Summary List the analytics endpoints
+// @Description Returns every analytics endpoint this server exposes with the query and path parameters it
+// @Description accepts, their types and, where limited, their accepted values, for dashboards that discover
+// @Description the available analytics at run time. Endpoints behind disabled features are not listed.
+// @Tags analytics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.AnalyticsCatalog
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Router /analytics [get]
+func (ac *AnalyticsController) GetAnalyticsCatalog(c *gin.Context) {
+	if ac.catalog == nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable, "Analytics catalog is not available")
+		return
+	}
+	c.JSON(http.StatusOK, ac.catalog)
+}
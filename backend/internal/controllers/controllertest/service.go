@@ -0,0 +1,219 @@
+// Package controllertest provides a stand-in analytics service for exercising
+// the controllers without a database.
+package controllertest
+
+import (
+	"context"
+
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/models"
+)
+
+var _ controllers.AnalyticsService = (*MockAnalyticsService)(nil)
+
+// MockAnalyticsService implements controllers.AnalyticsService by calling the
+// function set for each method. A method whose function is nil returns empty
+// results (empty lists and pages, successful writes), so a test only sets the
+// methods its handler reaches.
+type MockAnalyticsService struct {
+	PingFunc                     func(ctx context.Context) error
+	GetMigrationStatusFunc       func(ctx context.Context) (*models.MigrationStatus, error)
+	GetCountryRevenueFunc        func(ctx context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error)
+	GetCategoryRevenueFunc       func(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CategoryRevenue, bool, error)
+	GetAverageOrderValueFunc     func(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CountryOrderValue, bool, error)
+	GetOrderValuePercentilesFunc func(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) (*models.OrderValuePercentiles, bool, error)
+	ComparePeriodsFunc           func(ctx context.Context, a, b models.DateRange, metric string, conversion models.Conversion) (*models.PeriodComparison, bool, error)
+	GetTopProductsFunc           func(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int, conversion models.Conversion) (*models.ProductRevenuePage, bool, error)
+	GetTopCustomersFunc          func(ctx context.Context, dateRange models.DateRange, limit, offset int, conversion models.Conversion) (*models.CustomerRevenuePage, bool, error)
+	GetRevenueConcentrationFunc  func(ctx context.Context, dateRange models.DateRange, thresholds []float64, conversion models.Conversion) (*models.RevenueConcentration, bool, error)
+	GetMonthlySalesFunc          func(ctx context.Context, filter models.SalesFilter, granularity string, compareYoY bool, conversion models.Conversion) ([]models.MonthlySales, bool, error)
+	GetRevenueGrowthFunc         func(ctx context.Context, filter models.SalesFilter, conversion models.Conversion) ([]models.RevenueGrowth, bool, error)
+	GetRevenueForecastFunc       func(ctx context.Context, filter models.SalesFilter, months, window int, conversion models.Conversion) ([]models.RevenueForecast, bool, error)
+	GetDailyRevenueFunc          func(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.DailyRevenue, bool, error)
+	GetTopRegionsFunc            func(ctx context.Context, filter models.RegionFilter, n int, conversion models.Conversion) ([]models.RegionRevenue, bool, error)
+	GetRegionTrendsFunc          func(ctx context.Context, dateRange models.DateRange, n int, conversion models.Conversion) ([]models.RegionTrend, bool, error)
+	GetSummaryFunc               func(ctx context.Context, dateRange models.DateRange, productLimit, regionCount int, conversion models.Conversion) (*models.DashboardSummary, bool, error)
+	GetDataMetaFunc              func(ctx context.Context) (*models.DataMeta, bool, error)
+	GetDimensionsFunc            func(ctx context.Context) (*models.Dimensions, bool, error)
+	ListTransactionsFunc         func(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error)
+	GetTransactionFunc           func(ctx context.Context, id uint) (*models.TransactionResponse, error)
+	ExportTransactionsFunc       func(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error
+	IngestTransactionsFunc       func(ctx context.Context, inputs []models.TransactionInput) (int, error)
+	DeleteTransactionFunc        func(ctx context.Context, id uint) error
+	FlushCacheFunc               func(ctx context.Context, scope string) (int, error)
+}
+
+func (m *MockAnalyticsService) Ping(ctx context.Context) error {
+	if m.PingFunc == nil {
+		return nil
+	}
+	return m.PingFunc(ctx)
+}
+
+func (m *MockAnalyticsService) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	if m.GetMigrationStatusFunc == nil {
+		return &models.MigrationStatus{Migrated: true, Missing: []models.SchemaGap{}}, nil
+	}
+	return m.GetMigrationStatusFunc(ctx)
+}
+
+func (m *MockAnalyticsService) GetCountryRevenue(ctx context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error) {
+	if m.GetCountryRevenueFunc == nil {
+		return []models.CountryRevenue{}, false, nil
+	}
+	return m.GetCountryRevenueFunc(ctx, filter, conversion)
+}
+
+func (m *MockAnalyticsService) GetCategoryRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CategoryRevenue, bool, error) {
+	if m.GetCategoryRevenueFunc == nil {
+		return []models.CategoryRevenue{}, false, nil
+	}
+	return m.GetCategoryRevenueFunc(ctx, dateRange, conversion)
+}
+
+func (m *MockAnalyticsService) GetAverageOrderValue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CountryOrderValue, bool, error) {
+	if m.GetAverageOrderValueFunc == nil {
+		return []models.CountryOrderValue{}, false, nil
+	}
+	return m.GetAverageOrderValueFunc(ctx, dateRange, conversion)
+}
+
+func (m *MockAnalyticsService) GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) (*models.OrderValuePercentiles, bool, error) {
+	if m.GetOrderValuePercentilesFunc == nil {
+		return &models.OrderValuePercentiles{}, false, nil
+	}
+	return m.GetOrderValuePercentilesFunc(ctx, dateRange, conversion)
+}
+
+func (m *MockAnalyticsService) ComparePeriods(ctx context.Context, a, b models.DateRange, metric string, conversion models.Conversion) (*models.PeriodComparison, bool, error) {
+	if m.ComparePeriodsFunc == nil {
+		return &models.PeriodComparison{Metric: metric}, false, nil
+	}
+	return m.ComparePeriodsFunc(ctx, a, b, metric, conversion)
+}
+
+func (m *MockAnalyticsService) GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int, conversion models.Conversion) (*models.ProductRevenuePage, bool, error) {
+	if m.GetTopProductsFunc == nil {
+		return &models.ProductRevenuePage{Data: []models.ProductRevenue{}, Limit: limit, Offset: offset}, false, nil
+	}
+	return m.GetTopProductsFunc(ctx, filter, sort, limit, offset, conversion)
+}
+
+func (m *MockAnalyticsService) GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int, conversion models.Conversion) (*models.CustomerRevenuePage, bool, error) {
+	if m.GetTopCustomersFunc == nil {
+		return &models.CustomerRevenuePage{Data: []models.CustomerRevenue{}, Limit: limit, Offset: offset}, false, nil
+	}
+	return m.GetTopCustomersFunc(ctx, dateRange, limit, offset, conversion)
+}
+
+func (m *MockAnalyticsService) GetRevenueConcentration(ctx context.Context, dateRange models.DateRange, thresholds []float64, conversion models.Conversion) (*models.RevenueConcentration, bool, error) {
+	if m.GetRevenueConcentrationFunc == nil {
+		return &models.RevenueConcentration{Products: []models.ProductShare{}, Thresholds: []models.ConcentrationThreshold{}}, false, nil
+	}
+	return m.GetRevenueConcentrationFunc(ctx, dateRange, thresholds, conversion)
+}
+
+func (m *MockAnalyticsService) GetMonthlySales(ctx context.Context, filter models.SalesFilter, granularity string, compareYoY bool, conversion models.Conversion) ([]models.MonthlySales, bool, error) {
+	if m.GetMonthlySalesFunc == nil {
+		return []models.MonthlySales{}, false, nil
+	}
+	return m.GetMonthlySalesFunc(ctx, filter, granularity, compareYoY, conversion)
+}
+
+func (m *MockAnalyticsService) GetRevenueGrowth(ctx context.Context, filter models.SalesFilter, conversion models.Conversion) ([]models.RevenueGrowth, bool, error) {
+	if m.GetRevenueGrowthFunc == nil {
+		return []models.RevenueGrowth{}, false, nil
+	}
+	return m.GetRevenueGrowthFunc(ctx, filter, conversion)
+}
+
+func (m *MockAnalyticsService) GetRevenueForecast(ctx context.Context, filter models.SalesFilter, months, window int, conversion models.Conversion) ([]models.RevenueForecast, bool, error) {
+	if m.GetRevenueForecastFunc == nil {
+		return []models.RevenueForecast{}, false, nil
+	}
+	return m.GetRevenueForecastFunc(ctx, filter, months, window, conversion)
+}
+
+func (m *MockAnalyticsService) GetDailyRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.DailyRevenue, bool, error) {
+	if m.GetDailyRevenueFunc == nil {
+		return []models.DailyRevenue{}, false, nil
+	}
+	return m.GetDailyRevenueFunc(ctx, dateRange, conversion)
+}
+
+func (m *MockAnalyticsService) GetTopRegions(ctx context.Context, filter models.RegionFilter, n int, conversion models.Conversion) ([]models.RegionRevenue, bool, error) {
+	if m.GetTopRegionsFunc == nil {
+		return []models.RegionRevenue{}, false, nil
+	}
+	return m.GetTopRegionsFunc(ctx, filter, n, conversion)
+}
+
+func (m *MockAnalyticsService) GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int, conversion models.Conversion) ([]models.RegionTrend, bool, error) {
+	if m.GetRegionTrendsFunc == nil {
+		return []models.RegionTrend{}, false, nil
+	}
+	return m.GetRegionTrendsFunc(ctx, dateRange, n, conversion)
+}
+
+func (m *MockAnalyticsService) GetSummary(ctx context.Context, dateRange models.DateRange, productLimit, regionCount int, conversion models.Conversion) (*models.DashboardSummary, bool, error) {
+	if m.GetSummaryFunc == nil {
+		return &models.DashboardSummary{}, false, nil
+	}
+	return m.GetSummaryFunc(ctx, dateRange, productLimit, regionCount, conversion)
+}
+
+func (m *MockAnalyticsService) GetDataMeta(ctx context.Context) (*models.DataMeta, bool, error) {
+	if m.GetDataMetaFunc == nil {
+		return &models.DataMeta{}, false, nil
+	}
+	return m.GetDataMetaFunc(ctx)
+}
+
+func (m *MockAnalyticsService) GetDimensions(ctx context.Context) (*models.Dimensions, bool, error) {
+	if m.GetDimensionsFunc == nil {
+		return &models.Dimensions{Countries: []string{}, Regions: []string{}, Products: []string{}, Categories: []string{}}, false, nil
+	}
+	return m.GetDimensionsFunc(ctx)
+}
+
+func (m *MockAnalyticsService) ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error) {
+	if m.ListTransactionsFunc == nil {
+		return &models.TransactionPage{Data: []models.TransactionResponse{}, Limit: limit, Offset: offset}, nil
+	}
+	return m.ListTransactionsFunc(ctx, filter, after, limit, offset)
+}
+
+func (m *MockAnalyticsService) GetTransaction(ctx context.Context, id uint) (*models.TransactionResponse, error) {
+	if m.GetTransactionFunc == nil {
+		return &models.TransactionResponse{ID: id}, nil
+	}
+	return m.GetTransactionFunc(ctx, id)
+}
+
+func (m *MockAnalyticsService) ExportTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error {
+	if m.ExportTransactionsFunc == nil {
+		return nil
+	}
+	return m.ExportTransactionsFunc(ctx, filter, fn)
+}
+
+func (m *MockAnalyticsService) IngestTransactions(ctx context.Context, inputs []models.TransactionInput) (int, error) {
+	if m.IngestTransactionsFunc == nil {
+		return len(inputs), nil
+	}
+	return m.IngestTransactionsFunc(ctx, inputs)
+}
+
+func (m *MockAnalyticsService) DeleteTransaction(ctx context.Context, id uint) error {
+	if m.DeleteTransactionFunc == nil {
+		return nil
+	}
+	return m.DeleteTransactionFunc(ctx, id)
+}
+
+func (m *MockAnalyticsService) FlushCache(ctx context.Context, scope string) (int, error) {
+	if m.FlushCacheFunc == nil {
+		return 0, nil
+	}
+	return m.FlushCacheFunc(ctx, scope)
+}
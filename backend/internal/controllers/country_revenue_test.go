@@ -0,0 +1,53 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestGetCountryRevenueShapesServiceRows(t *testing.T) {
+	var gotFilter models.CountryFilter
+	var gotConversion models.Conversion
+	service := &controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(_ context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error) {
+			gotFilter, gotConversion = filter, conversion
+			return []models.CountryRevenue{
+				{Country: "US", Revenue: money("1500.5"), Orders: 3, Percentage: 75.01, Currency: "EUR"},
+				{Country: "DE", Revenue: money("499.999"), Orders: 1, Percentage: 24.99, Currency: "EUR"},
+			}, true, nil
+		},
+	}
+	handler := newTestController(service).GetCountryRevenue
+
+	w := get(handler, "/country-revenue", "/country-revenue?country=US&country=%20de%20&country=us&from=2024-01-01&to=2024-01-31&currency=eur")
+	assertStatus(t, w, http.StatusOK)
+
+	// countries are folded and deduplicated before they reach the service
+	if !reflect.DeepEqual(gotFilter.Countries, []string{"us", "de"}) || gotFilter.DateRange.From == nil || gotFilter.DateRange.To == nil {
+		t.Errorf("filter = %+v, want countries us and de and both bounds", gotFilter)
+	} else if want := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC); !gotFilter.DateRange.From.Equal(want) {
+		t.Errorf("from = %s, want %s", gotFilter.DateRange.From, want)
+	}
+	if gotConversion.Currency != "EUR" {
+		t.Errorf("currency = %q, want EUR", gotConversion.Currency)
+	}
+	if cache := w.Header().Get("X-Cache"); cache != "HIT" {
+		t.Errorf("X-Cache = %q, want HIT", cache)
+	}
+
+	var body []map[string]interface{}
+	decodeJSON(t, w, &body)
+	want := []map[string]interface{}{
+		{"country": "US", "revenue": "1500.50", "order_count": 3.0, "percentage": 75.01, "currency": "EUR"},
+		{"country": "DE", "revenue": "500.00", "order_count": 1.0, "percentage": 24.99, "currency": "EUR"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+}
@@ -0,0 +1,74 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestDailyRevenueZeroFillsDays(t *testing.T) {
+	sale := func(day int, revenue string) models.Transaction {
+		return models.Transaction{
+			TransactionDate: time.Date(2024, time.February, day, 15, 0, 0, 0, time.UTC),
+			Country:         "US",
+			Region:          "Texas",
+			Product:         "Widget",
+			Quantity:        1,
+			Revenue:         money(revenue),
+		}
+	}
+	handler := newSQLiteController(newSQLiteDB(t, sale(27, "10"), sale(27, "5"), sale(29, "7"), sale(3, "99"))).GetDailyRevenue
+
+	// 2024 is a leap year, so the window runs through 29 February
+	w := get(handler, "/daily-revenue", "/daily-revenue?from=2024-02-26&to=2024-03-01")
+	assertStatus(t, w, http.StatusOK)
+	var rows []models.DailyRevenue
+	decodeJSON(t, w, &rows)
+
+	want := []struct{ date, revenue string }{
+		{"2024-02-26", "0"},
+		{"2024-02-27", "15"},
+		{"2024-02-28", "0"},
+		{"2024-02-29", "7"},
+		{"2024-03-01", "0"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %+v, want one row per day of %v", rows, want)
+	}
+	for i, day := range want {
+		if rows[i].Date != day.date || !rows[i].Revenue.Equal(money(day.revenue).Decimal) {
+			t.Errorf("row %d = %s %s, want %s %s", i, rows[i].Date, rows[i].Revenue.Format(), day.date, day.revenue)
+		}
+	}
+}
+
+func TestDailyRevenueRangeGuard(t *testing.T) {
+	handler := newSQLiteController(newSQLiteDB(t)).GetDailyRevenue
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "a full leap year", query: "from=2024-01-01&to=2024-12-31", want: http.StatusOK},
+		{name: "one day too many", query: "from=2024-01-01&to=2025-01-01", want: http.StatusUnprocessableEntity},
+		{name: "missing from", query: "to=2024-01-31", want: http.StatusUnprocessableEntity},
+		{name: "missing to", query: "from=2024-01-01", want: http.StatusUnprocessableEntity},
+		{name: "no range", want: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(handler, "/daily-revenue", "/daily-revenue?"+tt.query)
+			assertStatus(t, w, tt.want)
+			if tt.want == http.StatusOK {
+				var rows []models.DailyRevenue
+				decodeJSON(t, w, &rows)
+				if len(rows) != 366 {
+					t.Errorf("got %d days, want 366", len(rows))
+				}
+			}
+		})
+	}
+}
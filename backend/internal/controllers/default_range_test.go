@@ -0,0 +1,157 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/services"
+)
+
+func TestDefaultRangeAppliesUnlessAllIsSet(t *testing.T) {
+	sale := func(at time.Time, revenue string) models.Transaction {
+		return models.Transaction{TransactionDate: at, Country: "US", Region: "Texas", Product: "Widget", Category: "Tools", Quantity: 1, Revenue: money(revenue)}
+	}
+	now := time.Now().UTC()
+	db := newSQLiteDB(t, sale(now, "10"), sale(now.AddDate(0, 0, -200), "90"))
+	cfg := testConfig()
+	cfg.DefaultRangeDays = 90
+	repo := repository.NewAnalyticsRepository(db, cfg.Rates)
+	controller := controllers.NewAnalyticsController(services.NewAnalyticsService(repo, cache.NewMemoryCache(), cfg), cfg, nil)
+
+	tests := []struct {
+		name   string
+		query  string
+		orders int64
+		echoed bool
+	}{
+		{name: "default window", orders: 1, echoed: true},
+		{name: "all=true", query: "?all=true", orders: 2},
+		{name: "explicit range", query: "?from=2000-01-01", orders: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(controller.GetCountryRevenue, "/country-revenue", "/country-revenue"+tt.query)
+			assertStatus(t, w, http.StatusOK)
+			var countries []models.CountryRevenue
+			decodeJSON(t, w, &countries)
+			if len(countries) != 1 || countries[0].Orders != tt.orders {
+				t.Errorf("country revenue = %+v, want %d orders", countries, tt.orders)
+			}
+			if echoed := w.Header().Get(controllers.DefaultRangeFromHeader) != "" && w.Header().Get(controllers.DefaultRangeToHeader) != ""; echoed != tt.echoed {
+				t.Errorf("window echoed = %v, want %v", echoed, tt.echoed)
+			}
+
+			for _, regions := range []struct {
+				handler gin.HandlerFunc
+				route   string
+				target  string
+			}{
+				{controller.GetTopRegions, "/top-regions", "/top-regions"},
+				{controller.GetCountryRegions, "/country/:country/regions", "/country/US/regions"},
+			} {
+				w = get(regions.handler, regions.route, regions.target+tt.query)
+				assertStatus(t, w, http.StatusOK)
+				var rows []models.RegionRevenue
+				decodeJSON(t, w, &rows)
+				if len(rows) != 1 || rows[0].Orders != tt.orders {
+					t.Errorf("%s = %+v, want %d orders", regions.route, rows, tt.orders)
+				}
+				if echoed := w.Header().Get(controllers.DefaultRangeFromHeader) != ""; echoed != tt.echoed {
+					t.Errorf("%s: window echoed = %v, want %v", regions.route, echoed, tt.echoed)
+				}
+			}
+
+			// v1 envelopes echo the window in their meta, matching the headers
+			w = get(controller.GetCountryRevenue, "/country-revenue", "/country-revenue"+tt.query, controllers.APIVersionHeader, controllers.EnvelopeV1)
+			assertStatus(t, w, http.StatusOK)
+			var envelope struct {
+				Meta *struct {
+					DefaultRange *models.DateWindow `json:"default_range"`
+				} `json:"meta"`
+			}
+			decodeJSON(t, w, &envelope)
+			switch {
+			case !tt.echoed && envelope.Meta != nil:
+				t.Errorf("envelope meta = %+v, want none", envelope.Meta)
+			case tt.echoed && (envelope.Meta == nil || envelope.Meta.DefaultRange == nil):
+				t.Error("envelope meta does not echo the default window")
+			case tt.echoed:
+				window := envelope.Meta.DefaultRange
+				from, to := window.From.Format(time.RFC3339), window.To.Format(time.RFC3339Nano)
+				if from != w.Header().Get(controllers.DefaultRangeFromHeader) || to != w.Header().Get(controllers.DefaultRangeToHeader) {
+					t.Errorf("envelope meta window = %s..%s, want the echoed headers %s..%s", from, to,
+						w.Header().Get(controllers.DefaultRangeFromHeader), w.Header().Get(controllers.DefaultRangeToHeader))
+				}
+			}
+
+			// every section of the summary covers the same window
+			w = get(controller.GetSummary, "/summary", "/summary"+tt.query)
+			assertStatus(t, w, http.StatusOK)
+			var summary models.DashboardSummary
+			decodeJSON(t, w, &summary)
+			switch {
+			case len(summary.CountryRevenue) != 1 || summary.CountryRevenue[0].Orders != tt.orders:
+				t.Errorf("summary country revenue = %+v, want %d orders", summary.CountryRevenue, tt.orders)
+			case len(summary.TopRegions) != 1 || summary.TopRegions[0].Orders != tt.orders:
+				t.Errorf("summary top regions = %+v, want %d orders", summary.TopRegions, tt.orders)
+			case summary.TopProducts == nil || len(summary.TopProducts.Data) != 1 || summary.TopProducts.Data[0].Units != tt.orders:
+				t.Errorf("summary top products = %+v, want %d units", summary.TopProducts, tt.orders)
+			case int64(len(summary.MonthlySales)) != tt.orders:
+				t.Errorf("summary monthly sales = %+v, want %d months", summary.MonthlySales, tt.orders)
+			}
+			if echoed := w.Header().Get(controllers.DefaultRangeFromHeader) != ""; echoed != tt.echoed {
+				t.Errorf("summary: window echoed = %v, want %v", echoed, tt.echoed)
+			}
+
+			// endpoints scanning transactions default the same way
+			w = get(controller.GetOrderValuePercentiles, "/order-value-percentiles", "/order-value-percentiles"+tt.query)
+			assertStatus(t, w, http.StatusOK)
+			var percentiles models.OrderValuePercentiles
+			decodeJSON(t, w, &percentiles)
+			if percentiles.Orders != tt.orders {
+				t.Errorf("percentiles over %d orders, want %d", percentiles.Orders, tt.orders)
+			}
+		})
+	}
+}
+
+func TestDataMetaReportsTheDefaultRange(t *testing.T) {
+	db := newSQLiteDB(t)
+	tests := []struct {
+		name string
+		days int
+		want bool
+	}{
+		{name: "configured", days: 30, want: true},
+		{name: "full history", days: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.DefaultRangeDays = tt.days
+			repo := repository.NewAnalyticsRepository(db, cfg.Rates)
+			controller := controllers.NewAnalyticsController(services.NewAnalyticsService(repo, cache.NewMemoryCache(), cfg), cfg, nil)
+
+			w := get(controller.GetDataMeta, "/meta", "/meta")
+			assertStatus(t, w, http.StatusOK)
+			var meta models.DataMeta
+			decodeJSON(t, w, &meta)
+			if got := meta.DefaultRange != nil; got != tt.want {
+				t.Fatalf("default_range = %+v, want set %v", meta.DefaultRange, tt.want)
+			}
+			if tt.want {
+				want := models.TrailingDays(tt.days, time.Now())
+				if !meta.DefaultRange.From.Equal(*want.From) || !meta.DefaultRange.To.Equal(*want.To) {
+					t.Errorf("default_range = %+v, want %v..%v", meta.DefaultRange, *want.From, *want.To)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// APIVersionHeader selects the response envelope version of the endpoints that
+// support one and echoes the version served
+const APIVersionHeader = "X-API-Version"
+
+// Response envelope versions selectable with APIVersionHeader
+const (
+	EnvelopeV1 = "1"
+)
+
+var envelopeVersions = []string{EnvelopeV1}
+
+// envelopeVersion returns the envelope version requested in APIVersionHeader,
+// empty when the client asked for none and gets the bare response. A version
+// that is not served is answered with 400 and reported as false.
+func envelopeVersion(c *gin.Context) (string, bool) {
+	c.Writer.Header().Add("Vary", APIVersionHeader)
+
+	version := strings.TrimSpace(c.GetHeader(APIVersionHeader))
+	if version == "" {
+		return "", true
+	}
+	for _, candidate := range envelopeVersions {
+		if version == candidate {
+			c.Header(APIVersionHeader, version)
+			return version, true
+		}
+	}
+	respondError(c, http.StatusBadRequest, models.ErrCodeInvalidParameter,
+		fmt.Sprintf("Unsupported %s %q: supported versions are %s", APIVersionHeader, version, strings.Join(envelopeVersions, ", ")))
+	return "", false
+}
+
+// envelop shapes data as the envelope of version prescribes, leaving it bare for
+// an empty version. The v1 envelope carries the default window applied to the
+// request in its meta.
+func envelop(c *gin.Context, version string, data interface{}) interface{} {
+	switch version {
+	case EnvelopeV1:
+		envelope := models.Envelope{APIVersion: version, Data: emptyIfNil(data)}
+		if window, ok := c.Get(defaultRangeKey); ok {
+			envelope.Meta = &models.ResponseMeta{DefaultRange: window.(*models.DateWindow)}
+		}
+		return envelope
+	default:
+		return data
+	}
+}
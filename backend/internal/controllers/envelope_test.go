@@ -0,0 +1,65 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestEnvelopeVersions(t *testing.T) {
+	controller := newTestController(&controllertest.MockAnalyticsService{})
+	endpoints := []struct {
+		path    string
+		handler gin.HandlerFunc
+	}{
+		{"/country-revenue", controller.GetCountryRevenue},
+		{"/top-products", controller.GetTopProducts},
+		{"/monthly-sales", controller.GetMonthlySales},
+		{"/top-regions", controller.GetTopRegions},
+	}
+
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.path, func(t *testing.T) {
+			bare := get(endpoint.handler, endpoint.path, endpoint.path)
+			assertStatus(t, bare, http.StatusOK)
+			if got := bare.Header().Get(controllers.APIVersionHeader); got != "" {
+				t.Errorf("bare response echoes %s %q", controllers.APIVersionHeader, got)
+			}
+
+			w := get(endpoint.handler, endpoint.path, endpoint.path, controllers.APIVersionHeader, controllers.EnvelopeV1)
+			assertStatus(t, w, http.StatusOK)
+			var envelope struct {
+				APIVersion string          `json:"api_version"`
+				Data       json.RawMessage `json:"data"`
+			}
+			decodeJSON(t, w, &envelope)
+			if envelope.APIVersion != controllers.EnvelopeV1 {
+				t.Errorf("api_version = %q, want %q", envelope.APIVersion, controllers.EnvelopeV1)
+			}
+			if strings.TrimSpace(string(envelope.Data)) != strings.TrimSpace(bare.Body.String()) {
+				t.Errorf("data = %s, want the bare body %s", envelope.Data, bare.Body.String())
+			}
+			if got := w.Header().Get(controllers.APIVersionHeader); got != controllers.EnvelopeV1 {
+				t.Errorf("%s = %q, want the version served echoed", controllers.APIVersionHeader, got)
+			}
+			if vary := w.Header().Values("Vary"); !strings.Contains(strings.Join(vary, ","), controllers.APIVersionHeader) {
+				t.Errorf("Vary = %v, want it to include %s", vary, controllers.APIVersionHeader)
+			}
+
+			unknown := get(endpoint.handler, endpoint.path, endpoint.path, controllers.APIVersionHeader, "2")
+			assertStatus(t, unknown, http.StatusBadRequest)
+			var body models.ErrorResponse
+			decodeJSON(t, unknown, &body)
+			if body.Code != models.ErrCodeInvalidParameter {
+				t.Errorf("code = %q, want %q", body.Code, models.ErrCodeInvalidParameter)
+			}
+		})
+	}
+}
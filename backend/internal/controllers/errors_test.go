@@ -0,0 +1,85 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+// errorResponseKeys are the only keys an error body may carry
+var errorResponseKeys = map[string]bool{"code": true, "message": true, "details": true, "request_id": true}
+
+// assertErrorResponse checks that body is the standard error body with code
+func assertErrorResponse(t *testing.T, body map[string]interface{}, code string) {
+	t.Helper()
+	for key := range body {
+		if !errorResponseKeys[key] {
+			t.Errorf("unexpected key %q in error body %v", key, body)
+		}
+	}
+	if body["code"] != code {
+		t.Errorf("code = %v, want %s", body["code"], code)
+	}
+	if message, _ := body["message"].(string); message == "" {
+		t.Errorf("message missing from error body %v", body)
+	}
+}
+
+func TestHandlerErrorsUseErrorResponse(t *testing.T) {
+	failure := errors.New("connection refused")
+	service := &controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(context.Context, models.CountryFilter, models.Conversion) ([]models.CountryRevenue, bool, error) {
+			return nil, false, failure
+		},
+		GetTopProductsFunc: func(context.Context, models.ProductFilter, models.ProductSort, int, int, models.Conversion) (*models.ProductRevenuePage, bool, error) {
+			return nil, false, failure
+		},
+		GetMonthlySalesFunc: func(context.Context, models.SalesFilter, string, bool, models.Conversion) ([]models.MonthlySales, bool, error) {
+			return nil, false, failure
+		},
+		GetTopRegionsFunc: func(context.Context, models.RegionFilter, int, models.Conversion) ([]models.RegionRevenue, bool, error) {
+			return nil, false, failure
+		},
+	}
+	controller := newTestController(service)
+	withoutDatabase := controllers.NewAnalyticsController(nil, testConfig(), nil)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		status  int
+		code    string
+	}{
+		{"country revenue", controller.GetCountryRevenue, "/", http.StatusInternalServerError, models.ErrCodeInternal},
+		{"top products", controller.GetTopProducts, "/", http.StatusInternalServerError, models.ErrCodeInternal},
+		{"monthly sales", controller.GetMonthlySales, "/", http.StatusInternalServerError, models.ErrCodeInternal},
+		{"top regions", controller.GetTopRegions, "/", http.StatusInternalServerError, models.ErrCodeInternal},
+		{"readiness", withoutDatabase.Readiness, "/", http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable},
+		{"invalid parameter", controller.GetCountryRevenue, "/?from=yesterday", http.StatusUnprocessableEntity, models.ErrCodeValidationFailed},
+		{"unknown currency", controller.GetCountryRevenue, "/?currency=GBP", http.StatusUnprocessableEntity, models.ErrCodeValidationFailed},
+		{"unknown sort key", controller.GetTopProducts, "/?sort=price", http.StatusUnprocessableEntity, models.ErrCodeValidationFailed},
+		{"unknown sort direction", controller.GetTopProducts, "/?dir=up", http.StatusUnprocessableEntity, models.ErrCodeValidationFailed},
+		{"no database", withoutDatabase.GetTopProducts, "/", http.StatusServiceUnavailable, models.ErrCodeServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.handler, "/", tt.target)
+			assertStatus(t, w, tt.status)
+			var body map[string]interface{}
+			decodeJSON(t, w, &body)
+			assertErrorResponse(t, body, tt.code)
+			if tt.status == http.StatusInternalServerError && body["message"] == failure.Error() {
+				t.Error("internal error leaked to the client")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSONWithETag writes data as JSON tagged with a weak ETag derived from
+// the serialized body, answering 304 Not Modified with no body when the
+// request's If-None-Match already names that ETag. Data that is nil, as when
+// there are no transactions to aggregate, is sent as an empty value.
+func respondJSONWithETag(c *gin.Context, data interface{}) {
+	body, err := json.Marshal(emptyIfNil(data))
+	if err != nil {
+		respondInternalError(c, err, "Failed to encode response")
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// emptyIfNil replaces a nil slice or map with an empty one and a nil pointer
+// with a pointer to the zero value, so responses never carry a bare null
+func emptyIfNil(data interface{}) interface{} {
+	value := reflect.ValueOf(data)
+	switch {
+	case value.Kind() == reflect.Slice && value.IsNil():
+		return reflect.MakeSlice(value.Type(), 0, 0).Interface()
+	case value.Kind() == reflect.Map && value.IsNil():
+		return reflect.MakeMap(value.Type()).Interface()
+	case value.Kind() == reflect.Ptr && value.IsNil():
+		return reflect.New(value.Type().Elem()).Interface()
+	}
+	return data
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag
+// using the weak comparison required for GET requests
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestConditionalGetReturnsNotModified(t *testing.T) {
+	revenue := money("100")
+	service := &controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(context.Context, models.CountryFilter, models.Conversion) ([]models.CountryRevenue, bool, error) {
+			return []models.CountryRevenue{{Country: "US", Revenue: revenue, Orders: 1, Percentage: 100}}, true, nil
+		},
+	}
+	handler := newTestController(service).GetCountryRevenue
+
+	first := get(handler, "/", "/")
+	assertStatus(t, first, http.StatusOK)
+	etag := first.Header().Get("ETag")
+	if len(etag) < 4 || etag[:3] != `W/"` {
+		t.Fatalf("ETag = %q, want a weak ETag", etag)
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{name: "same ETag", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "strong form of the ETag", ifNoneMatch: etag[2:], status: http.StatusNotModified},
+		{name: "among several", ifNoneMatch: `W/"stale", ` + etag, status: http.StatusNotModified},
+		{name: "any", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "stale ETag", ifNoneMatch: `W/"stale"`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(handler, "/", "/", "If-None-Match", tt.ifNoneMatch)
+			assertStatus(t, w, tt.status)
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if tt.status == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 carried a body: %q", w.Body.String())
+			}
+		})
+	}
+
+	// A change in the data changes the ETag, so the old one no longer matches
+	revenue = money("101")
+	w := get(handler, "/", "/", "If-None-Match", etag)
+	assertStatus(t, w, http.StatusOK)
+	if w.Header().Get("ETag") == etag {
+		t.Error("ETag did not change with the data")
+	}
+}
@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+const (
+	mimeCSV = "text/csv"
+	mimeTSV = "text/tab-separated-values"
+)
+
+// exportFormat is one serialization render can produce. Tabular formats carry
+// the field delimiter; JSON has none.
+type exportFormat struct {
+	name         string
+	mime         string
+	delimiter    rune
+	decimalComma bool
+}
+
+// exportFormats lists the supported formats, the default first
+var exportFormats = []exportFormat{
+	{name: "json", mime: gin.MIMEJSON},
+	{name: "csv", mime: mimeCSV, delimiter: ','},
+	{name: "tsv", mime: mimeTSV, delimiter: '\t'},
+}
+
+// column extracts one field of a row for tabular output
+type column struct {
+	header string
+	value  func(row interface{}) string
+}
+
+// exportColumns maps each row type that can be rendered as CSV or TSV to its columns
+var exportColumns = map[reflect.Type][]column{
+	reflect.TypeOf(models.CountryRevenue{}): {
+		{"country", func(row interface{}) string { return row.(models.CountryRevenue).Country }},
+		{"revenue", func(row interface{}) string { return row.(models.CountryRevenue).Revenue.Format() }},
+		{"order_count", func(row interface{}) string { return strconv.FormatInt(row.(models.CountryRevenue).Orders, 10) }},
+		{"percentage", func(row interface{}) string { return formatDecimal(row.(models.CountryRevenue).Percentage) }},
+	},
+	reflect.TypeOf(models.ProductRevenue{}): {
+		{"product", func(row interface{}) string { return row.(models.ProductRevenue).Product }},
+		{"units", func(row interface{}) string { return strconv.FormatInt(row.(models.ProductRevenue).Units, 10) }},
+		{"revenue", func(row interface{}) string { return row.(models.ProductRevenue).Revenue.Format() }},
+	},
+	reflect.TypeOf(models.Transaction{}): {
+		{"id", func(row interface{}) string { return strconv.FormatUint(uint64(row.(models.Transaction).ID), 10) }},
+		{"order_id", func(row interface{}) string { return row.(models.Transaction).OrderID }},
+		{"customer_id", func(row interface{}) string { return row.(models.Transaction).CustomerID }},
+		{"transaction_date", func(row interface{}) string {
+			return row.(models.Transaction).TransactionDate.UTC().Format(time.RFC3339)
+		}},
+		{"country", func(row interface{}) string { return row.(models.Transaction).Country }},
+		{"region", func(row interface{}) string { return row.(models.Transaction).Region }},
+		{"product", func(row interface{}) string { return row.(models.Transaction).Product }},
+		{"category", func(row interface{}) string { return row.(models.Transaction).Category }},
+		{"quantity", func(row interface{}) string { return strconv.Itoa(row.(models.Transaction).Quantity) }},
+		{"revenue", func(row interface{}) string { return row.(models.Transaction).Revenue.Format() }},
+		{"currency", func(row interface{}) string { return row.(models.Transaction).Currency }},
+	},
+}
+
+// decimalColumns are the export columns, of whichever row type, holding decimal
+// numbers written with a dot, which comma-decimal exports rewrite
+var decimalColumns = map[string]bool{
+	"revenue":    true,
+	"percentage": true,
+}
+
+// tableFlushRows is how many streamed records are buffered before they are
+// pushed to the client
+const tableFlushRows = 500
+
+// render writes the response in the format picked by ?format= or, failing that,
+// the Accept header. JSON, the default, serializes data with an ETag; CSV and TSV
+// are attachments named after name with one record per element of the rows slice,
+// whose element type must be registered in exportColumns, and decimals written
+// with the decimal separator. Any other format gets 406.
+func render(c *gin.Context, name string, data interface{}, rows interface{}, decimal string) {
+	format, ok := negotiateFormat(c)
+	if !ok {
+		names := make([]string, len(exportFormats))
+		for i, f := range exportFormats {
+			names[i] = f.name
+		}
+		respondError(c, http.StatusNotAcceptable, models.ErrCodeNotAcceptable,
+			"Unsupported response format: supported formats are "+strings.Join(names, ", "))
+		return
+	}
+
+	if format.delimiter == 0 {
+		respondJSONWithETag(c, data)
+		return
+	}
+	writeTable(c, name, format.withDecimal(decimal), rows)
+}
+
+// negotiateFormat picks the response format, reporting false when the client
+// asked only for formats that are not supported
+func negotiateFormat(c *gin.Context) (exportFormat, bool) {
+	if name := c.Query("format"); name != "" {
+		return exportFormatNamed(name)
+	}
+
+	offers := make([]string, len(exportFormats))
+	for i, f := range exportFormats {
+		offers[i] = f.mime
+	}
+	mime := c.NegotiateFormat(offers...)
+	for _, f := range exportFormats {
+		if f.mime == mime {
+			return f, true
+		}
+	}
+	return exportFormat{}, false
+}
+
+// exportFormatNamed looks up one of exportFormats by its format= name
+func exportFormatNamed(name string) (exportFormat, bool) {
+	for _, f := range exportFormats {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return exportFormat{}, false
+}
+
+// withDecimal returns the format writing decimals with the named separator, one of
+// config.DecimalDot and config.DecimalComma. Comma decimals move CSV fields to
+// semicolons so the separator and the delimiter never collide.
+func (f exportFormat) withDecimal(separator string) exportFormat {
+	if separator != config.DecimalComma || f.delimiter == 0 {
+		return f
+	}
+	f.decimalComma = true
+	if f.delimiter == ',' {
+		f.delimiter = ';'
+	}
+	return f
+}
+
+// writeTable streams rows as a delimited attachment straight to the response writer
+func writeTable(c *gin.Context, name string, format exportFormat, rows interface{}) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		respondInternalError(c, fmt.Errorf("render %s: rows must be a slice, got %T", name, rows), "Failed to encode response")
+		return
+	}
+	table, err := newTableWriter(c, name, format, v.Type().Elem())
+	if err != nil {
+		respondInternalError(c, err, "Failed to encode response")
+		return
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := table.write(v.Index(i).Interface()); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	if err := table.close(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// tableWriter writes rows of one registered type as a delimited attachment. The
+// headers and the header record are only sent with the first row, or on close,
+// so the caller can still answer with an error until then.
+type tableWriter struct {
+	c       *gin.Context
+	name    string
+	format  exportFormat
+	columns []column
+	csv     *csv.Writer
+	record  []string
+	rows    int
+}
+
+func newTableWriter(c *gin.Context, name string, format exportFormat, rowType reflect.Type) (*tableWriter, error) {
+	columns, ok := exportColumns[rowType]
+	if !ok {
+		return nil, fmt.Errorf("render %s: no export columns for %s", name, rowType)
+	}
+	return &tableWriter{c: c, name: name, format: format, columns: columns, record: make([]string, len(columns))}, nil
+}
+
+// started reports whether the response has been committed
+func (t *tableWriter) started() bool {
+	return t.csv != nil
+}
+
+func (t *tableWriter) start() {
+	t.c.Header("Content-Type", t.format.mime+"; charset=utf-8")
+	t.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", t.name, t.format.name))
+	t.c.Status(http.StatusOK)
+
+	t.csv = csv.NewWriter(t.c.Writer)
+	t.csv.Comma = t.format.delimiter
+	for i, col := range t.columns {
+		t.record[i] = col.header
+	}
+	_ = t.csv.Write(t.record)
+}
+
+// write adds one record, pushing buffered records to the client every tableFlushRows rows
+func (t *tableWriter) write(row interface{}) error {
+	if !t.started() {
+		t.start()
+	}
+	for i, col := range t.columns {
+		t.record[i] = col.value(row)
+		if t.format.decimalComma && decimalColumns[col.header] {
+			t.record[i] = strings.Replace(t.record[i], ".", ",", 1)
+		}
+	}
+	if err := t.csv.Write(t.record); err != nil {
+		return err
+	}
+
+	t.rows++
+	if t.rows%tableFlushRows == 0 {
+		t.csv.Flush()
+		t.c.Writer.Flush()
+		return t.csv.Error()
+	}
+	return nil
+}
+
+// close sends whatever is still buffered, starting the response first when no row was written
+func (t *tableWriter) close() error {
+	if !t.started() {
+		t.start()
+	}
+	t.csv.Flush()
+	return t.csv.Error()
+}
+
+// formatDecimal renders a number in plain decimal form, never in scientific notation
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
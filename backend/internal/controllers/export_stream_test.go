@@ -0,0 +1,54 @@
+package controllers_test
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestExportTransactionsStreamsThousandsOfRows(t *testing.T) {
+	const n = 3000
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]models.Transaction, n)
+	for i := range rows {
+		rows[i] = models.Transaction{
+			OrderID:         fmt.Sprintf("ORD-%05d", i+1),
+			TransactionDate: start.Add(time.Duration(i) * time.Minute),
+			Country:         "US",
+			Region:          "Texas",
+			Product:         "Widget",
+			Quantity:        1,
+			Revenue:         money("9.99"),
+		}
+	}
+	db := newSQLiteDB(t)
+	if err := db.CreateInBatches(&rows, 500).Error; err != nil {
+		t.Fatalf("insert rows: %v", err)
+	}
+
+	w := get(newSQLiteController(db).ExportTransactions, "/transactions/export", "/transactions/export")
+	assertStatus(t, w, http.StatusOK)
+	if !w.Flushed {
+		t.Error("export was not flushed while streaming")
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("parse export: %v", err)
+	}
+	if len(records) != n+1 {
+		t.Fatalf("export holds %d records, want a header and %d rows", len(records), n)
+	}
+	distinct := map[string]bool{}
+	for _, record := range records[1:] {
+		distinct[strings.Join(record, ",")] = true
+	}
+	if len(distinct) != n {
+		t.Errorf("export holds %d distinct rows, want %d", len(distinct), n)
+	}
+}
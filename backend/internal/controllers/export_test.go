@@ -0,0 +1,201 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestCountryRevenueCSVMatchesJSON(t *testing.T) {
+	service := &controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(ctx context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error) {
+			return []models.CountryRevenue{
+				{Country: "United States", Revenue: money("12345678901.25"), Orders: 1200000, Percentage: 99.9999988},
+				{Country: "Côte d'Ivoire, \"CI\"", Revenue: money("0.15"), Orders: 1, Percentage: 0.0000012},
+			}, false, nil
+		},
+	}
+	handler := newTestController(service).GetCountryRevenue
+
+	jsonResp := get(handler, "/country-revenue", "/country-revenue?format=json")
+	assertStatus(t, jsonResp, 200)
+	var want []models.CountryRevenue
+	decodeJSON(t, jsonResp, &want)
+
+	csvResp := get(handler, "/country-revenue", "/country-revenue?format=csv")
+	assertStatus(t, csvResp, 200)
+	if ct := csvResp.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	records, err := csv.NewReader(csvResp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+
+	header := []string{"country", "revenue", "order_count", "percentage"}
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(header, ",") {
+		t.Fatalf("header = %v, want %v", records, header)
+	}
+	rows := records[1:]
+	if len(rows) != len(want) {
+		t.Fatalf("got %d CSV rows, want %d", len(rows), len(want))
+	}
+
+	for i, row := range rows {
+		for _, field := range row[1:] {
+			if strings.ContainsAny(field, "eE") {
+				t.Errorf("row %d: %q is in scientific notation", i, field)
+			}
+		}
+
+		if row[0] != want[i].Country {
+			t.Errorf("row %d: country = %q, want %q", i, row[0], want[i].Country)
+		}
+		if row[1] != want[i].Revenue.Format() {
+			t.Errorf("row %d: revenue = %q, want %q", i, row[1], want[i].Revenue.Format())
+		}
+		if orders, err := strconv.ParseInt(row[2], 10, 64); err != nil || orders != want[i].Orders {
+			t.Errorf("row %d: orders = %q, want %d", i, row[2], want[i].Orders)
+		}
+		if percentage, err := strconv.ParseFloat(row[3], 64); err != nil || percentage != want[i].Percentage {
+			t.Errorf("row %d: percentage = %q, want %v", i, row[3], want[i].Percentage)
+		}
+	}
+}
+
+func TestRenderNegotiatesFormat(t *testing.T) {
+	controller := newTestController(&controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(context.Context, models.CountryFilter, models.Conversion) ([]models.CountryRevenue, bool, error) {
+			return []models.CountryRevenue{{Country: "US", Revenue: money("10"), Orders: 1, Percentage: 100}}, false, nil
+		},
+		GetTopProductsFunc: func(context.Context, models.ProductFilter, models.ProductSort, int, int, models.Conversion) (*models.ProductRevenuePage, bool, error) {
+			return &models.ProductRevenuePage{Data: []models.ProductRevenue{{Product: "Widget", Revenue: money("10")}}, Total: 1}, false, nil
+		},
+	})
+	endpoints := []struct {
+		route   string
+		handler gin.HandlerFunc
+	}{
+		{"/country-revenue", controller.GetCountryRevenue},
+		{"/top-products", controller.GetTopProducts},
+	}
+	tests := []struct {
+		name        string
+		query       string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "JSON by default", status: http.StatusOK, contentType: "application/json"},
+		{name: "JSON by parameter", query: "?format=json", accept: "text/csv", status: http.StatusOK, contentType: "application/json"},
+		{name: "CSV by Accept", accept: "text/csv", status: http.StatusOK, contentType: "text/csv"},
+		{name: "CSV by parameter", query: "?format=csv", status: http.StatusOK, contentType: "text/csv"},
+		{name: "TSV by Accept", accept: "text/tab-separated-values", status: http.StatusOK, contentType: "text/tab-separated-values"},
+		{name: "unsupported parameter", query: "?format=xml", status: http.StatusNotAcceptable},
+		{name: "unsupported Accept", accept: "application/xml", status: http.StatusNotAcceptable},
+	}
+	for _, endpoint := range endpoints {
+		for _, tt := range tests {
+			t.Run(endpoint.route+" "+tt.name, func(t *testing.T) {
+				var headers []string
+				if tt.accept != "" {
+					headers = []string{"Accept", tt.accept}
+				}
+				w := get(endpoint.handler, endpoint.route, endpoint.route+tt.query, headers...)
+				assertStatus(t, w, tt.status)
+
+				if tt.status == http.StatusNotAcceptable {
+					var body models.ErrorResponse
+					decodeJSON(t, w, &body)
+					if body.Code != models.ErrCodeNotAcceptable {
+						t.Errorf("code = %q, want %q", body.Code, models.ErrCodeNotAcceptable)
+					}
+					return
+				}
+				if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+					t.Errorf("Content-Type = %q, want %s", ct, tt.contentType)
+				}
+				if !strings.Contains(w.Body.String(), "10") {
+					t.Errorf("body %q is missing the revenue", w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestExportDecimalLocalesParseBack(t *testing.T) {
+	rows := []models.CountryRevenue{
+		{Country: "Deutschland; DE", Revenue: money("1234567.89"), Orders: 42, Percentage: 87.5},
+		{Country: "France, \"FR\"", Revenue: money("0.05"), Orders: 1, Percentage: 12.5},
+	}
+	service := &controllertest.MockAnalyticsService{
+		GetCountryRevenueFunc: func(context.Context, models.CountryFilter, models.Conversion) ([]models.CountryRevenue, bool, error) {
+			return rows, false, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		configured string
+		query      string
+		delimiter  rune
+		separator  string
+	}{
+		{name: "dot by default", configured: config.DecimalDot, query: "format=csv", delimiter: ',', separator: "."},
+		{name: "comma by parameter", configured: config.DecimalDot, query: "format=csv&decimal=comma", delimiter: ';', separator: ","},
+		{name: "comma by config", configured: config.DecimalComma, query: "format=csv", delimiter: ';', separator: ","},
+		{name: "parameter overrides config", configured: config.DecimalComma, query: "format=csv&decimal=dot", delimiter: ',', separator: "."},
+		{name: "comma TSV keeps tabs", configured: config.DecimalDot, query: "format=tsv&decimal=comma", delimiter: '\t', separator: ","},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.CSVDecimal = tt.configured
+			handler := controllers.NewAnalyticsController(service, cfg, nil).GetCountryRevenue
+
+			w := get(handler, "/country-revenue", "/country-revenue?"+tt.query)
+			assertStatus(t, w, http.StatusOK)
+			reader := csv.NewReader(w.Body)
+			reader.Comma = tt.delimiter
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("parse export: %v", err)
+			}
+			if len(records) != len(rows)+1 {
+				t.Fatalf("got %d records, want a header and %d rows", len(records), len(rows))
+			}
+
+			for i, record := range records[1:] {
+				want := rows[i]
+				if record[0] != want.Country {
+					t.Errorf("row %d: country = %q, want %q", i, record[0], want.Country)
+				}
+				for _, field := range []string{record[1], record[3]} {
+					if other := strings.Trim(".,", tt.separator); strings.Contains(field, other) {
+						t.Errorf("row %d: %q uses %q as decimal separator, want %q", i, field, other, tt.separator)
+					}
+				}
+				if revenue := strings.Replace(record[1], tt.separator, ".", 1); revenue != want.Revenue.Format() {
+					t.Errorf("row %d: revenue = %q, want %s", i, record[1], want.Revenue.Format())
+				}
+				if orders, err := strconv.ParseInt(record[2], 10, 64); err != nil || orders != want.Orders {
+					t.Errorf("row %d: order_count = %q, want %d", i, record[2], want.Orders)
+				}
+				percentage, err := strconv.ParseFloat(strings.Replace(record[3], tt.separator, ".", 1), 64)
+				if err != nil || percentage != want.Percentage {
+					t.Errorf("row %d: percentage = %q, want %v", i, record[3], want.Percentage)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestFiltersMatchStoredValuesIgnoringSpaceAndCase(t *testing.T) {
+	sale := func(country, region, product, revenue string) models.Transaction {
+		return models.Transaction{
+			TransactionDate: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+			Country:         country,
+			Region:          region,
+			Product:         product,
+			Quantity:        1,
+			Revenue:         money(revenue),
+		}
+	}
+	controller := newSQLiteController(newSQLiteDB(t,
+		sale("US", "Texas", "Widget", "10"),
+		sale("US", "Texas", "Gadget", "20"),
+		sale("DE", "Bavaria", "Widget", "40"),
+	))
+
+	for _, value := range []string{"US", " us ", "us", "\tUs\n"} {
+		t.Run(url.QueryEscape(value), func(t *testing.T) {
+			filter := url.QueryEscape(value)
+
+			w := get(controller.GetCountryRevenue, "/country-revenue", "/country-revenue?country="+filter)
+			assertStatus(t, w, http.StatusOK)
+			var countries []models.CountryRevenue
+			decodeJSON(t, w, &countries)
+			if len(countries) != 1 || countries[0].Country != "US" || countries[0].Orders != 2 {
+				t.Errorf("country revenue = %+v, want the 2 stored US orders", countries)
+			}
+
+			w = get(controller.GetTopProducts, "/top-products", "/top-products?country="+filter)
+			assertStatus(t, w, http.StatusOK)
+			var products models.ProductRevenuePage
+			decodeJSON(t, w, &products)
+			if products.Total != 2 {
+				t.Errorf("top products total = %d, want the 2 products sold in US", products.Total)
+			}
+
+			w = get(controller.ListTransactions, "/transactions", "/transactions?country="+filter)
+			assertStatus(t, w, http.StatusOK)
+			var transactions struct {
+				Total int64 `json:"total"`
+			}
+			decodeJSON(t, w, &transactions)
+			if transactions.Total != 2 {
+				t.Errorf("transactions total = %d, want 2", transactions.Total)
+			}
+
+			w = get(controller.GetCountryRegions, "/country/:country/regions", "/country/"+url.PathEscape(value)+"/regions")
+			assertStatus(t, w, http.StatusOK)
+			var regions []models.RegionRevenue
+			decodeJSON(t, w, &regions)
+			if len(regions) != 1 || regions[0].Region != "Texas" {
+				t.Errorf("regions = %+v, want Texas", regions)
+			}
+		})
+	}
+
+	// product and region filters fold the same way
+	w := get(controller.ListTransactions, "/transactions", "/transactions?product="+url.QueryEscape(" WIDGET ")+"&region="+url.QueryEscape("bavaria "))
+	assertStatus(t, w, http.StatusOK)
+	var transactions struct {
+		Total int64 `json:"total"`
+	}
+	decodeJSON(t, w, &transactions)
+	if transactions.Total != 1 {
+		t.Errorf("transactions total = %d, want the Bavarian widget", transactions.Total)
+	}
+}
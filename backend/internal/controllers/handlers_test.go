@@ -0,0 +1,120 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/services"
+)
+
+// testConfig returns the settings the handlers read
+func testConfig() *config.Config {
+	return &config.Config{
+		APIBasePath:  "/api/v1",
+		MaxPageLimit: 100,
+		CSVDecimal:   config.DecimalDot,
+		Rates:        map[string]float64{"USD": 1, "EUR": 0.5},
+		BaseCurrency: "USD",
+	}
+}
+
+func newTestController(service *controllertest.MockAnalyticsService) *controllers.AnalyticsController {
+	return controllers.NewAnalyticsController(service, testConfig(), nil)
+}
+
+// newSQLiteDB opens a migrated in-memory SQLite database holding rows, on a
+// single connection since every connection to :memory: is a database of its own
+func newSQLiteDB(t *testing.T, rows ...models.Transaction) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("access pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if len(rows) > 0 {
+		if err := db.Create(&rows).Error; err != nil {
+			t.Fatalf("insert rows: %v", err)
+		}
+	}
+	return db
+}
+
+// newSQLiteController returns a controller over the real service and repository
+// on db, with an in-memory cache
+func newSQLiteController(db *gorm.DB) *controllers.AnalyticsController {
+	cfg := testConfig()
+	repo := repository.NewAnalyticsRepository(db, cfg.Rates)
+	service := services.NewAnalyticsService(repo, cache.NewMemoryCache(), cfg)
+	return controllers.NewAnalyticsController(service, cfg, nil)
+}
+
+// serve routes one request for target through handler mounted at route. headers
+// holds alternating header names and values.
+func serve(handler gin.HandlerFunc, method, route, target string, body string, headers ...string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, route, handler)
+
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// get is serve for a GET request without a body
+func get(handler gin.HandlerFunc, route, target string, headers ...string) *httptest.ResponseRecorder {
+	return serve(handler, http.MethodGet, route, target, "", headers...)
+}
+
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder, dest interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), dest); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+}
+
+func assertStatus(t *testing.T, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Fatalf("status = %d, want %d; body %s", w.Code, want, w.Body.String())
+	}
+}
+
+func money(amount string) models.Money {
+	return models.NewMoney(decimal.RequireFromString(amount))
+}
@@ -0,0 +1,151 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/health"
+	"abt-analytics/internal/models"
+)
+
+func TestHealthCheckAggregatesDependencies(t *testing.T) {
+	// checker returns a dependency probe that fails when down is set
+	checker := func(name string, critical, down bool) health.Checker {
+		return health.NewChecker(name, critical, func(context.Context) error {
+			if down {
+				return errors.New(name + " unreachable")
+			}
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name              string
+		dbDown, cacheDown bool
+		code              int
+		status            string
+	}{
+		{name: "healthy", code: http.StatusOK, status: health.StatusHealthy},
+		{name: "cache down", cacheDown: true, code: http.StatusOK, status: health.StatusDegraded},
+		{name: "database down", dbDown: true, code: http.StatusServiceUnavailable, status: health.StatusUnhealthy},
+		{name: "both down", dbDown: true, cacheDown: true, code: http.StatusServiceUnavailable, status: health.StatusUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkers := []health.Checker{
+				checker("database", true, tt.dbDown),
+				checker("cache", false, tt.cacheDown),
+			}
+			controller := controllers.NewAnalyticsController(&controllertest.MockAnalyticsService{}, testConfig(), checkers)
+
+			w := get(controller.HealthCheck, "/health", "/health")
+			assertStatus(t, w, tt.code)
+			var body models.HealthResponse
+			decodeJSON(t, w, &body)
+			if body.Status != tt.status {
+				t.Errorf("status = %q, want %q", body.Status, tt.status)
+			}
+
+			want := map[string]bool{"database": tt.dbDown, "cache": tt.cacheDown}
+			if len(body.Checks) != len(want) {
+				t.Fatalf("checks = %+v, want database and cache", body.Checks)
+			}
+			for _, check := range body.Checks {
+				down, ok := want[check.Name]
+				switch {
+				case !ok:
+					t.Errorf("unexpected check %q", check.Name)
+				case down && (check.Status != health.StatusDown || check.Error == ""):
+					t.Errorf("%s = %+v, want down with its error", check.Name, check)
+				case !down && (check.Status != health.StatusUp || check.Error != ""):
+					t.Errorf("%s = %+v, want up", check.Name, check)
+				}
+				if check.LatencyMs < 0 {
+					t.Errorf("%s latency = %v", check.Name, check.LatencyMs)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessHealthyDatabase(t *testing.T) {
+	handler := newSQLiteController(newSQLiteDB(t)).Readiness
+
+	w := get(handler, "/ready", "/ready")
+	assertStatus(t, w, http.StatusOK)
+	var body models.ReadinessResponse
+	decodeJSON(t, w, &body)
+	if body.Status != "ready" {
+		t.Errorf("status = %q, want ready", body.Status)
+	}
+}
+
+func TestReadinessClosedDatabase(t *testing.T) {
+	db := newSQLiteDB(t)
+	handler := newSQLiteController(db).Readiness
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+
+	w := get(handler, "/ready", "/ready")
+	assertStatus(t, w, http.StatusServiceUnavailable)
+	var body struct {
+		models.ErrorResponse
+		Details models.DependencyError `json:"details"`
+	}
+	decodeJSON(t, w, &body)
+	if body.Code != models.ErrCodeServiceUnavailable {
+		t.Errorf("code = %q, want %q", body.Code, models.ErrCodeServiceUnavailable)
+	}
+	if body.Details.Dependency != "database" || body.Details.Error == "" {
+		t.Errorf("details = %+v, want the failed database dependency", body.Details)
+	}
+}
+
+func TestMigrationStatusOnSQLite(t *testing.T) {
+	migrated := newSQLiteDB(t)
+	unmigrated, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if sqlDB, err := unmigrated.DB(); err == nil {
+		t.Cleanup(func() { sqlDB.Close() })
+	}
+
+	tests := []struct {
+		name   string
+		db     *gorm.DB
+		status int
+		want   models.MigrationStatus
+	}{
+		{name: "migrated", db: migrated, status: http.StatusOK, want: models.MigrationStatus{Migrated: true, Missing: []models.SchemaGap{}}},
+		{
+			name:   "not migrated",
+			db:     unmigrated,
+			status: http.StatusServiceUnavailable,
+			want:   models.MigrationStatus{Missing: []models.SchemaGap{{Table: "transactions"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(newSQLiteController(tt.db).MigrationStatus, "/health/migrations", "/health/migrations")
+			assertStatus(t, w, tt.status)
+			var got models.MigrationStatus
+			decodeJSON(t, w, &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("status = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
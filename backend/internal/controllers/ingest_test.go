@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+func TestIngestTransactions(t *testing.T) {
+	const (
+		valid     = `{"transaction_date":"2024-03-01T10:00:00Z","country":"US","region":"Texas","product":"Widget","revenue":"19.99"}`
+		noCountry = `{"transaction_date":"2024-03-01T10:00:00Z","region":"Texas","product":"Widget","revenue":"5"}`
+		negative  = `{"transaction_date":"2024-03-01T10:00:00Z","country":"US","region":"Texas","product":"Widget","revenue":"-1"}`
+	)
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		inserted int
+		rows     []models.RowError
+	}{
+		{name: "valid batch", body: "[" + valid + "," + valid + "," + valid + "]", status: http.StatusCreated, inserted: 3},
+		{
+			name:   "partially invalid batch",
+			body:   "[" + valid + "," + noCountry + "," + valid + "," + negative + "]",
+			status: http.StatusUnprocessableEntity,
+			rows: []models.RowError{
+				{Index: 1, Field: "country"},
+				{Index: 3, Field: "revenue"},
+			},
+		},
+		{name: "empty array", body: "[]", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newSQLiteDB(t)
+			controller := newSQLiteController(db)
+
+			w := serve(controller.IngestTransactions, http.MethodPost, "/transactions/batch", "/transactions/batch", tt.body)
+			assertStatus(t, w, tt.status)
+
+			var stored int64
+			if err := db.Model(&models.Transaction{}).Count(&stored).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored != int64(tt.inserted) {
+				t.Errorf("%d rows stored, want %d", stored, tt.inserted)
+			}
+
+			switch tt.status {
+			case http.StatusCreated:
+				var result models.BatchInsertResult
+				decodeJSON(t, w, &result)
+				if result.Inserted != tt.inserted {
+					t.Errorf("inserted = %d, want %d", result.Inserted, tt.inserted)
+				}
+			case http.StatusUnprocessableEntity:
+				var body struct {
+					Code    string            `json:"code"`
+					Details []models.RowError `json:"details"`
+				}
+				decodeJSON(t, w, &body)
+				if body.Code != models.ErrCodeValidationFailed || len(body.Details) != len(tt.rows) {
+					t.Fatalf("got %s with %+v, want %s on %+v", body.Code, body.Details, models.ErrCodeValidationFailed, tt.rows)
+				}
+				for i, want := range tt.rows {
+					if got := body.Details[i]; got.Index != want.Index || got.Field != want.Field {
+						t.Errorf("row error %d = %d/%s, want %d/%s", i, got.Index, got.Field, want.Index, want.Field)
+					}
+				}
+			}
+		})
+	}
+}
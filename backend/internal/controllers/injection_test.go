@@ -0,0 +1,78 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestInjectionPayloadsAreRejected(t *testing.T) {
+	payloads := []string{
+		"revenue; DROP TABLE transactions",
+		"revenue DESC, (SELECT 1)",
+		"units--",
+		"revenue' OR '1'='1",
+	}
+	db := newSQLiteDB(t,
+		models.Transaction{TransactionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Country: "US", Region: "CA", Product: "Widget", Quantity: 1, Revenue: money("10")},
+		models.Transaction{TransactionDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Country: "DE", Region: "Bavaria", Product: "Gadget", Quantity: 2, Revenue: money("20")},
+	)
+	handler := newSQLiteController(db).GetTopProducts
+
+	for _, param := range []string{"sort", "dir"} {
+		for _, payload := range payloads {
+			t.Run(param+" "+payload, func(t *testing.T) {
+				w := get(handler, "/top-products", "/top-products?"+param+"="+url.QueryEscape(payload))
+				assertStatus(t, w, http.StatusUnprocessableEntity)
+				var body struct {
+					Code    string              `json:"code"`
+					Details []models.FieldError `json:"details"`
+				}
+				decodeJSON(t, w, &body)
+				if body.Code != models.ErrCodeValidationFailed || len(body.Details) != 1 || body.Details[0].Field != param {
+					t.Errorf("%s: details = %+v, want one error on %s", body.Code, body.Details, param)
+				}
+			})
+		}
+	}
+
+	// a payload in a filter is a bound value that matches nothing
+	for _, payload := range payloads {
+		t.Run("country "+payload, func(t *testing.T) {
+			w := get(handler, "/top-products", "/top-products?country="+url.QueryEscape(payload))
+			assertStatus(t, w, http.StatusOK)
+			var page models.ProductRevenuePage
+			decodeJSON(t, w, &page)
+			if page.Total != 0 {
+				t.Errorf("total = %d, want no products", page.Total)
+			}
+		})
+	}
+
+	var rows int64
+	if err := db.Model(&models.Transaction{}).Count(&rows).Error; err != nil || rows != 2 {
+		t.Errorf("after the payloads: %d rows, %v; want the 2 rows untouched", rows, err)
+	}
+}
+
+func TestUnknownSortFieldFromTheRepositoryIs400(t *testing.T) {
+	handler := newTestController(&controllertest.MockAnalyticsService{
+		GetTopProductsFunc: func(_ context.Context, _ models.ProductFilter, sort models.ProductSort, _, _ int, _ models.Conversion) (*models.ProductRevenuePage, bool, error) {
+			return nil, false, fmt.Errorf("%w %q", models.ErrUnknownSortField, sort.By)
+		},
+	}).GetTopProducts
+
+	w := get(handler, "/top-products", "/top-products")
+	assertStatus(t, w, http.StatusBadRequest)
+	var body models.ErrorResponse
+	decodeJSON(t, w, &body)
+	if body.Code != models.ErrCodeInvalidParameter {
+		t.Errorf("code = %q, want %q", body.Code, models.ErrCodeInvalidParameter)
+	}
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// TotalCountHeader carries the total number of rows of a paginated listing, for
+// clients that read it from the headers rather than the body
+const TotalCountHeader = "X-Total-Count"
+
+// setTotalCountHeader reports the total of a paginated listing in TotalCountHeader
+func setTotalCountHeader(c *gin.Context, total int64) {
+	c.Header(TotalCountHeader, strconv.FormatInt(total, 10))
+}
+
+// offsetPageLinks links the current page and its neighbours for offset pagination.
+// next is omitted on the last page and prev on the first; prev never goes below offset 0.
+func offsetPageLinks(c *gin.Context, limit, offset int, total int64) *models.PageLinks {
+	links := &models.PageLinks{Self: pageURL(c, nil)}
+	if int64(offset+limit) < total {
+		links.Next = pageURL(c, map[string]string{"offset": strconv.Itoa(offset + limit), "limit": strconv.Itoa(limit)})
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links.Prev = pageURL(c, map[string]string{"offset": strconv.Itoa(prev), "limit": strconv.Itoa(limit)})
+	}
+	return links
+}
+
+// cursorPageLinks links the current page and, unless it is the last, the page
+// after it. Keyset pages cannot be walked backwards, so prev is always omitted.
+func cursorPageLinks(c *gin.Context, limit int, nextCursor string) *models.PageLinks {
+	links := &models.PageLinks{Self: pageURL(c, nil)}
+	if nextCursor != "" {
+		links.Next = pageURL(c, map[string]string{"cursor": nextCursor, "limit": strconv.Itoa(limit), "offset": ""})
+	}
+	return links
+}
+
+// pageURL returns the request's path and query with the given parameters replaced;
+// an empty value removes the parameter. Every other query parameter is kept.
+func pageURL(c *gin.Context, set map[string]string) string {
+	query := c.Request.URL.Query()
+	for name, value := range set {
+		if value == "" {
+			query.Del(name)
+		} else {
+			query.Set(name, value)
+		}
+	}
+
+	link := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	return link.String()
+}
@@ -0,0 +1,86 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestOffsetPageLinks(t *testing.T) {
+	const total = 25
+	controller := newTestController(&controllertest.MockAnalyticsService{
+		GetTopProductsFunc: func(_ context.Context, _ models.ProductFilter, _ models.ProductSort, limit, offset int, _ models.Conversion) (*models.ProductRevenuePage, bool, error) {
+			return &models.ProductRevenuePage{Data: []models.ProductRevenue{}, Total: total, Limit: limit, Offset: offset}, false, nil
+		},
+		ListTransactionsFunc: func(_ context.Context, _ models.TransactionFilter, _ *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error) {
+			return &models.TransactionPage{Data: []models.TransactionResponse{}, Total: total, Limit: limit, Offset: offset}, nil
+		},
+	})
+	endpoints := []struct {
+		path    string
+		handler gin.HandlerFunc
+	}{
+		{"/top-products", controller.GetTopProducts},
+		{"/transactions", controller.ListTransactions},
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  models.PageLinks
+	}{
+		{
+			name:  "first page",
+			query: "?limit=10&country=US",
+			want:  models.PageLinks{Self: "?country=US&limit=10", Next: "?country=US&limit=10&offset=10"},
+		},
+		{
+			name:  "middle page",
+			query: "?country=US&limit=10&offset=10",
+			want: models.PageLinks{
+				Self: "?country=US&limit=10&offset=10",
+				Next: "?country=US&limit=10&offset=20",
+				Prev: "?country=US&limit=10&offset=0",
+			},
+		},
+		{
+			name:  "last page",
+			query: "?country=US&limit=10&offset=20",
+			want:  models.PageLinks{Self: "?country=US&limit=10&offset=20", Prev: "?country=US&limit=10&offset=10"},
+		},
+		{
+			name:  "prev stops at the start",
+			query: "?limit=10&offset=5",
+			want:  models.PageLinks{Self: "?limit=10&offset=5", Next: "?limit=10&offset=15", Prev: "?limit=10&offset=0"},
+		},
+	}
+	for _, endpoint := range endpoints {
+		for _, tt := range tests {
+			t.Run(endpoint.path+" "+tt.name, func(t *testing.T) {
+				w := get(endpoint.handler, endpoint.path, endpoint.path+tt.query)
+				assertStatus(t, w, http.StatusOK)
+
+				var page struct {
+					Links *models.PageLinks `json:"links"`
+				}
+				decodeJSON(t, w, &page)
+				if page.Links == nil {
+					t.Fatalf("no links in %s", w.Body.String())
+				}
+				want := tt.want
+				for _, link := range []*string{&want.Self, &want.Next, &want.Prev} {
+					if *link != "" {
+						*link = endpoint.path + *link
+					}
+				}
+				if *page.Links != want {
+					t.Errorf("links = %+v, want %+v", *page.Links, want)
+				}
+			})
+		}
+	}
+}
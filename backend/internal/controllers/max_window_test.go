@@ -0,0 +1,58 @@
+package controllers_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/services"
+)
+
+func TestMaxQueryDaysLimitsScanningEndpoints(t *testing.T) {
+	db := newSQLiteDB(t)
+	cfg := testConfig()
+	cfg.MaxQueryDays = 31
+	service := services.NewAnalyticsService(repository.NewAnalyticsRepository(db, cfg.Rates), cache.NewMemoryCache(), cfg)
+	controller := controllers.NewAnalyticsController(service, cfg, nil)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		want    int
+		fields  []string
+	}{
+		{name: "a window of the maximum", handler: controller.ListTransactions, target: "/?from=2024-01-01&to=2024-01-31", want: http.StatusOK},
+		{name: "one day too many", handler: controller.ListTransactions, target: "/?from=2024-01-01&to=2024-02-01", want: http.StatusUnprocessableEntity, fields: []string{"to"}},
+		{name: "open-ended window", handler: controller.ListTransactions, target: "/?from=2024-01-01", want: http.StatusUnprocessableEntity, fields: []string{"to"}},
+		{name: "no window", handler: controller.ExportTransactions, target: "/", want: http.StatusUnprocessableEntity, fields: []string{"from", "to"}},
+		{name: "tightens the daily revenue limit", handler: controller.GetDailyRevenue, target: "/?from=2024-01-01&to=2024-03-31", want: http.StatusUnprocessableEntity, fields: []string{"to"}},
+		{name: "bounded aggregates opt out", handler: controller.GetCountryRevenue, target: "/", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.handler, "/", tt.target)
+			assertStatus(t, w, tt.want)
+			if tt.fields == nil {
+				return
+			}
+			var body struct {
+				Details []models.FieldError `json:"details"`
+			}
+			decodeJSON(t, w, &body)
+			got := make([]string, 0, len(body.Details))
+			for _, detail := range body.Details {
+				got = append(got, detail.Field)
+			}
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("errors on %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/controllers"
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestPageLimitsApplyPerEndpoint(t *testing.T) {
+	var requested int
+	cfg := testConfig()
+	cfg.PageLimits = map[string]config.PageLimits{config.PageTransactions: {Default: 25, Max: 200}}
+	controller := controllers.NewAnalyticsController(&controllertest.MockAnalyticsService{
+		GetTopProductsFunc: func(_ context.Context, _ models.ProductFilter, _ models.ProductSort, limit, offset int, _ models.Conversion) (*models.ProductRevenuePage, bool, error) {
+			requested = limit
+			return &models.ProductRevenuePage{Data: []models.ProductRevenue{}, Limit: limit, Offset: offset}, false, nil
+		},
+		GetTopCustomersFunc: func(_ context.Context, _ models.DateRange, limit, offset int, _ models.Conversion) (*models.CustomerRevenuePage, bool, error) {
+			requested = limit
+			return &models.CustomerRevenuePage{Data: []models.CustomerRevenue{}, Limit: limit, Offset: offset}, false, nil
+		},
+		ListTransactionsFunc: func(_ context.Context, _ models.TransactionFilter, _ *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error) {
+			requested = limit
+			return &models.TransactionPage{Data: []models.TransactionResponse{}, Limit: limit, Offset: offset}, nil
+		},
+	}, cfg, nil)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		query   string
+		status  int
+		limit   int
+	}{
+		{name: "top products default", handler: controller.GetTopProducts, status: http.StatusOK, limit: 10},
+		{name: "top customers default", handler: controller.GetTopCustomers, status: http.StatusOK, limit: 10},
+		{name: "configured transactions default", handler: controller.ListTransactions, status: http.StatusOK, limit: 25},
+		{name: "top products at MAX_PAGE_LIMIT", handler: controller.GetTopProducts, query: "?limit=100", status: http.StatusOK, limit: 100},
+		{name: "top products over MAX_PAGE_LIMIT", handler: controller.GetTopProducts, query: "?limit=101", status: http.StatusUnprocessableEntity},
+		{name: "transactions under their own max", handler: controller.ListTransactions, query: "?limit=200", status: http.StatusOK, limit: 200},
+		{name: "transactions over their own max", handler: controller.ListTransactions, query: "?limit=201", status: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = 0
+			w := get(tt.handler, "/", "/"+tt.query)
+			assertStatus(t, w, tt.status)
+			if requested != tt.limit {
+				t.Errorf("service asked for %d rows, want %d", requested, tt.limit)
+			}
+		})
+	}
+}
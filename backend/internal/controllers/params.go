@@ -0,0 +1,66 @@
+package controllers
+
+import "time"
+
+const (
+	dateOnlyLayout = "2006-01-02"
+
+	defaultTopRegions = 30
+	maxTopRegions     = 100
+
+	// Region trends carry a whole series per region, so fewer of them are returned
+	defaultTrendRegions = 5
+	maxTrendRegions     = 20
+
+	// healthCheckTimeout bounds each dependency probe of the health check
+	healthCheckTimeout = 2 * time.Second
+
+	// maxBatchSize bounds the number of transactions in a single ingestion request
+	maxBatchSize = 1000
+
+	// maxDailyRevenueDays bounds the number of days a daily revenue series may cover
+	maxDailyRevenueDays = 366
+
+	// compareYoY requests a year-over-year comparison on monthly sales
+	compareYoY = "yoy"
+
+	// maxConcentrationThresholds bounds the revenue shares one concentration request may ask about
+	maxConcentrationThresholds = 10
+
+	// Revenue forecasts project a few months ahead from the trend of the last few months
+	defaultForecastMonths = 3
+	maxForecastMonths     = 24
+	defaultForecastWindow = 6
+	minForecastWindow     = 2
+	maxForecastWindow     = 36
+)
+
+// defaultConcentrationThresholds are the revenue shares, in percent, revenue
+// concentration reports product counts for when the request names none
+var defaultConcentrationThresholds = []float64{50, 80, 95}
+
+// transactionListParams are the query parameters understood by ListTransactions
+var transactionListParams = []string{"country", "product", "region", "from", "to", "updated_since", "limit", "offset", "cursor"}
+
+// transactionExportParams are the query parameters understood by ExportTransactions
+var transactionExportParams = []string{"country", "product", "region", "from", "to", "format", "decimal"}
+
+// daysSpanned counts the UTC calendar days touched by the inclusive window from..to
+func daysSpanned(from, to time.Time) int {
+	from, to = from.UTC(), to.UTC()
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(last.Sub(first).Hours()/24) + 1
+}
+
+// parseDate accepts RFC3339 timestamps or plain YYYY-MM-DD dates (interpreted as UTC)
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(dateOnlyLayout, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
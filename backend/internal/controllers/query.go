@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+// queryParams reads a request's query parameters, recording every invalid value
+// instead of stopping at the first so that one response can report them all
+type queryParams struct {
+	c      *gin.Context
+	errors []models.FieldError
+}
+
+func newQueryParams(c *gin.Context) *queryParams {
+	return &queryParams{c: c}
+}
+
+// addError records a problem with the named parameter
+func (p *queryParams) addError(field, format string, args ...interface{}) {
+	p.errors = append(p.errors, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseIntParam returns the named integer, or defaultValue when it is absent or invalid.
+// Values outside [min, max] are recorded as errors.
+func (p *queryParams) parseIntParam(name string, defaultValue, min, max int) int {
+	value := p.c.Query(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil:
+		p.addError(name, "%q is not an integer", value)
+	case n < min:
+		p.addError(name, "must be at least %d", min)
+	case n > max:
+		p.addError(name, "must not exceed %d", max)
+	default:
+		return n
+	}
+	return defaultValue
+}
+
+// parseTopNParam returns an optional positive count, applying the default when
+// absent and capping the result at max rather than rejecting it
+func (p *queryParams) parseTopNParam(name string, defaultValue, max int) int {
+	n := p.parseIntParam(name, defaultValue, 1, math.MaxInt)
+	if n > max {
+		n = max
+	}
+	return n
+}
+
+// parseListParam returns every value of a repeatable parameter with duplicates
+// removed, recording empty values as errors
+func (p *queryParams) parseListParam(name string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, value := range p.c.QueryArray(name) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			p.addError(name, "must not be empty")
+			continue
+		}
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parsePercentagesParam returns the values of a repeatable percentage parameter,
+// each above 0 and at most 100, or defaults when it is absent. At most max values
+// are accepted; invalid ones are recorded as errors.
+func (p *queryParams) parsePercentagesParam(name string, defaults []float64, max int) []float64 {
+	values := p.parseListParam(name)
+	if len(values) == 0 {
+		return defaults
+	}
+	if len(values) > max {
+		p.addError(name, "must not be given more than %d times", max)
+		return defaults
+	}
+
+	percentages := make([]float64, 0, len(values))
+	for _, value := range values {
+		percentage, err := strconv.ParseFloat(value, 64)
+		switch {
+		case err != nil:
+			p.addError(name, "%q is not a number", value)
+		case percentage <= 0 || percentage > 100:
+			p.addError(name, "%q must be above 0 and at most 100", value)
+		default:
+			percentages = append(percentages, percentage)
+		}
+	}
+	return percentages
+}
+
+// parseFilterParam returns a dimension filter such as country or product trimmed
+// and case-folded. The repository matches dimensions ignoring case, so " us " and
+// "US" select the same rows and share a cache entry.
+func (p *queryParams) parseFilterParam(name string) string {
+	return foldFilter(p.c.Query(name))
+}
+
+// parseFilterListParam is parseFilterParam for a repeatable parameter, returning
+// the distinct folded values
+func (p *queryParams) parseFilterListParam(name string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, value := range p.parseListParam(name) {
+		value = foldFilter(value)
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func foldFilter(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// parseEnumParam returns the named value when it is one of allowed, or defaultValue
+// when it is absent or invalid
+func (p *queryParams) parseEnumParam(name, defaultValue string, allowed ...string) string {
+	value := p.c.Query(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	p.addError(name, "%q must be one of '%s'", value, strings.Join(allowed, "', '"))
+	return defaultValue
+}
+
+// parseDateParam parses an optional RFC3339 or YYYY-MM-DD value. With endOfDay a
+// date-only value covers the whole day so ranges ending on it stay inclusive.
+func (p *queryParams) parseDateParam(name string, endOfDay bool) *time.Time {
+	value := p.c.Query(name)
+	if value == "" {
+		return nil
+	}
+
+	t, dateOnly, err := parseDate(value)
+	if err != nil {
+		p.addError(name, "%q is not a valid date: expected RFC3339 or YYYY-MM-DD", value)
+		return nil
+	}
+	if dateOnly && endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t
+}
+
+// DefaultRangeFromHeader and DefaultRangeToHeader echo the bounds of the default
+// window applied to a request that gave neither from nor to
+const (
+	DefaultRangeFromHeader = "X-Default-Range-From"
+	DefaultRangeToHeader   = "X-Default-Range-To"
+)
+
+// defaultRangeKey is the context key of the default window applied to the request,
+// which envelopes echo in their meta
+const defaultRangeKey = "default_range"
+
+// parseDateRangeParams reads the inclusive from/to window. With a positive
+// defaultDays a request giving neither bound covers the trailing defaultDays UTC
+// days, today included, whose bounds are echoed in DefaultRangeFromHeader and
+// DefaultRangeToHeader and in the envelope meta, unless it sets all=true to cover
+// the full history.
+func (p *queryParams) parseDateRangeParams(defaultDays int) models.DateRange {
+	dateRange := p.parseRangeParams("from", "to")
+	all := p.parseEnumParam("all", "false", "true", "false") == "true"
+	if defaultDays <= 0 || all || p.c.Query("from") != "" || p.c.Query("to") != "" {
+		return dateRange
+	}
+
+	dateRange = models.TrailingDays(defaultDays, time.Now())
+	p.c.Header(DefaultRangeFromHeader, dateRange.From.Format(time.RFC3339))
+	p.c.Header(DefaultRangeToHeader, dateRange.To.Format(time.RFC3339Nano))
+	p.c.Set(defaultRangeKey, dateRange.Window())
+	return dateRange
+}
+
+// parsePeriodParams reads the inclusive <prefix>from/<prefix>to window of one of
+// the periods being compared. Both bounds are required.
+func (p *queryParams) parsePeriodParams(prefix string) models.DateRange {
+	fromName, toName := prefix+"from", prefix+"to"
+	for _, name := range []string{fromName, toName} {
+		if p.c.Query(name) == "" {
+			p.addError(name, "is required")
+		}
+	}
+	return p.parseRangeParams(fromName, toName)
+}
+
+func (p *queryParams) parseRangeParams(fromName, toName string) models.DateRange {
+	dateRange := models.DateRange{
+		From: p.parseDateParam(fromName, false),
+		To:   p.parseDateParam(toName, true),
+	}
+	if dateRange.From != nil && dateRange.To != nil && dateRange.From.After(*dateRange.To) {
+		p.addError(fromName, "must be before or equal to '%s'", toName)
+	}
+	return dateRange
+}
+
+// parseWindowParams reads the from/to window of an endpoint that scans individual
+// transactions. With a positive maxDays both bounds are required and the window may
+// touch at most maxDays UTC days; zero leaves it optional like parseDateRangeParams,
+// defaulting to the trailing defaultDays when positive. Endpoints serving bounded
+// aggregates use parseDateRangeParams.
+func (p *queryParams) parseWindowParams(maxDays, defaultDays int) models.DateRange {
+	if maxDays <= 0 {
+		return p.parseDateRangeParams(defaultDays)
+	}
+
+	dateRange := p.parseDateRangeParams(0)
+
+	if dateRange.From == nil && p.c.Query("from") == "" {
+		p.addError("from", "is required: windows are limited to %d days", maxDays)
+	}
+	if dateRange.To == nil && p.c.Query("to") == "" {
+		p.addError("to", "is required: windows are limited to %d days", maxDays)
+	}
+	if dateRange.From != nil && dateRange.To != nil && !dateRange.From.After(*dateRange.To) {
+		if days := daysSpanned(*dateRange.From, *dateRange.To); days > maxDays {
+			p.addError("to", "window covers %d days, at most %d are allowed: split the request into shorter windows", days, maxDays)
+		}
+	}
+	return dateRange
+}
+
+// parseIDParam reads the positive integer ID in the named path parameter
+func (p *queryParams) parseIDParam(name string) uint {
+	id, err := strconv.ParseUint(p.c.Param(name), 10, 0)
+	if err != nil || id == 0 {
+		p.addError(name, "%q is not a positive integer", p.c.Param(name))
+		return 0
+	}
+	return uint(id)
+}
+
+// parsePaginationParams reads limit/offset within the endpoint's page sizes,
+// defaulting to the first page
+func (p *queryParams) parsePaginationParams(limits config.PageLimits) (int, int) {
+	limit := p.parseIntParam("limit", limits.Default, 1, limits.Max)
+	offset := p.parseIntParam("offset", 0, 0, math.MaxInt)
+	return limit, offset
+}
+
+// parseTimeZoneParam returns the IANA time zone named by the tz parameter,
+// defaulting to UTC
+func (p *queryParams) parseTimeZoneParam() *time.Location {
+	name := p.c.Query("tz")
+	if name == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		p.addError("tz", "%q is not a known IANA time zone", name)
+		return time.UTC
+	}
+	return loc
+}
+
+// parseCurrencyParam returns the requested currency code in upper case, or empty
+// for the base currency, recording codes missing from rates
+func (p *queryParams) parseCurrencyParam(rates map[string]float64) string {
+	currency := currencyParam(p.c)
+	if currency == "" {
+		return ""
+	}
+
+	if _, ok := rates[currency]; !ok {
+		codes := make([]string, 0, len(rates))
+		for code := range rates {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		p.addError("currency", "%q is not supported: supported currencies are %s", currency, strings.Join(codes, ", "))
+	}
+	return currency
+}
+
+// parseConversionParams returns the requested currency, as parsed by
+// parseCurrencyParam, and whether revenue recorded in other currencies should be
+// normalized to the base currency before it is summed
+func (p *queryParams) parseConversionParams(rates map[string]float64) models.Conversion {
+	return models.Conversion{
+		Currency:  p.parseCurrencyParam(rates),
+		Normalize: p.parseEnumParam("normalize", "false", "true", "false") == "true",
+	}
+}
+
+// rejectUnknownParams records any query parameter outside allowed, so misspelled
+// filters fail loudly instead of silently widening the result
+func (p *queryParams) rejectUnknownParams(allowed []string) {
+	known := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		known[name] = true
+	}
+
+	var unknown []string
+	for name := range p.c.Request.URL.Query() {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		p.addError(name, "unknown query parameter: supported parameters are %s", strings.Join(allowed, ", "))
+	}
+}
+
+// respondIfInvalid answers 422 listing every recorded error and reports whether it did
+func (p *queryParams) respondIfInvalid() bool {
+	if len(p.errors) == 0 {
+		return false
+	}
+
+	respondErrorDetails(p.c, http.StatusUnprocessableEntity, models.ErrCodeValidationFailed,
+		fmt.Sprintf("%d invalid request parameter(s)", len(p.errors)), p.errors)
+	return true
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestParams returns the query parameters of a GET request for target
+func newTestParams(target string) (*queryParams, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	return newQueryParams(c), w
+}
+
+func errorFields(p *queryParams) []string {
+	fields := make([]string, 0, len(p.errors))
+	for _, err := range p.errors {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestParseDateRangeParams(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	endOfDay := func(year int, month time.Month, d int) time.Time {
+		return day(year, month, d).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		from, to *time.Time
+		errors   []string
+	}{
+		{name: "no bounds", query: ""},
+		{name: "date-only from starts the day", query: "from=2024-01-01", from: ptr(day(2024, 1, 1))},
+		{name: "date-only to ends the day", query: "to=2024-01-31", to: ptr(endOfDay(2024, 1, 31))},
+		{
+			name:  "RFC3339 bounds are kept exact",
+			query: "from=2024-01-01T10:00:00Z&to=2024-01-01T12:30:00Z",
+			from:  ptr(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)),
+			to:    ptr(time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:  "single day is inclusive",
+			query: "from=2024-02-29&to=2024-02-29",
+			from:  ptr(day(2024, 2, 29)),
+			to:    ptr(endOfDay(2024, 2, 29)),
+		},
+		{
+			name:  "equal RFC3339 bounds are accepted",
+			query: "from=2024-01-01T10:00:00Z&to=2024-01-01T10:00:00Z",
+			from:  ptr(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)),
+			to:    ptr(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:   "reversed range",
+			query:  "from=2024-02-01&to=2024-01-01",
+			from:   ptr(day(2024, 2, 1)),
+			to:     ptr(endOfDay(2024, 1, 1)),
+			errors: []string{"from"},
+		},
+		{
+			name:   "reversed by one nanosecond past the day",
+			query:  "from=2024-01-02T00:00:00Z&to=2024-01-01",
+			from:   ptr(day(2024, 1, 2)),
+			to:     ptr(endOfDay(2024, 1, 1)),
+			errors: []string{"from"},
+		},
+		{name: "invalid from", query: "from=01/02/2024", errors: []string{"from"}},
+		{name: "invalid to", query: "to=2024-13-01", errors: []string{"to"}},
+		{name: "both invalid", query: "from=yesterday&to=today", errors: []string{"from", "to"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestParams("/?" + tt.query)
+			dateRange := p.parseDateRangeParams(0)
+
+			assertTime(t, "from", dateRange.From, tt.from)
+			assertTime(t, "to", dateRange.To, tt.to)
+			if got := errorFields(p); !equalStrings(got, tt.errors) {
+				t.Errorf("errors on %v, want %v", got, tt.errors)
+			}
+		})
+	}
+}
+
+func TestParseDateRangeParamsDefaultWindow(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	defaultFrom := today.AddDate(0, 0, -89)
+	defaultTo := today.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	given := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		defaultDays int
+		from, to    *time.Time
+		echoed      bool
+		errors      []string
+	}{
+		{name: "trailing window when no bound is given", defaultDays: 90, from: &defaultFrom, to: &defaultTo, echoed: true},
+		{name: "all=true covers the full history", query: "all=true", defaultDays: 90},
+		{name: "all=false keeps the window", query: "all=false", defaultDays: 90, from: &defaultFrom, to: &defaultTo, echoed: true},
+		{name: "a from bound replaces the window", query: "from=2024-01-01", defaultDays: 90, from: &given},
+		{name: "a to bound replaces the window", query: "to=2024-01-01", defaultDays: 90, to: ptr(given.AddDate(0, 0, 1).Add(-time.Nanosecond))},
+		{name: "no default configured", defaultDays: 0},
+		{name: "invalid all", query: "all=yes", defaultDays: 90, from: &defaultFrom, to: &defaultTo, echoed: true, errors: []string{"all"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, w := newTestParams("/?" + tt.query)
+			dateRange := p.parseDateRangeParams(tt.defaultDays)
+
+			assertTime(t, "from", dateRange.From, tt.from)
+			assertTime(t, "to", dateRange.To, tt.to)
+			from, to := w.Header().Get(DefaultRangeFromHeader), w.Header().Get(DefaultRangeToHeader)
+			switch {
+			case !tt.echoed && (from != "" || to != ""):
+				t.Errorf("echoed %q..%q, want no default window", from, to)
+			case tt.echoed && (from != defaultFrom.Format(time.RFC3339) || to != defaultTo.Format(time.RFC3339Nano)):
+				t.Errorf("echoed %q..%q, want %s..%s", from, to, defaultFrom.Format(time.RFC3339), defaultTo.Format(time.RFC3339Nano))
+			}
+			if got := errorFields(p); !equalStrings(got, tt.errors) {
+				t.Errorf("errors on %v, want %v", got, tt.errors)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func assertTime(t *testing.T, name string, got, want *time.Time) {
+	t.Helper()
+	switch {
+	case got == nil && want == nil:
+	case got == nil || want == nil:
+		t.Errorf("%s = %v, want %v", name, got, want)
+	case !got.Equal(*want):
+		t.Errorf("%s = %s, want %s", name, got.Format(time.RFC3339Nano), want.Format(time.RFC3339Nano))
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetCountryRegions(t *testing.T) {
+	sale := func(country, region, revenue string) models.Transaction {
+		return models.Transaction{
+			TransactionDate: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+			Country:         country,
+			Region:          region,
+			Product:         "Widget",
+			Quantity:        1,
+			Revenue:         money(revenue),
+		}
+	}
+	controller := newSQLiteController(newSQLiteDB(t,
+		sale("United States", "Texas", "300"),
+		sale("United States", "California", "500"),
+		sale("United States", "Texas", "250"),
+		sale("Germany", "Bavaria", "900"),
+	))
+
+	tests := []struct {
+		name    string
+		target  string
+		regions []string
+		revenue []string
+	}{
+		{
+			name:    "URL-encoded country",
+			target:  "/analytics/country/United%20States/regions",
+			regions: []string{"Texas", "California"},
+			revenue: []string{"550", "500"},
+		},
+		{
+			name:    "case and spaces ignored",
+			target:  "/analytics/country/%20united%20states%20/regions?n=1",
+			regions: []string{"Texas"},
+			revenue: []string{"550"},
+		},
+		{name: "unknown country", target: "/analytics/country/Atlantis/regions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(controller.GetCountryRegions, "/analytics/country/:country/regions", tt.target)
+			assertStatus(t, w, http.StatusOK)
+
+			var rows []models.RegionRevenue
+			decodeJSON(t, w, &rows)
+			if rows == nil {
+				t.Fatalf("body %s, want a list", w.Body.String())
+			}
+			if len(rows) != len(tt.regions) {
+				t.Fatalf("got %+v, want regions %v", rows, tt.regions)
+			}
+			for i, row := range rows {
+				if row.Region != tt.regions[i] {
+					t.Errorf("row %d = %s, want %s", i, row.Region, tt.regions[i])
+				}
+				if !row.Revenue.Equal(money(tt.revenue[i]).Decimal) {
+					t.Errorf("%s revenue = %s, want %s", row.Region, row.Revenue.Format(), tt.revenue[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+
+	"abt-analytics/internal/models"
+)
+
+// AnalyticsService is the analytics behaviour the controller serves. It is met by
+// *services.AnalyticsService backed by the database and by
+// *services.DemoAnalyticsService serving the built-in sample data; handler tests
+// can substitute controllertest.MockAnalyticsService.
+type AnalyticsService interface {
+	Ping(ctx context.Context) error
+	GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error)
+	GetCountryRevenue(ctx context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error)
+	GetCategoryRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CategoryRevenue, bool, error)
+	GetAverageOrderValue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CountryOrderValue, bool, error)
+	GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) (*models.OrderValuePercentiles, bool, error)
+	ComparePeriods(ctx context.Context, a, b models.DateRange, metric string, conversion models.Conversion) (*models.PeriodComparison, bool, error)
+	GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int, conversion models.Conversion) (*models.ProductRevenuePage, bool, error)
+	GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int, conversion models.Conversion) (*models.CustomerRevenuePage, bool, error)
+	GetRevenueConcentration(ctx context.Context, dateRange models.DateRange, thresholds []float64, conversion models.Conversion) (*models.RevenueConcentration, bool, error)
+	GetMonthlySales(ctx context.Context, filter models.SalesFilter, granularity string, compareYoY bool, conversion models.Conversion) ([]models.MonthlySales, bool, error)
+	GetRevenueGrowth(ctx context.Context, filter models.SalesFilter, conversion models.Conversion) ([]models.RevenueGrowth, bool, error)
+	GetRevenueForecast(ctx context.Context, filter models.SalesFilter, months, window int, conversion models.Conversion) ([]models.RevenueForecast, bool, error)
+	GetDailyRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.DailyRevenue, bool, error)
+	GetTopRegions(ctx context.Context, filter models.RegionFilter, n int, conversion models.Conversion) ([]models.RegionRevenue, bool, error)
+	GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int, conversion models.Conversion) ([]models.RegionTrend, bool, error)
+	GetSummary(ctx context.Context, dateRange models.DateRange, productLimit, regionCount int, conversion models.Conversion) (*models.DashboardSummary, bool, error)
+	GetDataMeta(ctx context.Context) (*models.DataMeta, bool, error)
+	GetDimensions(ctx context.Context) (*models.Dimensions, bool, error)
+	ExportTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error
+	ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error)
+	GetTransaction(ctx context.Context, id uint) (*models.TransactionResponse, error)
+	IngestTransactions(ctx context.Context, inputs []models.TransactionInput) (int, error)
+	DeleteTransaction(ctx context.Context, id uint) error
+	FlushCache(ctx context.Context, scope string) (int, error)
+}
@@ -0,0 +1,67 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/middleware"
+	"abt-analytics/internal/models"
+)
+
+func TestTimeoutCancelsSlowQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+		want  int
+	}{
+		{name: "within the deadline", delay: 0, want: http.StatusOK},
+		{name: "past the deadline", delay: time.Minute, want: http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queryErr error
+			service := &controllertest.MockAnalyticsService{
+				// a query that runs for delay unless its context ends first
+				GetCountryRevenueFunc: func(ctx context.Context, _ models.CountryFilter, _ models.Conversion) ([]models.CountryRevenue, bool, error) {
+					select {
+					case <-time.After(tt.delay):
+						return []models.CountryRevenue{}, false, nil
+					case <-ctx.Done():
+						queryErr = ctx.Err()
+						return nil, false, queryErr
+					}
+				},
+			}
+			router := gin.New()
+			router.Use(middleware.Timeout(20 * time.Millisecond))
+			router.GET("/country-revenue", newTestController(service).GetCountryRevenue)
+
+			started := time.Now()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/country-revenue", nil))
+
+			assertStatus(t, w, tt.want)
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("request took %s, the query was not canceled", elapsed)
+			}
+			if tt.want != http.StatusGatewayTimeout {
+				return
+			}
+			if !errors.Is(queryErr, context.DeadlineExceeded) {
+				t.Errorf("query ended with %v, want the deadline", queryErr)
+			}
+			var body models.ErrorResponse
+			decodeJSON(t, w, &body)
+			if body.Code != models.ErrCodeTimeout {
+				t.Errorf("code = %q, want %q", body.Code, models.ErrCodeTimeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	// so the zones resolve on machines without a zoneinfo database
+	_ "time/tzdata"
+
+	"abt-analytics/internal/models"
+)
+
+func TestMonthlySalesBucketsByTimeZone(t *testing.T) {
+	sale := func(at, revenue string) models.Transaction {
+		date, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return models.Transaction{TransactionDate: date, Country: "JP", Region: "Tokyo", Product: "Widget", Quantity: 1, Revenue: money(revenue)}
+	}
+	handler := newSQLiteController(newSQLiteDB(t,
+		// 20:00 UTC on 31 January is already 1 February in Tokyo
+		sale("2024-01-31T20:00:00Z", "100"),
+		// 03:00 UTC on 1 February is still 31 January in Los Angeles
+		sale("2024-02-01T03:00:00Z", "10"),
+	)).GetMonthlySales
+
+	type month struct{ period, revenue string }
+	tests := []struct {
+		tz   string
+		want []month
+	}{
+		{tz: "", want: []month{{"2024-01", "100"}, {"2024-02", "10"}}},
+		{tz: "UTC", want: []month{{"2024-01", "100"}, {"2024-02", "10"}}},
+		{tz: "Asia/Tokyo", want: []month{{"2024-02", "110"}}},
+		{tz: "America/Los_Angeles", want: []month{{"2024-01", "110"}}},
+	}
+	for _, tt := range tests {
+		t.Run("tz="+tt.tz, func(t *testing.T) {
+			w := get(handler, "/monthly-sales", "/monthly-sales?tz="+tt.tz)
+			assertStatus(t, w, http.StatusOK)
+
+			var rows []models.MonthlySales
+			decodeJSON(t, w, &rows)
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %+v, want %v", rows, tt.want)
+			}
+			for i, m := range tt.want {
+				if rows[i].Period != m.period || !rows[i].Revenue.Equal(money(m.revenue).Decimal) {
+					t.Errorf("row %d = %s %s, want %s %s", i, rows[i].Period, rows[i].Revenue.Format(), m.period, m.revenue)
+				}
+			}
+		})
+	}
+
+	for _, tz := range []string{"Mars/Olympus_Mons", "Local"} {
+		w := get(handler, "/monthly-sales", "/monthly-sales?tz="+tz)
+		assertStatus(t, w, http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetTransaction(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	sold := models.Transaction{
+		OrderID: "ORD-1", CustomerID: "CUST-7", TransactionDate: date, Country: "US", Region: "Texas",
+		Product: "Widget", Category: "Tools", Quantity: 3, Revenue: money("29.97"), Currency: "USD",
+	}
+	voided := models.Transaction{OrderID: "ORD-2", CustomerID: "CUST-8", TransactionDate: date, Country: "US", Region: "Texas", Product: "Widget", Quantity: 1, Revenue: money("9.99")}
+	db := newSQLiteDB(t, sold, voided)
+	if err := db.Where("order_id = ?", "ORD-2").Delete(&models.Transaction{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	handler := newSQLiteController(db).GetTransaction
+
+	t.Run("found", func(t *testing.T) {
+		w := get(handler, "/transactions/:id", "/transactions/1")
+		assertStatus(t, w, http.StatusOK)
+		var got models.TransactionResponse
+		decodeJSON(t, w, &got)
+		if got.ID != 1 || got.OrderID != "ORD-1" || got.CustomerID != "CUST-7" || !got.TransactionDate.Equal(date) ||
+			got.Country != "US" || got.Region != "Texas" || got.Product != "Widget" || got.Category != "Tools" ||
+			got.Quantity != 3 || !got.Revenue.Equal(money("29.97").Decimal) || got.Currency != "USD" {
+			t.Errorf("transaction = %+v, want the stored %+v", got, sold)
+		}
+	})
+
+	for _, tt := range []struct {
+		name   string
+		id     string
+		status int
+		code   string
+	}{
+		{name: "not found", id: "999", status: http.StatusNotFound, code: models.ErrCodeNotFound},
+		{name: "voided", id: "2", status: http.StatusNotFound, code: models.ErrCodeNotFound},
+		{name: "not a number", id: "abc", status: http.StatusUnprocessableEntity, code: models.ErrCodeValidationFailed},
+		{name: "zero", id: "0", status: http.StatusUnprocessableEntity, code: models.ErrCodeValidationFailed},
+		{name: "negative", id: "-1", status: http.StatusUnprocessableEntity, code: models.ErrCodeValidationFailed},
+		{name: "fractional", id: "1.5", status: http.StatusUnprocessableEntity, code: models.ErrCodeValidationFailed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(handler, "/transactions/:id", "/transactions/"+tt.id)
+			assertStatus(t, w, tt.status)
+			var body models.ErrorResponse
+			decodeJSON(t, w, &body)
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestListTransactionsCursorPaging(t *testing.T) {
+	// three sales share every timestamp, so pages must break ties on the ID
+	var rows []models.Transaction
+	for i := 0; i < 23; i++ {
+		rows = append(rows, models.Transaction{
+			OrderID:         fmt.Sprintf("order-%02d", i),
+			TransactionDate: time.Date(2024, time.January, 1+i/3, 12, 0, 0, 0, time.UTC),
+			Country:         "US",
+			Region:          "Texas",
+			Product:         "Widget",
+			Quantity:        1,
+			Revenue:         money("1"),
+		})
+	}
+	handler := newSQLiteController(newSQLiteDB(t, rows...)).ListTransactions
+
+	seen := make(map[uint]bool)
+	var previous *models.TransactionResponse
+	cursor, pages := "", 0
+	for {
+		target := "/transactions?limit=5"
+		if cursor != "" {
+			target += "&cursor=" + url.QueryEscape(cursor)
+		}
+		w := get(handler, "/transactions", target)
+		assertStatus(t, w, http.StatusOK)
+		var page models.TransactionPage
+		decodeJSON(t, w, &page)
+		pages++
+
+		for i := range page.Data {
+			row := page.Data[i]
+			if seen[row.ID] {
+				t.Errorf("transaction %d returned twice", row.ID)
+			}
+			seen[row.ID] = true
+			if previous != nil && (row.TransactionDate.After(previous.TransactionDate) ||
+				row.TransactionDate.Equal(previous.TransactionDate) && row.ID > previous.ID) {
+				t.Errorf("transaction %d follows %d out of order", row.ID, previous.ID)
+			}
+			previous = &row
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		if pages > len(rows) {
+			t.Fatal("paging does not end")
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(seen) != len(rows) {
+		t.Errorf("paging returned %d transactions, want all %d", len(seen), len(rows))
+	}
+	if pages != 5 {
+		t.Errorf("took %d pages, want 5 of at most 5 rows", pages)
+	}
+}
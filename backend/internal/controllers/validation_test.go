@@ -0,0 +1,88 @@
+package controllers_test
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/controllers/controllertest"
+	"abt-analytics/internal/models"
+)
+
+func TestEveryInvalidParameterIsReported(t *testing.T) {
+	controller := newTestController(&controllertest.MockAnalyticsService{})
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		route   string
+		query   string
+		fields  []string
+	}{
+		{
+			name:    "top products",
+			handler: controller.GetTopProducts,
+			route:   "/analytics/top-products",
+			query:   "sort=price&dir=up&limit=abc&offset=-1&from=yesterday&to=2024-13-01&currency=XYZ&decimal=semicolon",
+			fields:  []string{"currency", "decimal", "dir", "from", "limit", "offset", "sort", "to"},
+		},
+		{
+			name:    "monthly sales",
+			handler: controller.GetMonthlySales,
+			route:   "/analytics/monthly-sales",
+			query:   "granularity=week&compare=mom&from=2024-02-01&to=2024-01-01",
+			fields:  []string{"compare", "from", "granularity"},
+		},
+		{
+			name:    "country revenue",
+			handler: controller.GetCountryRevenue,
+			route:   "/analytics/country-revenue",
+			query:   "from=01/02/2024&to=tomorrow&currency=XYZ",
+			fields:  []string{"currency", "from", "to"},
+		},
+		{
+			name:    "period comparison",
+			handler: controller.ComparePeriods,
+			route:   "/analytics/compare",
+			query:   "metric=margin&a_from=2024-02-01&a_to=2024-01-01&b_from=2024-03-01",
+			fields:  []string{"a_from", "b_to", "metric"},
+		},
+		{
+			name:    "empty country",
+			handler: controller.GetCountryRevenue,
+			route:   "/analytics/country-revenue",
+			query:   "country=US&country=%20",
+			fields:  []string{"country"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.handler, tt.route, tt.route+"?"+tt.query)
+			assertStatus(t, w, http.StatusUnprocessableEntity)
+
+			var body struct {
+				Code    string              `json:"code"`
+				Details []models.FieldError `json:"details"`
+			}
+			decodeJSON(t, w, &body)
+			if body.Code != models.ErrCodeValidationFailed {
+				t.Errorf("code = %q, want %q", body.Code, models.ErrCodeValidationFailed)
+			}
+
+			var fields []string
+			for _, detail := range body.Details {
+				if detail.Message == "" {
+					t.Errorf("%s is reported without a message", detail.Field)
+				}
+				fields = append(fields, detail.Field)
+			}
+			sort.Strings(fields)
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("reported %v, want every one of %v", fields, tt.fields)
+			}
+		})
+	}
+}
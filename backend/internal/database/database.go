@@ -0,0 +1,181 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/tracing"
+)
+
+// Connect opens the configured database, retrying transient failures, and
+// applies the connection pool limits
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	dialector, err := openDialector(cfg.Driver, cfg.GetDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := connectWithRetry(func() (*gorm.DB, error) {
+		return gorm.Open(dialector, &gorm.Config{
+			Logger:         newLogger(cfg),
+			NamingStrategy: schema.NamingStrategy{TablePrefix: cfg.DBTablePrefix},
+		})
+	}, cfg.DBConnectAttempts, cfg.DBConnectBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, err
+	}
+	if err := useReplicas(db, cfg); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	configurePool(sqlDB, cfg)
+
+	return db, nil
+}
+
+// openDialector returns the GORM dialector of driver for dsn
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case config.DriverMySQL:
+		return mysql.Open(dsn), nil
+	case config.DriverPostgres:
+		return postgres.Open(dsn), nil
+	case config.DriverSQLite:
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// useReplicas sends the reads made through db to the DB_REPLICAS databases, picked
+// at random per query, leaving writes and transactions on the primary. Without
+// replicas everything stays on the primary.
+func useReplicas(db *gorm.DB, cfg *config.Config) error {
+	if len(cfg.DBReplicas) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, len(cfg.DBReplicas))
+	for i, dsn := range cfg.DBReplicas {
+		dialector, err := openDialector(cfg.Driver, dsn)
+		if err != nil {
+			return err
+		}
+		replicas[i] = dialector
+	}
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas, Policy: dbresolver.RandomPolicy{}}).
+		SetMaxOpenConns(cfg.DBMaxOpenConns).
+		SetMaxIdleConns(cfg.DBMaxIdleConns).
+		SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("register read replicas: %w", err)
+	}
+
+	log.Printf("🔌 Database reads routed to %d replica(s)", len(replicas))
+	return nil
+}
+
+// logLevels maps the DB_LOG_LEVEL names onto GORM's log levels
+var logLevels = map[string]logger.LogLevel{
+	config.DBLogSilent: logger.Silent,
+	config.DBLogError:  logger.Error,
+	config.DBLogWarn:   logger.Warn,
+	config.DBLogInfo:   logger.Info,
+}
+
+// newLogger returns the SQL query logger for the configured level. Queries slower
+// than cfg.DBSlowThreshold are logged as warnings; missing records are not errors
+// worth logging since lookups report them to their callers.
+func newLogger(cfg *config.Config) logger.Interface {
+	level, ok := logLevels[cfg.DBLogLevel]
+	if !ok {
+		level = logger.Warn
+	}
+
+	return logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:             cfg.DBSlowThreshold,
+		LogLevel:                  level,
+		IgnoreRecordNotFoundError: true,
+	})
+}
+
+// Migrate creates or updates the schema for all models, inspecting the primary
+// rather than a read replica
+func Migrate(db *gorm.DB) error {
+	return db.Clauses(dbresolver.Write).AutoMigrate(schemaModels...)
+}
+
+// schemaModels are the models Migrate creates and updates tables for
+var schemaModels = []interface{}{&models.Transaction{}}
+
+// MissingSchema compares the database with the tables and columns Migrate would
+// create, returning the tables that do not exist and the missing columns of
+// those that do
+func MissingSchema(db *gorm.DB) ([]models.SchemaGap, error) {
+	migrator := db.Clauses(dbresolver.Write).Migrator()
+	missing := []models.SchemaGap{}
+	for _, model := range schemaModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			missing = append(missing, models.SchemaGap{Table: table})
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !migrator.HasColumn(model, field.DBName) {
+				missing = append(missing, models.SchemaGap{Table: table, Column: field.DBName})
+			}
+		}
+	}
+	return missing, nil
+}
+
+// Close releases the connection pool behind db, if any
+func Close(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Printf("Warning: Could not access database pool: %v", err)
+		return
+	}
+	if err := sqlDB.Close(); err != nil {
+		log.Printf("Warning: Failed to close database: %v", err)
+	}
+}
+
+// configurePool applies the configured connection pool limits to sqlDB
+func configurePool(sqlDB *sql.DB, cfg *config.Config) {
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+
+	log.Printf("🔌 Database pool: max_open=%d max_idle=%d max_lifetime=%s",
+		cfg.DBMaxOpenConns, cfg.DBMaxIdleConns, cfg.DBConnMaxLifetime)
+}
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+// openSQLite opens an in-memory SQLite database without touching its pool settings
+func openSQLite(t *testing.T) (*gorm.DB, *sql.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("access pool: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db, sqlDB
+}
+
+func TestConfigurePool(t *testing.T) {
+	_, sqlDB := openSQLite(t)
+	configurePool(sqlDB, &config.Config{DBMaxOpenConns: 3, DBMaxIdleConns: 1, DBConnMaxLifetime: time.Hour})
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+
+	// Hold three connections at once, then release them: only one may stay idle
+	conns := make([]*sql.Conn, 3)
+	for i := range conns {
+		conn, err := sqlDB.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("connection %d: %v", i+1, err)
+		}
+		conns[i] = conn
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if stats := sqlDB.Stats(); stats.Idle != 1 || stats.MaxIdleClosed != 2 {
+		t.Errorf("idle = %d with %d closed, want 1 idle and 2 closed", stats.Idle, stats.MaxIdleClosed)
+	}
+}
+
+func TestNewLoggerUsesConfiguredLevelAndThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		threshold time.Duration
+		want      logger.LogLevel
+	}{
+		{name: "slow queries only", level: config.DBLogWarn, threshold: 200 * time.Millisecond, want: logger.Warn},
+		{name: "silent", level: config.DBLogSilent, threshold: time.Second, want: logger.Silent},
+		{name: "every query", level: config.DBLogInfo, threshold: 50 * time.Millisecond, want: logger.Info},
+		{name: "unknown level falls back to warn", level: "verbose", threshold: time.Second, want: logger.Warn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLogger(&config.Config{DBLogLevel: tt.level, DBSlowThreshold: tt.threshold})
+
+			// GORM keeps the logger's settings in the Config embedded in its implementation
+			settings := reflect.ValueOf(l).Elem().FieldByName("Config").Interface().(logger.Config)
+			if settings.LogLevel != tt.want {
+				t.Errorf("LogLevel = %v, want %v", settings.LogLevel, tt.want)
+			}
+			if settings.SlowThreshold != tt.threshold {
+				t.Errorf("SlowThreshold = %s, want %s", settings.SlowThreshold, tt.threshold)
+			}
+			if !settings.IgnoreRecordNotFoundError {
+				t.Error("missing records are logged as errors")
+			}
+		})
+	}
+}
+
+func TestMissingSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(db *gorm.DB) error
+		want    []models.SchemaGap
+	}{
+		{name: "not migrated", prepare: func(*gorm.DB) error { return nil }, want: []models.SchemaGap{{Table: "transactions"}}},
+		{name: "migrated", prepare: Migrate, want: []models.SchemaGap{}},
+		{
+			name: "column dropped after migrating",
+			prepare: func(db *gorm.DB) error {
+				if err := Migrate(db); err != nil {
+					return err
+				}
+				return db.Migrator().DropColumn(&models.Transaction{}, "currency")
+			},
+			want: []models.SchemaGap{{Table: "transactions", Column: "currency"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlDB := openSQLite(t)
+			sqlDB.SetMaxOpenConns(1)
+			if err := tt.prepare(db); err != nil {
+				t.Fatalf("prepare: %v", err)
+			}
+
+			missing, err := MissingSchema(db)
+			if err != nil {
+				t.Fatalf("MissingSchema: %v", err)
+			}
+			if !reflect.DeepEqual(missing, tt.want) {
+				t.Errorf("missing = %+v, want %+v", missing, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadsGoToReplicas(t *testing.T) {
+	// seed opens the SQLite file at path, migrated, holding one sale in country
+	seed := func(t *testing.T, path, country string) {
+		t.Helper()
+		db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			t.Fatalf("open %s: %v", path, err)
+		}
+		defer Close(db)
+		if err := Migrate(db); err != nil {
+			t.Fatalf("migrate %s: %v", path, err)
+		}
+		row := models.Transaction{TransactionDate: time.Now(), Country: country, Region: "R", Product: "P", Quantity: 1}
+		if err := db.Create(&row).Error; err != nil {
+			t.Fatalf("seed %s: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		replicas bool
+		reads    string
+	}{
+		{name: "with a replica", replicas: true, reads: "replica"},
+		{name: "without replicas", reads: "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := &config.Config{
+				Driver:            config.DriverSQLite,
+				DBName:            filepath.Join(dir, "primary.db"),
+				DBLogLevel:        config.DBLogSilent,
+				DBConnectAttempts: 1,
+				DBMaxOpenConns:    1,
+				DBMaxIdleConns:    1,
+			}
+			seed(t, cfg.DBName, "primary")
+			if tt.replicas {
+				replica := filepath.Join(dir, "replica.db")
+				seed(t, replica, "replica")
+				cfg.DBReplicas = []string{replica}
+			}
+
+			db, err := Connect(cfg)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer Close(db)
+			if _, registered := db.Config.Plugins["gorm:db_resolver"]; registered != tt.replicas {
+				t.Errorf("resolver registered = %v, want %v", registered, tt.replicas)
+			}
+
+			var read []string
+			if err := db.Model(&models.Transaction{}).Pluck("country", &read).Error; err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(read, []string{tt.reads}) {
+				t.Errorf("read %v, want the %s row", read, tt.reads)
+			}
+
+			// writes, and reads pinned to the primary, never reach the replica
+			row := models.Transaction{TransactionDate: time.Now(), Country: "written", Region: "R", Product: "P", Quantity: 1}
+			if err := db.Create(&row).Error; err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			var written []string
+			if err := db.Clauses(dbresolver.Write).Model(&models.Transaction{}).Order("id").Pluck("country", &written).Error; err != nil {
+				t.Fatalf("read primary: %v", err)
+			}
+			if want := []string{"primary", "written"}; !reflect.DeepEqual(written, want) {
+				t.Errorf("primary holds %v, want %v", written, want)
+			}
+		})
+	}
+}
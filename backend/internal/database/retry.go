@@ -0,0 +1,47 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxConnectBackoff caps the delay between connection attempts
+const maxConnectBackoff = 30 * time.Second
+
+// dbOpener opens a database connection; it is called once per attempt
+type dbOpener func() (*gorm.DB, error)
+
+// connectWithRetry calls open up to attempts times, doubling the delay after each
+// failure starting from baseDelay. It returns the last error once attempts run out.
+func connectWithRetry(open dbOpener, attempts int, baseDelay time.Duration) (*gorm.DB, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := baseDelay
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var db *gorm.DB
+		db, err = open()
+		if err == nil {
+			return db, nil
+		}
+
+		log.Printf("Database connection attempt %d/%d failed: %v", attempt, attempts, err)
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("Retrying database connection in %s", delay)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxConnectBackoff {
+			delay = maxConnectBackoff
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// flakyOpener fails the first failures calls, then returns db
+func flakyOpener(db *gorm.DB, failures int) (dbOpener, *int) {
+	calls := 0
+	return func() (*gorm.DB, error) {
+		calls++
+		if calls <= failures {
+			return nil, errors.New("connection refused")
+		}
+		return db, nil
+	}, &calls
+}
+
+func TestConnectWithRetrySucceedsAfterFailures(t *testing.T) {
+	db, _ := openSQLite(t)
+	open, calls := flakyOpener(db, 3)
+
+	start := time.Now()
+	got, err := connectWithRetry(open, 5, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("connectWithRetry: %v", err)
+	}
+	if got != db || *calls != 4 {
+		t.Errorf("got the database after %d calls, want 4", *calls)
+	}
+	// The delays double: 5ms, 10ms and 20ms before the fourth attempt
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("retried within %s, want at least the 35ms of backoff", elapsed)
+	}
+}
+
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	open, calls := flakyOpener(nil, 10)
+
+	_, err := connectWithRetry(open, 3, time.Millisecond)
+	if err == nil || err.Error() != "giving up after 3 attempts: connection refused" {
+		t.Errorf("err = %v, want the last failure after 3 attempts", err)
+	}
+	if *calls != 3 {
+		t.Errorf("opener called %d times, want 3", *calls)
+	}
+}
+
+func TestConnectWithRetryTriesAtLeastOnce(t *testing.T) {
+	db, _ := openSQLite(t)
+	open, calls := flakyOpener(db, 0)
+
+	if _, err := connectWithRetry(open, 0, time.Millisecond); err != nil || *calls != 1 {
+		t.Errorf("with 0 attempts: err = %v after %d calls, want one successful call", err, *calls)
+	}
+}
@@ -0,0 +1,101 @@
+// Package health probes the API's dependencies and rolls their states up into
+// one overall status for the health endpoint.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// Overall statuses. A failed critical dependency makes the API unhealthy, a
+// failed non-critical one only degrades it.
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Dependency statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Checker probes a single dependency
+type Checker interface {
+	Name() string
+	Critical() bool
+	Check(ctx context.Context) error
+}
+
+// funcChecker adapts a probe function to Checker
+type funcChecker struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// NewChecker creates a Checker named name that runs check
+func NewChecker(name string, critical bool, check func(ctx context.Context) error) Checker {
+	return &funcChecker{name: name, critical: critical, check: check}
+}
+
+func (f *funcChecker) Name() string                    { return f.name }
+func (f *funcChecker) Critical() bool                  { return f.critical }
+func (f *funcChecker) Check(ctx context.Context) error { return f.check(ctx) }
+
+// Run probes every checker concurrently, giving each at most timeout, and
+// returns the overall status with one entry per dependency in checker order
+func Run(ctx context.Context, checkers []Checker, timeout time.Duration) (string, []models.DependencyHealth) {
+	results := make([]models.DependencyHealth, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = probe(ctx, checker, timeout)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	return aggregate(results), results
+}
+
+// probe runs one check and records its outcome and latency
+func probe(ctx context.Context, checker Checker, timeout time.Duration) models.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := models.DependencyHealth{
+		Name:      checker.Name(),
+		Status:    StatusUp,
+		Critical:  checker.Critical(),
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// aggregate derives the overall status from the dependency results
+func aggregate(results []models.DependencyHealth) string {
+	status := StatusHealthy
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Critical {
+			return StatusUnhealthy
+		}
+		status = StatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// scrape returns the exposition text served by m
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestMiddlewareCountsRequestsByRoute(t *testing.T) {
+	m := New()
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/api/v1/analytics/top-regions/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const counter = `abt_analytics_http_requests_total{method="GET",route="/api/v1/analytics/top-regions/:id",status="200"}`
+	if strings.Contains(scrape(t, m), counter) {
+		t.Fatal("counter present before any request")
+	}
+
+	for _, path := range []string{"/api/v1/analytics/top-regions/1", "/api/v1/analytics/top-regions/2", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		counter + " 2",
+		`abt_analytics_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`abt_analytics_http_request_duration_seconds_count{method="GET",route="/api/v1/analytics/top-regions/:id",status="200"} 2`,
+		`abt_analytics_http_requests_in_flight{route="/api/v1/analytics/top-regions/:id"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db, err := gormDB.DB()
+	if err != nil {
+		t.Fatalf("access pool: %v", err)
+	}
+	defer db.Close()
+
+	m := New()
+	m.RegisterDBStats(db)
+	body := scrape(t, m)
+	for _, want := range []string{"abt_analytics_db_open_connections 1", "abt_analytics_db_in_use_connections 0"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+}
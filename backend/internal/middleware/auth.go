@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// APIKeyHeader is the header clients use to present their API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth rejects requests whose X-API-Key header does not match one of keys.
+// Keys are hashed before comparison so every check is constant time regardless
+// of key length, and all keys are always compared.
+func APIKeyAuth(keys []string) gin.HandlerFunc {
+	hashed := make([][sha256.Size]byte, len(keys))
+	for i, key := range keys {
+		hashed[i] = sha256.Sum256([]byte(key))
+	}
+
+	return func(c *gin.Context) {
+		provided := c.GetHeader(APIKeyHeader)
+		if provided == "" {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Missing API key")
+			return
+		}
+
+		providedHash := sha256.Sum256([]byte(provided))
+		match := 0
+		for i := range hashed {
+			match |= subtle.ConstantTimeCompare(providedHash[:], hashed[i][:])
+		}
+		if match != 1 {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Invalid API key")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortWithError stops the chain with the standard error envelope
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, models.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: GetRequestID(c),
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	router := gin.New()
+	router.Use(APIKeyAuth([]string{"first-key", "second-key", "third-key"}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name    string
+		key     string
+		status  int
+		message string
+	}{
+		{name: "missing key", status: http.StatusUnauthorized, message: "Missing API key"},
+		{name: "wrong key", key: "second-ke", status: http.StatusUnauthorized, message: "Invalid API key"},
+		{name: "first of several keys", key: "first-key", status: http.StatusNoContent},
+		{name: "last of several keys", key: "third-key", status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.message == "" {
+				return
+			}
+			var body models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", w.Body.String(), err)
+			}
+			if body.Code != models.ErrCodeUnauthorized || body.Message != tt.message {
+				t.Errorf("body = %+v, want %s with %q", body, models.ErrCodeUnauthorized, tt.message)
+			}
+		})
+	}
+}
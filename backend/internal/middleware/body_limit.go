@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// ErrBodyTooLarge is returned by reads of a request body past the BodyLimit
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// BodyLimit caps request bodies at maxBytes. Requests declaring a larger
+// Content-Length are rejected with 413 before the handler runs; for bodies of
+// unknown length, reads past the limit fail with ErrBodyTooLarge so the handler
+// can answer 413 itself.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			// The unread body is not worth draining; close the connection instead
+			c.Header("Connection", "close")
+			abortWithError(c, http.StatusRequestEntityTooLarge, models.ErrCodePayloadTooLarge,
+				fmt.Sprintf("Request body must not exceed %d bytes", maxBytes))
+			return
+		}
+
+		c.Request.Body = &limitedBody{ReadCloser: c.Request.Body, remaining: maxBytes}
+		c.Next()
+	}
+}
+
+// limitedBody fails reads with ErrBodyTooLarge once more than remaining bytes
+// have been read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	// Reading one byte past the limit tells a body of exactly the limit from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n, b.remaining = int(b.remaining), -1
+		return n, ErrBodyTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
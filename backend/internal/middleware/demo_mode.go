@@ -0,0 +1,15 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// DemoModeHeader marks responses served from the built-in sample data
+const DemoModeHeader = "X-Demo-Mode"
+
+// DemoMode sets DemoModeHeader to true on every response, so clients can make
+// it obvious that the data shown is sample data rather than real transactions
+func DemoMode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(DemoModeHeader, "true")
+		c.Next()
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// Gzip compresses response bodies of at least minSize bytes for clients that
+// accept gzip. Smaller bodies and responses that already carry a
+// Content-Encoding are sent unchanged. A panic in a later handler drops the
+// buffered body without committing a status and is passed on, so Recovery
+// registered before Gzip can still answer 500.
+func Gzip(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &gzipResponseWriter{ResponseWriter: original, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			c.Writer = original
+			if recovered := recover(); recovered != nil {
+				writer.discard()
+				panic(recovered)
+			}
+			writer.finish()
+		}()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		rejected := false
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				rejected = err == nil && q == 0
+			}
+		}
+		if !rejected {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the body until it reaches minSize, then switches
+// to streaming it through gzip. Headers are only committed once that decision
+// has been made, so Content-Encoding can still be set.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	direct  bool
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(data)
+	case w.direct:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() < w.minSize {
+		return len(data), nil
+	}
+
+	if w.Header().Get("Content-Encoding") != "" {
+		w.direct = true
+		_, err := w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return len(data), err
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.gz = gzipWriters.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return len(data), err
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush pushes compressed data to the client once compression has started;
+// a body still below minSize stays buffered until the size decision is made
+func (w *gzipResponseWriter) Flush() {
+	switch {
+	case w.gz != nil:
+		_ = w.gz.Flush()
+	case !w.direct:
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+// WriteHeaderNow is deferred until the body size is known
+func (w *gzipResponseWriter) WriteHeaderNow() {}
+
+// finish flushes whatever is still buffered, compressed or not
+func (w *gzipResponseWriter) finish() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+		return
+	}
+
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// discard drops whatever is still buffered without writing the status, for a
+// handler that panicked. Compressed data already sent cannot be taken back.
+func (w *gzipResponseWriter) discard() {
+	if w.gz != nil {
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+	}
+	w.buf.Reset()
+}
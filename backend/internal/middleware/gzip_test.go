@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+const gzipMinSize = 64
+
+// gzipRouter serves a JSON and a CSV body of size bytes behind Recovery and Gzip
+func gzipRouter(size int) *gin.Engine {
+	router := gin.New()
+	router.Use(Recovery(slog.New(slog.NewTextHandler(io.Discard, nil))), Gzip(gzipMinSize))
+	router.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("x", size)})
+	})
+	router.GET("/csv", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		for i := 0; i < size/10; i++ {
+			c.Writer.WriteString("row,12345\n")
+		}
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv")
+		c.Writer.WriteString("partial")
+		panic("boom")
+	})
+	return router
+}
+
+func gzipRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("open gzip body: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress body: %v", err)
+	}
+	return plain
+}
+
+func TestGzipCompressesLargeBodies(t *testing.T) {
+	for _, path := range []string{"/json", "/csv"} {
+		t.Run(path, func(t *testing.T) {
+			router := gzipRouter(1000)
+			plain := gzipRequest(router, path, "").Body.Bytes()
+
+			w := gzipRequest(router, path, "deflate, gzip;q=0.8")
+			if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "gzip" {
+				t.Fatalf("status %d with Content-Encoding %q, want a gzip 200", w.Code, w.Header().Get("Content-Encoding"))
+			}
+			if got := gunzip(t, w.Body.Bytes()); !bytes.Equal(got, plain) {
+				t.Errorf("decompressed body differs from the plain one: %d bytes, want %d", len(got), len(plain))
+			}
+			if w.Body.Len() >= len(plain) {
+				t.Errorf("compressed body is %d bytes, no smaller than the %d plain ones", w.Body.Len(), len(plain))
+			}
+		})
+	}
+}
+
+func TestGzipLeavesBodiesUncompressed(t *testing.T) {
+	tests := []struct {
+		name           string
+		size           int
+		acceptEncoding string
+	}{
+		{name: "not accepted", size: 1000},
+		{name: "refused", size: 1000, acceptEncoding: "gzip;q=0"},
+		{name: "below the minimum size", size: 10, acceptEncoding: "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gzipRequest(gzipRouter(tt.size), "/json", tt.acceptEncoding)
+			if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+				t.Errorf("status %d with Content-Encoding %q, want a plain 200", w.Code, w.Header().Get("Content-Encoding"))
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Errorf("body %q is not plain JSON", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGzipPanicLeavesRecoveryToAnswer(t *testing.T) {
+	w := gzipRequest(gzipRouter(0), "/panic", "gzip")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q on the error body", enc)
+	}
+	var body models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	if body.Code != models.ErrCodeInternal {
+		t.Errorf("code = %q, want %q", body.Code, models.ErrCodeInternal)
+	}
+}
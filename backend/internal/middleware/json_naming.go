@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CamelCaseJSON renames the object keys of JSON request and response bodies
+// between the snake_case the API is written in and camelCase, for clients that
+// expect the latter. Request bodies are rewritten as the handler reads them, so
+// body limits further down still apply; JSON responses are buffered and rewritten
+// once the handler is done. Other content, such as streamed exports, passes
+// through untouched.
+func CamelCaseJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body != nil && isJSON(c.GetHeader("Content-Type")) {
+			c.Request.Body = &renamedBody{
+				ReadCloser: c.Request.Body,
+				renamer:    newKeyRenamer(c.Request.Body, camelToSnake),
+			}
+		}
+
+		original := c.Writer
+		writer := &camelCaseWriter{ResponseWriter: original}
+		c.Writer = writer
+		// On a panic the buffered body is dropped so recovery can answer cleanly
+		defer func() { c.Writer = original }()
+
+		c.Next()
+		writer.finish()
+	}
+}
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(strings.TrimSpace(contentType), "application/json")
+}
+
+// renamedBody streams a request body with its object keys renamed
+type renamedBody struct {
+	io.ReadCloser
+	renamer *keyRenamer
+}
+
+func (b *renamedBody) Read(p []byte) (int, error) {
+	for b.renamer.out.Len() == 0 {
+		if err := b.renamer.next(); err != nil {
+			return 0, err
+		}
+	}
+	return b.renamer.out.Read(p)
+}
+
+// camelCaseWriter holds back JSON response bodies so their keys can be renamed
+// before anything is sent. The decision is made on the first write, when the
+// handler has set the Content-Type.
+type camelCaseWriter struct {
+	gin.ResponseWriter
+	buf     bytes.Buffer
+	decided bool
+	direct  bool
+}
+
+func (w *camelCaseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.direct = !isJSON(w.Header().Get("Content-Type"))
+	}
+	if w.direct {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+func (w *camelCaseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush passes through for streamed responses; buffered JSON is only sent by finish
+func (w *camelCaseWriter) Flush() {
+	if w.direct {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// WriteHeaderNow is deferred while a JSON body is being held back
+func (w *camelCaseWriter) WriteHeaderNow() {
+	if w.direct {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// finish writes the held-back body with its keys renamed. A body that does not
+// parse as JSON is sent as it was.
+func (w *camelCaseWriter) finish() {
+	if w.direct || w.buf.Len() == 0 {
+		return
+	}
+
+	body := w.buf.Bytes()
+	renamer := newKeyRenamer(bytes.NewReader(body), snakeToCamel)
+	var err error
+	for err == nil {
+		err = renamer.next()
+	}
+	if errors.Is(err, io.EOF) {
+		body = renamer.out.Bytes()
+	}
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// keyRenamer re-encodes a JSON stream one token at a time, renaming object keys
+type keyRenamer struct {
+	dec    *json.Decoder
+	rename func(string) string
+	out    bytes.Buffer
+	// stack holds, per open object or array, whether it is an object and how
+	// many keys and values it has seen, to place separators and spot keys
+	stack []struct {
+		object bool
+		count  int
+	}
+}
+
+func newKeyRenamer(r io.Reader, rename func(string) string) *keyRenamer {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &keyRenamer{dec: dec, rename: rename}
+}
+
+// next appends the next token, with its leading separator, to out. It returns
+// io.EOF at the end of the input.
+func (k *keyRenamer) next() error {
+	token, err := k.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+		k.out.WriteByte(byte(delim))
+		k.stack = k.stack[:len(k.stack)-1]
+		return nil
+	}
+
+	isKey := false
+	if n := len(k.stack); n > 0 {
+		top := &k.stack[n-1]
+		switch {
+		case top.object && top.count%2 == 1:
+			k.out.WriteByte(':')
+		case top.count > 0:
+			k.out.WriteByte(',')
+		}
+		isKey = top.object && top.count%2 == 0
+		top.count++
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		k.out.WriteByte(byte(value))
+		k.stack = append(k.stack, struct {
+			object bool
+			count  int
+		}{object: value == '{'})
+	case string:
+		if isKey {
+			value = k.rename(value)
+		}
+		encoded, _ := json.Marshal(value)
+		k.out.Write(encoded)
+	case json.Number:
+		k.out.WriteString(value.String())
+	case bool:
+		k.out.WriteString(strconv.FormatBool(value))
+	case nil:
+		k.out.WriteString("null")
+	}
+	return nil
+}
+
+// snakeToCamel turns order_id into orderId
+func snakeToCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelToSnake turns orderId into order_id
+func camelToSnake(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"abt-analytics/internal/models"
+)
+
+// Roles carried in the JWT role claim
+const (
+	RoleAnalyst = "analyst"
+	RoleAdmin   = "admin"
+)
+
+// ClaimsKey is the gin context key holding the validated *Claims
+const ClaimsKey = "jwt_claims"
+
+// Claims are the JWT claims the API understands
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWTAuth validates the HS256 bearer token in the Authorization header against
+// secret and stores its claims in the context. Missing, malformed and expired
+// tokens get 401, as do tokens without an exp claim, which would never expire;
+// valid tokens whose role is not in roles get 403.
+func JWTAuth(secret []byte, roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		raw := strings.TrimPrefix(header, "Bearer ")
+		if header == "" || raw == header {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Missing bearer token")
+			return
+		}
+
+		claims := &Claims{}
+		if _, err := parser.ParseWithClaims(raw, claims, keyFunc); err != nil {
+			message := "Invalid bearer token"
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				message = "Bearer token has expired"
+			}
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, message)
+			return
+		}
+		if claims.ExpiresAt == nil {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Bearer token has no expiry")
+			return
+		}
+
+		if !allowed[claims.Role] {
+			abortWithError(c, http.StatusForbidden, models.ErrCodeForbidden, "Role not permitted")
+			return
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose JWT claims, stored by JWTAuth, carry none of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if ok {
+			for _, role := range roles {
+				if claims.Role == role {
+					c.Next()
+					return
+				}
+			}
+		}
+		abortWithError(c, http.StatusForbidden, models.ErrCodeForbidden, "Role not permitted")
+	}
+}
+
+// GetClaims returns the claims stored by JWTAuth, if any
+func GetClaims(c *gin.Context) (*Claims, bool) {
+	value, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
+
+// MintToken signs an HS256 token for subject with the given role, expiring after ttl
+func MintToken(secret []byte, subject, role string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"abt-analytics/internal/models"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// mintToken signs a token for the test secret, failing the test on error
+func mintToken(t *testing.T, role string, ttl time.Duration) string {
+	t.Helper()
+	token, err := MintToken(testSecret, "user-1", role, ttl)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+	return token
+}
+
+func TestJWTAuth(t *testing.T) {
+	router := gin.New()
+	router.Use(JWTAuth(testSecret, RoleAnalyst, RoleAdmin))
+	router.GET("/", func(c *gin.Context) {
+		claims, _ := GetClaims(c)
+		c.String(http.StatusOK, claims.Role)
+	})
+
+	withoutExpiry, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		Role:             RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"},
+	}).SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSecret, err := MintToken([]byte("another-secret-another-secret-32"), "user-1", RoleAdmin, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		message       string
+	}{
+		{name: "missing", status: http.StatusUnauthorized, message: "Missing bearer token"},
+		{name: "not a bearer token", authorization: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized, message: "Missing bearer token"},
+		{name: "malformed", authorization: "Bearer not.a.token", status: http.StatusUnauthorized, message: "Invalid bearer token"},
+		{name: "wrong secret", authorization: "Bearer " + otherSecret, status: http.StatusUnauthorized, message: "Invalid bearer token"},
+		{name: "expired", authorization: "Bearer " + mintToken(t, RoleAnalyst, -time.Minute), status: http.StatusUnauthorized, message: "Bearer token has expired"},
+		{name: "without expiry", authorization: "Bearer " + withoutExpiry, status: http.StatusUnauthorized, message: "Bearer token has no expiry"},
+		{name: "wrong role", authorization: "Bearer " + mintToken(t, "viewer", time.Hour), status: http.StatusForbidden, message: "Role not permitted"},
+		{name: "analyst", authorization: "Bearer " + mintToken(t, RoleAnalyst, time.Hour), status: http.StatusOK},
+		{name: "admin", authorization: "Bearer " + mintToken(t, RoleAdmin, time.Hour), status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.message == "" {
+				return
+			}
+			var body models.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", w.Body.String(), err)
+			}
+			if body.Message != tt.message {
+				t.Errorf("message = %q, want %q", body.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	router := gin.New()
+	router.Use(JWTAuth(testSecret, RoleAnalyst, RoleAdmin), RequireRole(RoleAdmin))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for role, want := range map[string]int{RoleAnalyst: http.StatusForbidden, RoleAdmin: http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+mintToken(t, role, time.Hour))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", role, w.Code, want)
+		}
+	}
+}
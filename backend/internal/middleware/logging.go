@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sampleSummaryInterval is how often the number of request lines left out by
+// sampling is reported
+const sampleSummaryInterval = time.Minute
+
+// RequestLogger emits one structured log line per request with the method,
+// path, status, latency and request ID. Below a sampleRate of 1 only that share
+// of 2xx responses is logged, spread evenly over the requests served, while
+// every other status is always logged; how many lines were left out is logged
+// once a minute, with the first request after the minute has passed.
+func RequestLogger(logger *slog.Logger, sampleRate float64) gin.HandlerFunc {
+	var sampler *requestSampler
+	if sampleRate < 1 {
+		sampler = &requestSampler{rate: sampleRate, lastSummary: time.Now()}
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		defer func() {
+			status := c.Writer.Status()
+			if sampler != nil {
+				if suppressed, since := sampler.summary(time.Now()); suppressed > 0 {
+					logger.LogAttrs(c.Request.Context(), slog.LevelInfo, "requests not logged by sampling",
+						slog.Int64("suppressed", suppressed),
+						slog.Duration("since", since),
+						slog.Float64("sample_rate", sampleRate),
+					)
+				}
+				if status >= http.StatusOK && status < http.StatusMultipleChoices && !sampler.keep() {
+					return
+				}
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", c.Request.Method),
+				slog.String("path", path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("client_ip", c.ClientIP()),
+				slog.String("request_id", GetRequestID(c)),
+			}
+			if len(c.Errors) > 0 {
+				attrs = append(attrs, slog.String("errors", c.Errors.String()))
+			}
+			logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+		}()
+
+		c.Next()
+	}
+}
+
+// requestSampler picks which successful requests are logged. It keeps the nth
+// one whenever n*rate crosses a whole number, so exactly the configured share is
+// logged without depending on chance.
+type requestSampler struct {
+	mu          sync.Mutex
+	rate        float64
+	seen        int64
+	suppressed  int64
+	lastSummary time.Time
+}
+
+// keep reports whether the next successful request is logged, counting it as
+// suppressed when it is not
+func (s *requestSampler) keep() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen++
+	if math.Floor(float64(s.seen)*s.rate) > math.Floor(float64(s.seen-1)*s.rate) {
+		return true
+	}
+	s.suppressed++
+	return false
+}
+
+// summary returns the number of requests suppressed since the last summary and
+// how long ago that was, once sampleSummaryInterval has passed, and resets the count
+func (s *requestSampler) summary(now time.Time) (int64, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := now.Sub(s.lastSummary)
+	if since < sampleSummaryInterval {
+		return 0, 0
+	}
+	suppressed := s.suppressed
+	s.suppressed = 0
+	s.lastSummary = now
+	return suppressed, since
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newBufferLogger returns a JSON logger at debug level writing into a buffer
+func newBufferLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// logLines decodes each JSON line written to buf
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	for _, raw := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(raw) == 0 {
+			continue
+		}
+		var line map[string]interface{}
+		if err := json.Unmarshal(raw, &line); err != nil {
+			t.Fatalf("decode log line %q: %v", raw, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestLoggerFields(t *testing.T) {
+	logger, buf := newBufferLogger()
+	router := gin.New()
+	router.Use(RequestID("X-Request-ID"), RequestLogger(logger, 1))
+	router.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42?verbose=1", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1: %s", len(lines), buf)
+	}
+	line := lines[0]
+
+	want := map[string]interface{}{
+		"msg":        "request",
+		"level":      "WARN",
+		"method":     http.MethodGet,
+		"path":       "/items/42",
+		"status":     float64(http.StatusTeapot),
+		"request_id": "req-123",
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("%s = %v, want %v", key, line[key], value)
+		}
+	}
+	if _, ok := line["latency"].(float64); !ok {
+		t.Errorf("latency = %v, want a duration", line["latency"])
+	}
+}
+
+func TestRequestLoggerSampling(t *testing.T) {
+	const requests = 1000
+	tests := []struct {
+		rate   float64
+		logged int
+	}{
+		{rate: 0, logged: 0},
+		{rate: 0.01, logged: 10},
+		{rate: 0.1, logged: 100},
+		{rate: 0.25, logged: 250},
+		{rate: 0.5, logged: 500},
+		{rate: 1, logged: requests},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatFloat(tt.rate, 'f', -1, 64), func(t *testing.T) {
+			logger, buf := newBufferLogger()
+			router := gin.New()
+			router.Use(RequestLogger(logger, tt.rate))
+			router.GET("/status/:code", func(c *gin.Context) {
+				code, _ := strconv.Atoi(c.Param("code"))
+				c.Status(code)
+			})
+
+			// every tenth request fails one way or another, the rest succeed
+			failures := []int{http.StatusMovedPermanently, http.StatusNotFound, http.StatusInternalServerError}
+			wantFailures := 0
+			for i := 0; i < requests; i++ {
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/200", nil))
+				if i%10 == 0 {
+					code := failures[(i/10)%len(failures)]
+					router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/"+strconv.Itoa(code), nil))
+					wantFailures++
+				}
+			}
+
+			ok, failed := 0, 0
+			for _, line := range logLines(t, buf) {
+				if line["msg"] != "request" {
+					continue
+				}
+				if line["status"] == float64(http.StatusOK) {
+					ok++
+				} else {
+					failed++
+				}
+			}
+			if ok != tt.logged {
+				t.Errorf("logged %d of %d successful requests, want %d", ok, requests, tt.logged)
+			}
+			if failed != wantFailures {
+				t.Errorf("logged %d of %d failed requests, want all of them", failed, wantFailures)
+			}
+		})
+	}
+}
+
+func TestRequestSamplerSummarizesSuppressedRequests(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sampler := &requestSampler{rate: 0.5, lastSummary: start}
+	for i := 0; i < 10; i++ {
+		sampler.keep()
+	}
+
+	if suppressed, _ := sampler.summary(start.Add(sampleSummaryInterval - time.Second)); suppressed != 0 {
+		t.Errorf("summary before the interval = %d, want none", suppressed)
+	}
+	later := start.Add(sampleSummaryInterval + time.Second)
+	if suppressed, since := sampler.summary(later); suppressed != 5 || since != sampleSummaryInterval+time.Second {
+		t.Errorf("summary = %d since %s, want 5 since %s", suppressed, since, sampleSummaryInterval+time.Second)
+	}
+	// the count starts over with each summary
+	if suppressed, _ := sampler.summary(later.Add(2 * sampleSummaryInterval)); suppressed != 0 {
+		t.Errorf("summary after a quiet interval = %d, want none", suppressed)
+	}
+}
+
+func TestRecoveryLogsPanicAtErrorLevel(t *testing.T) {
+	logger, buf := newBufferLogger()
+	router := gin.New()
+	router.Use(RequestID("X-Request-ID"), Recovery(logger))
+	router.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	lines := logLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1: %s", len(lines), buf)
+	}
+	line := lines[0]
+	if line["level"] != "ERROR" || line["panic"] != "boom" {
+		t.Errorf("log line = %v, want the panic at error level", line)
+	}
+	if stack, _ := line["stack"].(string); stack == "" {
+		t.Error("stack missing from the log line")
+	}
+	if line["request_id"] == "" || line["request_id"] == nil {
+		t.Error("request_id missing from the log line")
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"abt-analytics/internal/models"
+)
+
+const (
+	// limiterIdleTTL is how long a client can stay silent before its bucket is dropped
+	limiterIdleTTL = 10 * time.Minute
+	// limiterSweepInterval is how often idle buckets are looked for
+	limiterSweepInterval = time.Minute
+)
+
+// RateLimiter hands out one token bucket per client IP
+type RateLimiter struct {
+	mu        sync.Mutex
+	rps       rate.Limit
+	burst     int
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second per client with the given burst
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Middleware rejects requests over the client's budget with 429 and a Retry-After header.
+// Clients are keyed by gin's ClientIP, which honours X-Forwarded-For from trusted proxies.
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reservation := l.limiterFor(c.ClientIP()).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			abortWithError(c, http.StatusTooManyRequests, models.ErrCodeRateLimited, "Rate limit exceeded, retry later")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// limiterFor returns the bucket for ip, creating it on first use and
+// periodically dropping buckets of clients that have gone idle
+func (l *RateLimiter) limiterFor(ip string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= limiterSweepInterval {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > limiterIdleTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.clients[ip] = client
+	}
+	client.lastSeen = now
+	return client.limiter
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limitedRouter serves / behind a limiter of rps and burst
+func limitedRouter(rps float64, burst int) *gin.Engine {
+	router := gin.New()
+	router.Use(NewRateLimiter(rps, burst).Middleware())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func requestFrom(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiterRejectsPastBurst(t *testing.T) {
+	const burst = 3
+	router := limitedRouter(0.5, burst)
+
+	for i := 0; i < burst; i++ {
+		if w := requestFrom(router, "203.0.113.1"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d within the burst: status = %d", i+1, w.Code)
+		}
+	}
+
+	w := requestFrom(router, "203.0.113.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request past the burst: status = %d, want 429", w.Code)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 2 {
+		t.Errorf("Retry-After = %q, want the 2s until the next token", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimiterKeysByClient(t *testing.T) {
+	router := limitedRouter(0.5, 1)
+
+	if w := requestFrom(router, "203.0.113.1"); w.Code != http.StatusNoContent {
+		t.Fatalf("first client: status = %d", w.Code)
+	}
+	if w := requestFrom(router, "203.0.113.1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("first client again: status = %d, want 429", w.Code)
+	}
+	if w := requestFrom(router, "203.0.113.2"); w.Code != http.StatusNoContent {
+		t.Errorf("second client: status = %d, want its own bucket", w.Code)
+	}
+}
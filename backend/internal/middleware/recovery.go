@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// Recovery turns a panic in a later handler into a 500 with the standard error
+// body and the request ID, logging the panic value and stack at error level. The
+// stack only goes to the log, never to the client. If the handler had already
+// started the response its status can no longer change, so the panic is
+// re-raised as http.ErrAbortHandler: the server then drops the connection rather
+// than ending a truncated body as if it were complete.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose; let it through
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logger.ErrorContext(c.Request.Context(), "panic recovered",
+				slog.Any("panic", recovered),
+				slog.String("stack", string(debug.Stack())),
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String("request_id", GetRequestID(c)),
+			)
+			if c.Writer.Written() {
+				c.Abort()
+				panic(http.ErrAbortHandler)
+			}
+			abortWithError(c, http.StatusInternalServerError, models.ErrCodeInternal, "Internal server error")
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDKey is the gin context key holding the current request ID
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength bounds client-supplied IDs so they cannot bloat logs
+const maxRequestIDLength = 128
+
+// RequestID makes sure every request carries an ID under the given header.
+// An incoming ID is reused, otherwise a UUID is generated. The ID is stored
+// in the gin context under RequestIDKey and echoed on the response.
+func RequestID(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(header)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(header, id)
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID assigned to the current request by RequestID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDRouter echoes the request ID seen by the handler in the body
+func requestIDRouter(header string) *gin.Engine {
+	router := gin.New()
+	router.Use(RequestID(header))
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+	return router
+}
+
+func TestRequestIDPassesThrough(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Correlation-ID", "gateway-42")
+	w := httptest.NewRecorder()
+	requestIDRouter("X-Correlation-ID").ServeHTTP(w, req)
+
+	if got := w.Header().Get("X-Correlation-ID"); got != "gateway-42" {
+		t.Errorf("response header = %q, want gateway-42", got)
+	}
+	if got := w.Body.String(); got != "gateway-42" {
+		t.Errorf("context ID = %q, want gateway-42", got)
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+	}{
+		{name: "absent", incoming: ""},
+		{name: "too long", incoming: strings.Repeat("x", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set("X-Request-ID", tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			requestIDRouter("X-Request-ID").ServeHTTP(w, req)
+
+			id := w.Header().Get("X-Request-ID")
+			if _, err := uuid.Parse(id); err != nil {
+				t.Errorf("response header = %q, want a generated UUID", id)
+			}
+			if got := w.Body.String(); got != id {
+				t.Errorf("context ID = %q, want the echoed %q", got, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/timing"
+)
+
+// ServerTimingHeader is the response header listing the timed operations
+const ServerTimingHeader = "Server-Timing"
+
+// ServerTiming attaches a timing.Recorder to the request context and reports the
+// operations it recorded in the Server-Timing header. The header is added just
+// before the response headers are committed, so it covers everything the handler
+// timed before writing its body. Requests that recorded nothing get no header.
+func ServerTiming() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recorder := &timing.Recorder{}
+		c.Request = c.Request.WithContext(timing.NewContext(c.Request.Context(), recorder))
+
+		original := c.Writer
+		writer := &serverTimingWriter{ResponseWriter: original, recorder: recorder}
+		c.Writer = writer
+		defer func() {
+			writer.addHeader()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+// serverTimingWriter sets the Server-Timing header once, on the first write
+type serverTimingWriter struct {
+	gin.ResponseWriter
+	recorder *timing.Recorder
+	done     bool
+}
+
+func (w *serverTimingWriter) addHeader() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+	if value := w.recorder.Header(); value != "" {
+		w.Header().Set(ServerTimingHeader, value)
+	}
+}
+
+func (w *serverTimingWriter) Write(data []byte) (int, error) {
+	w.addHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.addHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.addHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
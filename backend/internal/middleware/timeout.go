@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"abt-analytics/internal/models"
+)
+
+// Timeout gives every request a context deadline of d. Handlers pass the request
+// context down to the database, so a slow query is canceled once the deadline
+// passes; if the handler has not responded by then, the request gets a 504.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			abortWithError(c, http.StatusGatewayTimeout, models.ErrCodeTimeout, "Request timed out")
+		}
+	}
+}
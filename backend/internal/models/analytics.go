@@ -0,0 +1,384 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Revenue rows carry the currency code only when a conversion was requested;
+// otherwise amounts are in the base currency.
+// Amounts are Money, rendered as strings with two decimals.
+
+// CountryRevenue represents the total revenue and order count for a country and
+// its percentage share of the revenue across all countries in the window
+type CountryRevenue struct {
+	Country    string  `json:"country"`
+	Revenue    Money   `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Orders     int64   `json:"order_count"`
+	Percentage float64 `json:"percentage"`
+	Currency   string  `json:"currency,omitempty"`
+}
+
+// ProductRevenue represents the total revenue and units sold for a product
+type ProductRevenue struct {
+	Product  string `json:"product"`
+	Units    int64  `json:"units"`
+	Revenue  Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// CustomerRevenue represents the total revenue and number of orders of one customer
+type CustomerRevenue struct {
+	CustomerID string `json:"customer_id"`
+	Orders     int64  `json:"order_count"`
+	Revenue    Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency   string `json:"currency,omitempty"`
+}
+
+// Keys products can be ranked by
+const (
+	ProductSortRevenue = "revenue"
+	ProductSortUnits   = "units"
+)
+
+// ErrUnknownSortField is returned when results are asked to be sorted by a field
+// that maps to no column
+var ErrUnknownSortField = errors.New("unknown sort field")
+
+// ProductSort selects the ranking key and direction for top products
+type ProductSort struct {
+	By        string
+	Ascending bool
+}
+
+// CountryOrderValue represents the average revenue per transaction in a country
+type CountryOrderValue struct {
+	Country           string `json:"country"`
+	AverageOrderValue Money  `json:"average_order_value" swaggertype:"string" example:"1234.50"`
+	Orders            int64  `json:"order_count"`
+	Currency          string `json:"currency,omitempty"`
+}
+
+// OrderValuePercentiles describes the distribution of per-transaction revenue with
+// nearest-rank percentiles: each is the smallest order value at least that share
+// of the orders do not exceed. They are null when there are no orders.
+type OrderValuePercentiles struct {
+	Orders   int64  `json:"order_count"`
+	P50      *Money `json:"p50" swaggertype:"string" example:"120.00"`
+	P90      *Money `json:"p90" swaggertype:"string" example:"480.00"`
+	P95      *Money `json:"p95" swaggertype:"string" example:"760.00"`
+	P99      *Money `json:"p99" swaggertype:"string" example:"1450.00"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// NewOrderValuePercentiles computes the percentiles of orders ascending order values,
+// reading the value at each 1-based rank with valueAt
+func NewOrderValuePercentiles(orders int64, valueAt func(rank int64) (Money, error)) (*OrderValuePercentiles, error) {
+	p := &OrderValuePercentiles{Orders: orders}
+	if orders == 0 {
+		return p, nil
+	}
+
+	for _, level := range []struct {
+		percentile int64
+		value      **Money
+	}{{50, &p.P50}, {90, &p.P90}, {95, &p.P95}, {99, &p.P99}} {
+		// Nearest rank: the smallest rank covering the percentile, in integer arithmetic
+		value, err := valueAt((level.percentile*orders + 99) / 100)
+		if err != nil {
+			return nil, err
+		}
+		*level.value = &value
+	}
+	return p, nil
+}
+
+// RevenueConcentration describes how revenue is spread over products: every
+// product ranked by revenue with its share of the total and the cumulative share
+// of it and the products above it, and how many of the top products it takes to
+// reach each threshold share
+type RevenueConcentration struct {
+	TotalRevenue Money                    `json:"total_revenue" swaggertype:"string" example:"1234.50"`
+	Products     []ProductShare           `json:"products"`
+	Thresholds   []ConcentrationThreshold `json:"thresholds"`
+	Currency     string                   `json:"currency,omitempty"`
+}
+
+// ProductShare is one product of a RevenueConcentration. Shares are percentages
+// of the total revenue.
+type ProductShare struct {
+	Product         string  `json:"product"`
+	Revenue         Money   `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Share           float64 `json:"share"`
+	CumulativeShare float64 `json:"cumulative_share"`
+}
+
+// ConcentrationThreshold is the number of top products whose revenue makes up at
+// least Threshold percent of the total
+type ConcentrationThreshold struct {
+	Threshold float64 `json:"threshold" example:"80"`
+	Products  int     `json:"products"`
+}
+
+// PeriodTotals is the number of transactions and their total revenue within a date range
+type PeriodTotals struct {
+	Orders  int64
+	Revenue Money
+}
+
+// PeriodValue is one side of a PeriodComparison: the range compared and the
+// metric's value over it. Values are decimal strings, with two decimals for
+// revenue and average order value; the latter is null for a period without orders.
+type PeriodValue struct {
+	From  *time.Time `json:"from"`
+	To    *time.Time `json:"to"`
+	Value *string    `json:"value" example:"1234.50"`
+}
+
+// PeriodComparison compares one metric over two periods. Difference is B minus A
+// and PercentChange is that difference as a percentage of A; both are null when
+// either value is, and the percentage also when A is zero.
+type PeriodComparison struct {
+	Metric        string      `json:"metric" example:"revenue"`
+	A             PeriodValue `json:"a"`
+	B             PeriodValue `json:"b"`
+	Difference    *string     `json:"difference" example:"250.00"`
+	PercentChange *float64    `json:"percent_change" example:"20.25"`
+	Currency      string      `json:"currency,omitempty"`
+}
+
+// CategoryRevenue represents the total revenue for a product category
+type CategoryRevenue struct {
+	Category string `json:"category"`
+	Revenue  Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// ProductRevenuePage is one page of ranked products plus the total number of products
+type ProductRevenuePage struct {
+	Data   []ProductRevenue `json:"data"`
+	Total  int64            `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+	Links  *PageLinks       `json:"links,omitempty"`
+}
+
+// CustomerRevenuePage is one page of ranked customers plus the total number of customers
+type CustomerRevenuePage struct {
+	Data   []CustomerRevenue `json:"data"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+	Links  *PageLinks        `json:"links,omitempty"`
+}
+
+// PageLinks holds the URLs of a page and its neighbours, each a path with the
+// request's query parameters. Next and Prev are omitted at the boundaries.
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// MonthlySales represents the total revenue for a sales period,
+// labelled YYYY-MM for months or YYYY-Qn for quarters. With a year-over-year
+// comparison PriorRevenue holds the same period a year earlier, and
+// PercentChange is omitted when there was no prior revenue.
+type MonthlySales struct {
+	Period        string   `json:"period"`
+	Revenue       Money    `json:"revenue" swaggertype:"string" example:"1234.50"`
+	PriorRevenue  *Money   `json:"prior_revenue,omitempty" swaggertype:"string" example:"1234.50"`
+	PercentChange *float64 `json:"percent_change,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+}
+
+// RevenueGrowth represents a month's revenue and its percentage change from the
+// month before. GrowthPct is null for the first month and after a month without revenue.
+type RevenueGrowth struct {
+	Period    string   `json:"period"`
+	Revenue   Money    `json:"revenue" swaggertype:"string" example:"1234.50"`
+	GrowthPct *float64 `json:"growth_pct"`
+	Currency  string   `json:"currency,omitempty"`
+}
+
+// RevenueForecast represents a month's revenue, as recorded or, when Forecast is
+// set, as projected from the trend of the months before it
+type RevenueForecast struct {
+	Period   string `json:"period"`
+	Revenue  Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Forecast bool   `json:"forecast"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// DailyRevenue represents the total revenue of one UTC calendar day, labelled YYYY-MM-DD
+type DailyRevenue struct {
+	Date     string `json:"date" example:"2023-01-15"`
+	Revenue  Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// RegionRevenue represents the total revenue and order count for a region
+type RegionRevenue struct {
+	Region   string `json:"region"`
+	Revenue  Money  `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Orders   int64  `json:"order_count"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// RegionMonthRevenue represents the revenue of one region in one YYYY-MM month
+type RegionMonthRevenue struct {
+	Region  string
+	Period  string
+	Revenue Money
+}
+
+// RegionTrend represents the monthly revenue of one region and its total. Every
+// series in a response covers the same consecutive months, with zero revenue for
+// the months the region sold nothing, so they can be charted on a common axis.
+type RegionTrend struct {
+	Region   string         `json:"region"`
+	Revenue  Money          `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency string         `json:"currency,omitempty"`
+	Series   []MonthlySales `json:"series"`
+}
+
+// DataMeta describes how current the analytics data is.
+// LastTransactionDate is null while there are no transactions. DefaultRange is
+// the window requests giving neither from nor to currently cover, null when they
+// cover the full history.
+type DataMeta struct {
+	LastTransactionDate *time.Time  `json:"last_transaction_date"`
+	TotalTransactions   int64       `json:"total_transactions"`
+	DefaultRange        *DateWindow `json:"default_range"`
+}
+
+// Dimensions lists the distinct values of each field analytics can be filtered
+// by, sorted alphabetically, for populating filter controls
+type Dimensions struct {
+	Countries  []string `json:"countries"`
+	Regions    []string `json:"regions"`
+	Products   []string `json:"products"`
+	Categories []string `json:"categories"`
+}
+
+// DateRange is an optional time window used to scope analytics queries.
+// A nil bound is treated as open-ended.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// DateWindow is an inclusive date window with both bounds set
+type DateWindow struct {
+	From time.Time `json:"from" example:"2024-01-01T00:00:00Z"`
+	To   time.Time `json:"to" example:"2024-03-31T23:59:59.999999999Z"`
+}
+
+// Window returns the range as a DateWindow, or nil unless both bounds are set
+func (r DateRange) Window() *DateWindow {
+	if r.From == nil || r.To == nil {
+		return nil
+	}
+	return &DateWindow{From: *r.From, To: *r.To}
+}
+
+// TrailingDays returns the window covering the last days UTC days, the day of now
+// included, that DEFAULT_RANGE_DAYS applies to requests giving neither bound
+func TrailingDays(days int, now time.Time) DateRange {
+	today := now.UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, 1-days)
+	to := today.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return DateRange{From: &from, To: &to}
+}
+
+// Conversion says how revenue is reported: in Currency, or the base currency when
+// it is empty. Normalize allows summing transactions recorded in other currencies
+// by converting them to the base currency first; without it their presence is an
+// error rather than being added up as if they were in the base currency.
+type Conversion struct {
+	Currency  string
+	Normalize bool
+}
+
+// TransactionFilter narrows a transaction listing; empty fields are not applied
+type TransactionFilter struct {
+	Country   string
+	Product   string
+	Region    string
+	DateRange DateRange
+	// UpdatedSince keeps the transactions stored or updated after it, for
+	// incremental syncs. Listings using it run in order of change, oldest first.
+	UpdatedSince *time.Time
+}
+
+// CountryFilter scopes a country breakdown to a set of countries; an empty set keeps all of them
+type CountryFilter struct {
+	Countries []string
+	DateRange DateRange
+}
+
+// ProductFilter scopes a product ranking; empty fields are not applied
+type ProductFilter struct {
+	Country   string
+	DateRange DateRange
+}
+
+// RegionFilter scopes a region ranking; empty fields are not applied
+type RegionFilter struct {
+	Country   string
+	DateRange DateRange
+}
+
+// SalesFilter scopes a sales trend to one product line; empty fields are not applied
+type SalesFilter struct {
+	Product   string
+	Category  string
+	DateRange DateRange
+	// Location is the time zone whose calendar months the sales are bucketed into,
+	// UTC when nil
+	Location *time.Location
+}
+
+// TimeZone returns the location months are bucketed in, defaulting to UTC
+func (f SalesFilter) TimeZone() *time.Location {
+	if f.Location == nil {
+		return time.UTC
+	}
+	return f.Location
+}
+
+// TransactionCursor marks the last transaction of a page so that the next page
+// can continue right after it in listing order. Cursors of listings filtered by
+// UpdatedSince carry the UpdatedAt of that transaction, all others its TransactionDate.
+type TransactionCursor struct {
+	TransactionDate time.Time
+	UpdatedAt       time.Time
+	ID              uint
+}
+
+// TransactionPage is one page of transactions plus the number of rows matching the filter.
+// NextCursor fetches the following page and is empty on the last one.
+type TransactionPage struct {
+	Data       []TransactionResponse `json:"data"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	NextCursor string                `json:"next_cursor"`
+	Links      *PageLinks            `json:"links,omitempty"`
+}
+
+// DashboardSummary bundles the dashboard aggregates into one response.
+// Sections that failed to load are left empty and listed in Errors.
+type DashboardSummary struct {
+	CountryRevenue []CountryRevenue    `json:"country_revenue"`
+	TopProducts    *ProductRevenuePage `json:"top_products"`
+	MonthlySales   []MonthlySales      `json:"monthly_sales"`
+	TopRegions     []RegionRevenue     `json:"top_regions"`
+	Errors         []SectionError      `json:"errors,omitempty"`
+}
+
+// SectionError reports a dashboard section that could not be loaded
+type SectionError struct {
+	Section string `json:"section"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDuplicateOrder is returned when an inserted transaction reuses an existing order ID
+var ErrDuplicateOrder = errors.New("order ID already exists")
+
+// TransactionInput is one transaction submitted for ingestion.
+// Category, OrderID and CustomerID are optional; a zero Quantity means one unit
+// and an empty Currency the base currency.
+type TransactionInput struct {
+	OrderID         string    `json:"order_id"`
+	CustomerID      string    `json:"customer_id"`
+	TransactionDate time.Time `json:"transaction_date"`
+	Country         string    `json:"country"`
+	Region          string    `json:"region"`
+	Product         string    `json:"product"`
+	Category        string    `json:"category"`
+	Quantity        int       `json:"quantity"`
+	Revenue         Money     `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency        string    `json:"currency" example:"EUR"`
+}
+
+// RowError reports why one row of a submitted batch was rejected
+type RowError struct {
+	Index   int    `json:"index"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// BatchInsertResult is the body returned after a batch was stored
+type BatchInsertResult struct {
+	Inserted int `json:"inserted"`
+}
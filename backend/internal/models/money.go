@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// moneyPlaces is the number of decimals amounts are rendered with
+const moneyPlaces = 2
+
+// Money is an exact decimal amount, so sums and conversions never drift the way
+// floats do. It is stored in DECIMAL columns and rendered in JSON as a string
+// with two decimals, e.g. "1234.50"; both strings and numbers are accepted on input.
+type Money struct {
+	decimal.Decimal
+}
+
+// NewMoney wraps an exact decimal amount
+func NewMoney(amount decimal.Decimal) Money {
+	return Money{Decimal: amount}
+}
+
+// MarshalJSON renders the amount as a string rounded to two decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Format())
+}
+
+// Format returns the amount rounded to two decimals in plain notation
+func (m Money) Format() string {
+	return m.StringFixed(moneyPlaces)
+}
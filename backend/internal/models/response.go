@@ -0,0 +1,132 @@
+package models
+
+// Error codes used in ErrorResponse.Code
+const (
+	ErrCodeInvalidParameter   = "invalid_parameter"
+	ErrCodeValidationFailed   = "validation_failed"
+	ErrCodeConflict           = "conflict"
+	ErrCodeInsufficientData   = "insufficient_data"
+	ErrCodeUnauthorized       = "unauthorized"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeNotAcceptable      = "not_acceptable"
+	ErrCodePayloadTooLarge    = "payload_too_large"
+	ErrCodeRateLimited        = "rate_limited"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeTimeout            = "timeout"
+	ErrCodeServiceUnavailable = "service_unavailable"
+)
+
+// ErrorResponse is the body of every 4xx/5xx response
+type ErrorResponse struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// Envelope wraps a response body with the version of its shape, for clients
+// that request one with the X-API-Version header. Meta is left out when there is
+// nothing to say about how the data was produced.
+type Envelope struct {
+	APIVersion string        `json:"api_version" example:"1"`
+	Data       interface{}   `json:"data"`
+	Meta       *ResponseMeta `json:"meta,omitempty"`
+}
+
+// ResponseMeta describes how the data of an Envelope was produced. DefaultRange
+// is the trailing DEFAULT_RANGE_DAYS window the data covers because the request
+// gave neither from nor to.
+type ResponseMeta struct {
+	DefaultRange *DateWindow `json:"default_range"`
+}
+
+// FieldError describes one invalid request field in ErrorResponse.Details
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// HealthResponse is the body returned by the health check. Status is healthy,
+// degraded or unhealthy depending on which dependencies in Checks are down.
+type HealthResponse struct {
+	Status    string             `json:"status"`
+	Message   string             `json:"message"`
+	Timestamp string             `json:"timestamp"`
+	Checks    []DependencyHealth `json:"checks"`
+}
+
+// CacheFlushResult reports how many cached entries a flush evicted.
+// Scope is the group of results cleared, omitted when everything was.
+type CacheFlushResult struct {
+	Evicted int    `json:"evicted"`
+	Scope   string `json:"scope,omitempty"`
+}
+
+// DependencyHealth is the outcome of probing one dependency
+type DependencyHealth struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// VersionResponse identifies the running build
+type VersionResponse struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// ReadinessResponse is the body returned when the API is ready to serve traffic
+type ReadinessResponse struct {
+	Status string `json:"status"`
+}
+
+// MigrationStatus reports whether the database schema is current: Migrated is
+// true once every table and column the models need exists, and Missing lists
+// the absent tables and the absent columns of existing tables otherwise
+type MigrationStatus struct {
+	Migrated bool        `json:"migrated"`
+	Missing  []SchemaGap `json:"missing"`
+}
+
+// SchemaGap names a missing table, or a missing column of it when Column is set
+type SchemaGap struct {
+	Table  string `json:"table" example:"transactions"`
+	Column string `json:"column,omitempty" example:"currency"`
+}
+
+// DependencyError describes a failed dependency in ErrorResponse.Details
+type DependencyError struct {
+	Dependency string `json:"dependency"`
+	Error      string `json:"error"`
+}
+
+// AnalyticsCatalog lists the analytics endpoints the API serves, so clients can
+// discover them instead of hard-coding them
+type AnalyticsCatalog struct {
+	Endpoints []AnalyticsEndpoint `json:"endpoints"`
+}
+
+// AnalyticsEndpoint describes one analytics endpoint and the parameters it accepts
+type AnalyticsEndpoint struct {
+	Method  string          `json:"method" example:"GET"`
+	Path    string          `json:"path" example:"/api/v1/analytics/country-revenue"`
+	Summary string          `json:"summary"`
+	Params  []EndpointParam `json:"params"`
+}
+
+// EndpointParam describes a query or path parameter of an endpoint. Type is
+// string, integer, boolean or array, whose elements are of type Items; Enum lists
+// the accepted values when they are limited.
+type EndpointParam struct {
+	Name        string        `json:"name" example:"from"`
+	In          string        `json:"in" example:"query"`
+	Type        string        `json:"type" example:"string"`
+	Items       string        `json:"items,omitempty"`
+	Required    bool          `json:"required"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty" swaggertype:"array,string"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrTransactionNotFound is returned when no live transaction has the requested ID
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// Transaction represents a single sales transaction as it is stored; responses
+// carry it as a TransactionResponse.
+// OrderID is the natural key used to upsert seeded and imported rows; it is
+// nullable so rows created before it existed can coexist with the unique index.
+// CustomerID is optional; transactions without one are left out of customer rankings.
+// Currency is the code Revenue was recorded in; empty means the base currency, as
+// for rows stored before currencies were recorded.
+// CreatedAt and UpdatedAt are maintained by GORM on insert and upsert; rows stored
+// before they existed have neither and never match an UpdatedSince filter.
+// Voided transactions are soft-deleted through DeletedAt: they stay in the table
+// for auditing while every query through the model leaves them out.
+type Transaction struct {
+	ID              uint      `gorm:"primaryKey"`
+	OrderID         string    `gorm:"size:64;uniqueIndex;default:null"`
+	CustomerID      string    `gorm:"size:64;index;default:null"`
+	TransactionDate time.Time `gorm:"index;not null"`
+	Country         string    `gorm:"size:100;index;not null"`
+	Region          string    `gorm:"size:100;index;not null"`
+	Product         string    `gorm:"size:200;index;not null"`
+	Category        string    `gorm:"size:100;index;not null;default:''"`
+	Quantity        int       `gorm:"not null;default:1"`
+	Revenue         Money     `gorm:"type:decimal(14,2);not null"`
+	Currency        string    `gorm:"size:3;index;not null;default:''"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time      `gorm:"index"`
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+}
+
+// TransactionResponse is a transaction as the API returns it. It is kept apart
+// from Transaction so the response shape does not follow schema changes and
+// columns such as DeletedAt never reach clients.
+type TransactionResponse struct {
+	ID              uint       `json:"id"`
+	OrderID         string     `json:"order_id,omitempty"`
+	CustomerID      string     `json:"customer_id,omitempty"`
+	TransactionDate time.Time  `json:"transaction_date"`
+	Country         string     `json:"country"`
+	Region          string     `json:"region"`
+	Product         string     `json:"product"`
+	Category        string     `json:"category"`
+	Quantity        int        `json:"quantity"`
+	Revenue         Money      `json:"revenue" swaggertype:"string" example:"1234.50"`
+	Currency        string     `json:"currency,omitempty" example:"USD"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewTransactionResponse returns the API representation of t
+func NewTransactionResponse(t Transaction) TransactionResponse {
+	return TransactionResponse{
+		ID:              t.ID,
+		OrderID:         t.OrderID,
+		CustomerID:      t.CustomerID,
+		TransactionDate: t.TransactionDate,
+		Country:         t.Country,
+		Region:          t.Region,
+		Product:         t.Product,
+		Category:        t.Category,
+		Quantity:        t.Quantity,
+		Revenue:         t.Revenue,
+		Currency:        t.Currency,
+		CreatedAt:       optionalTime(t.CreatedAt),
+		UpdatedAt:       optionalTime(t.UpdatedAt),
+	}
+}
+
+// optionalTime returns nil for the zero time, as scanned from a NULL column
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,679 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+)
+
+const (
+	// pingTimeout bounds how long a readiness ping may wait for the database
+	pingTimeout = 2 * time.Second
+	// insertBatchSize is the number of rows sent per INSERT statement
+	insertBatchSize = 200
+)
+
+// AnalyticsRepository runs the aggregation queries over the transactions table.
+// Revenue is summed in the base currency: rows recorded in another currency are
+// divided by its exchange rate first.
+type AnalyticsRepository struct {
+	db *gorm.DB
+	// revenue is the SQL expression for a row's revenue in the base currency
+	revenue     string
+	revenueArgs []interface{}
+}
+
+// NewAnalyticsRepository creates a new analytics repository converting revenue
+// with rates, the multipliers from the base currency to each currency code
+func NewAnalyticsRepository(db *gorm.DB, rates map[string]float64) *AnalyticsRepository {
+	revenue, revenueArgs := revenueExpr(rates)
+	return &AnalyticsRepository{db: db, revenue: revenue, revenueArgs: revenueArgs}
+}
+
+// Ping checks that the database connection is alive, waiting at most pingTimeout
+func (r *AnalyticsRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	return sqlDB.PingContext(ctx)
+}
+
+// GetMigrationStatus compares the schema with the tables and columns the migrations create
+func (r *AnalyticsRepository) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	missing, err := database.MissingSchema(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &models.MigrationStatus{Migrated: len(missing) == 0, Missing: missing}, nil
+}
+
+// GetCountryRevenue returns the total revenue and order count per country matching
+// the filter, highest revenue first
+func (r *AnalyticsRepository) GetCountryRevenue(ctx context.Context, filter models.CountryFilter) ([]models.CountryRevenue, error) {
+	var results []models.CountryRevenue
+
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("country, SUM("+r.revenue+") AS revenue, COUNT(*) AS orders", r.revenueArgs...)
+	if len(filter.Countries) > 0 {
+		query = whereFoldedIn(query, "country", filter.Countries)
+	}
+	err := applyDateRange(query, filter.DateRange).
+		Group("country").
+		Order("revenue DESC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetAverageOrderValue returns the average revenue per transaction for each country
+// within the given range, highest first
+func (r *AnalyticsRepository) GetAverageOrderValue(ctx context.Context, dateRange models.DateRange) ([]models.CountryOrderValue, error) {
+	var results []models.CountryOrderValue
+
+	// NULLIF keeps the division safe should a group ever count zero rows
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("country, SUM("+r.revenue+") / NULLIF(COUNT(*), 0) AS average_order_value, COUNT(*) AS orders", r.revenueArgs...)
+	err := applyDateRange(query, dateRange).
+		Group("country").
+		Order("average_order_value DESC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetOrderValuePercentiles returns the nearest-rank percentiles of the revenue of the
+// transactions inside the range. Each percentile is read with its own ORDER BY ..
+// OFFSET query rather than a percentile or window function, so it works the same
+// on every dialect and never loads the individual order values.
+func (r *AnalyticsRepository) GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange) (*models.OrderValuePercentiles, error) {
+	var orders int64
+	if err := applyDateRange(r.db.WithContext(ctx).Model(&models.Transaction{}), dateRange).
+		Count(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return models.NewOrderValuePercentiles(orders, func(rank int64) (models.Money, error) {
+		var value models.Money
+		query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+			Select(r.revenue+" AS value", r.revenueArgs...)
+		err := applyDateRange(query, dateRange).
+			Order("value ASC").
+			Limit(1).
+			Offset(int(rank - 1)).
+			Scan(&value).Error
+		return value, err
+	})
+}
+
+// GetPeriodTotals returns the number of transactions within the range and their total revenue
+func (r *AnalyticsRepository) GetPeriodTotals(ctx context.Context, dateRange models.DateRange) (*models.PeriodTotals, error) {
+	var totals models.PeriodTotals
+
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("COUNT(*) AS orders, COALESCE(SUM("+r.revenue+"), 0) AS revenue", r.revenueArgs...)
+	err := applyDateRange(query, dateRange).Scan(&totals).Error
+
+	return &totals, err
+}
+
+// GetCategoryRevenue returns the total revenue per product category within the given range, highest first
+func (r *AnalyticsRepository) GetCategoryRevenue(ctx context.Context, dateRange models.DateRange) ([]models.CategoryRevenue, error) {
+	var results []models.CategoryRevenue
+
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("category, SUM("+r.revenue+") AS revenue", r.revenueArgs...)
+	err := applyDateRange(query, dateRange).
+		Group("category").
+		Order("revenue DESC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTopProducts returns one page of products matching the filter, ranked by total
+// revenue or units sold, along with the number of distinct products across all pages
+func (r *AnalyticsRepository) GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int) ([]models.ProductRevenue, int64, error) {
+	var results []models.ProductRevenue
+	var total int64
+
+	order, err := productOrder(sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.productQuery(ctx, filter).
+		Distinct("product").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err = r.productQuery(ctx, filter).
+		Select("product, SUM(quantity) AS units, SUM("+r.revenue+") AS revenue", r.revenueArgs...).
+		Group("product").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Scan(&results).Error
+
+	return results, total, err
+}
+
+// GetRankedProducts returns every product sold inside the range with its revenue,
+// highest first, ties broken by product name
+func (r *AnalyticsRepository) GetRankedProducts(ctx context.Context, dateRange models.DateRange) ([]models.ProductRevenue, error) {
+	var results []models.ProductRevenue
+
+	err := r.productQuery(ctx, models.ProductFilter{DateRange: dateRange}).
+		Select("product, SUM(quantity) AS units, SUM("+r.revenue+") AS revenue", r.revenueArgs...).
+		Group("product").
+		Order("revenue DESC").
+		Order("product ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTopCustomers returns one page of customers ranked by revenue, highest first,
+// plus the number of customers matching the range. Transactions without a customer
+// ID are left out; ties are broken by customer ID so pages are stable.
+func (r *AnalyticsRepository) GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int) ([]models.CustomerRevenue, int64, error) {
+	var results []models.CustomerRevenue
+	var total int64
+
+	if err := r.customerQuery(ctx, dateRange).
+		Distinct("customer_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.customerQuery(ctx, dateRange).
+		Select("customer_id, COUNT(*) AS orders, SUM("+r.revenue+") AS revenue", r.revenueArgs...).
+		Group("customer_id").
+		Order("revenue DESC").
+		Order("customer_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&results).Error
+
+	return results, total, err
+}
+
+// customerQuery starts a query over the transactions inside dateRange that have a customer ID
+func (r *AnalyticsRepository) customerQuery(ctx context.Context, dateRange models.DateRange) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("customer_id IS NOT NULL AND customer_id <> ''")
+	return applyDateRange(query, dateRange)
+}
+
+// productQuery starts a transactions query narrowed by the product filter
+func (r *AnalyticsRepository) productQuery(ctx context.Context, filter models.ProductFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
+	if filter.Country != "" {
+		query = whereFolded(query, "country", filter.Country)
+	}
+	return applyDateRange(query, filter.DateRange)
+}
+
+// GetMonthlySales returns the total revenue per month of the transactions matching
+// the filter, in chronological order. Months are calendar months in the filter's
+// time zone.
+func (r *AnalyticsRepository) GetMonthlySales(ctx context.Context, filter models.SalesFilter) ([]models.MonthlySales, error) {
+	var results []models.MonthlySales
+
+	period, periodArgs := r.monthExpr(), []interface{}(nil)
+	if loc := filter.TimeZone(); loc != time.UTC {
+		first, err := r.salesBound(ctx, filter, "transaction_date ASC")
+		if err != nil || first == nil {
+			return results, err
+		}
+		last, err := r.salesBound(ctx, filter, "transaction_date DESC")
+		if err != nil || last == nil {
+			return results, err
+		}
+		period, periodArgs = monthBucketExpr(*first, *last, loc)
+	}
+
+	err := r.salesQuery(ctx, filter).
+		Select(period+" AS period, SUM("+r.revenue+") AS revenue", append(periodArgs, r.revenueArgs...)...).
+		Group("period").
+		Order("period ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// salesBound returns the first transaction date matching the filter in the given
+// order, or nil when nothing matches
+func (r *AnalyticsRepository) salesBound(ctx context.Context, filter models.SalesFilter, order string) (*time.Time, error) {
+	var dates []time.Time
+	if err := r.salesQuery(ctx, filter).
+		Order(order).
+		Limit(1).
+		Pluck("transaction_date", &dates).Error; err != nil || len(dates) == 0 {
+		return nil, err
+	}
+	return &dates[0], nil
+}
+
+// salesQuery starts a transactions query narrowed by the sales filter
+func (r *AnalyticsRepository) salesQuery(ctx context.Context, filter models.SalesFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
+	if filter.Product != "" {
+		query = whereFolded(query, "product", filter.Product)
+	}
+	if filter.Category != "" {
+		query = whereFolded(query, "category", filter.Category)
+	}
+	return applyDateRange(query, filter.DateRange)
+}
+
+// GetDailyRevenue returns the total revenue per day of the transactions inside the
+// range, in chronological order. Days without transactions are left out.
+func (r *AnalyticsRepository) GetDailyRevenue(ctx context.Context, dateRange models.DateRange) ([]models.DailyRevenue, error) {
+	var results []models.DailyRevenue
+
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select(r.dayExpr()+" AS date, SUM("+r.revenue+") AS revenue", r.revenueArgs...)
+	err := applyDateRange(query, dateRange).
+		Group("date").
+		Order("date ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTopRegions returns the n regions with the highest total revenue inside the
+// filter's date range, with their order counts, limited to one country unless
+// filter.Country is empty. Ties are broken by region name so the ordering is stable.
+func (r *AnalyticsRepository) GetTopRegions(ctx context.Context, filter models.RegionFilter, n int) ([]models.RegionRevenue, error) {
+	var results []models.RegionRevenue
+
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
+	if filter.Country != "" {
+		query = whereFolded(query, "country", filter.Country)
+	}
+	err := applyDateRange(query, filter.DateRange).
+		Select("region, SUM("+r.revenue+") AS revenue, COUNT(*) AS orders", r.revenueArgs...).
+		Group("region").
+		Order("revenue DESC").
+		Order("region ASC").
+		Limit(n).
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetRegionTrends returns the revenue per month of the n regions with the highest
+// total revenue inside the range, ordered by month. Months are UTC calendar months
+// and months in which a region sold nothing are left out.
+func (r *AnalyticsRepository) GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int) ([]models.RegionMonthRevenue, error) {
+	var results []models.RegionMonthRevenue
+
+	var top []models.RegionRevenue
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("region, SUM("+r.revenue+") AS revenue", r.revenueArgs...)
+	if err := applyDateRange(query, dateRange).
+		Group("region").
+		Order("revenue DESC").
+		Order("region ASC").
+		Limit(n).
+		Scan(&top).Error; err != nil || len(top) == 0 {
+		return results, err
+	}
+	regions := make([]string, len(top))
+	for i, row := range top {
+		regions[i] = row.Region
+	}
+
+	query = r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("region, "+r.monthExpr()+" AS period, SUM("+r.revenue+") AS revenue", r.revenueArgs...).
+		Where("region IN ?", regions)
+	err := applyDateRange(query, dateRange).
+		Group("region, period").
+		Order("period ASC").
+		Order("region ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// ListTransactions returns one page of raw transactions matching the filter in
+// listing order, along with the number of matching rows across all pages.
+// A non-nil after starts the page right behind that transaction using a keyset
+// condition on the sort columns, so no rows have to be skipped.
+func (r *AnalyticsRepository) ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) ([]models.Transaction, int64, error) {
+	var results []models.Transaction
+	var total int64
+
+	query := r.transactionQuery(ctx, filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case after != nil && filter.UpdatedSince != nil:
+		query = query.Where("updated_at > ? OR (updated_at = ? AND id > ?)",
+			after.UpdatedAt, after.UpdatedAt, after.ID)
+	case after != nil:
+		query = query.Where("transaction_date < ? OR (transaction_date = ? AND id < ?)",
+			after.TransactionDate, after.TransactionDate, after.ID)
+	}
+	err := listingOrder(query, filter).
+		Limit(limit).
+		Offset(offset).
+		Find(&results).Error
+
+	return results, total, err
+}
+
+// GetTransaction returns the live transaction with the given ID, or
+// models.ErrTransactionNotFound when there is none or it was deleted
+func (r *AnalyticsRepository) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+
+	err := r.db.WithContext(ctx).First(&transaction, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// StreamTransactions calls fn with every transaction matching the filter in listing
+// order, reading them one row at a time so memory use does not grow with the result.
+// It stops at the first error returned by fn or the database.
+func (r *AnalyticsRepository) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error {
+	db := listingOrder(r.transactionQuery(ctx, filter), filter)
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction models.Transaction
+		if err := db.ScanRows(rows, &transaction); err != nil {
+			return err
+		}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// transactionQuery starts a transactions query narrowed by the listing filter
+func (r *AnalyticsRepository) transactionQuery(ctx context.Context, filter models.TransactionFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
+	if filter.Country != "" {
+		query = whereFolded(query, "country", filter.Country)
+	}
+	if filter.Product != "" {
+		query = whereFolded(query, "product", filter.Product)
+	}
+	if filter.Region != "" {
+		query = whereFolded(query, "region", filter.Region)
+	}
+	if filter.UpdatedSince != nil {
+		query = query.Where("updated_at > ?", *filter.UpdatedSince)
+	}
+	return applyDateRange(query, filter.DateRange)
+}
+
+// listingOrder sorts a listing newest transaction first, or oldest change first
+// when it is filtered by UpdatedSince; the ID breaks ties either way
+func listingOrder(query *gorm.DB, filter models.TransactionFilter) *gorm.DB {
+	if filter.UpdatedSince != nil {
+		return query.Order("updated_at ASC").Order("id ASC")
+	}
+	return query.Order("transaction_date DESC").Order("id DESC")
+}
+
+// GetDataMeta returns the date of the newest transaction and the total row count.
+// The newest date is read by ordering rather than MAX so every driver scans it as a time.
+func (r *AnalyticsRepository) GetDataMeta(ctx context.Context) (*models.DataMeta, error) {
+	var meta models.DataMeta
+
+	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Count(&meta.TotalTransactions).Error; err != nil {
+		return nil, err
+	}
+	if meta.TotalTransactions == 0 {
+		return &meta, nil
+	}
+
+	var last time.Time
+	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Select("transaction_date").
+		Order("transaction_date DESC").
+		Limit(1).
+		Scan(&last).Error; err != nil {
+		return nil, err
+	}
+	meta.LastTransactionDate = &last
+
+	return &meta, nil
+}
+
+// GetDimensions returns the sorted distinct countries, regions, products and
+// non-empty categories of the live transactions
+func (r *AnalyticsRepository) GetDimensions(ctx context.Context) (*models.Dimensions, error) {
+	dimensions := &models.Dimensions{}
+
+	columns := []struct {
+		name   string
+		values *[]string
+	}{
+		{"country", &dimensions.Countries},
+		{"region", &dimensions.Regions},
+		{"product", &dimensions.Products},
+		{"category", &dimensions.Categories},
+	}
+	for _, column := range columns {
+		*column.values = []string{}
+		if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+			Where(column.name+" <> ''").
+			Distinct(column.name).
+			Order(column.name+" ASC").
+			Pluck(column.name, column.values).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return dimensions, nil
+}
+
+// GetCurrencies returns the sorted distinct currencies transactions are recorded in,
+// leaving out rows stored without one
+func (r *AnalyticsRepository) GetCurrencies(ctx context.Context) ([]string, error) {
+	currencies := []string{}
+	err := r.db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("currency <> ''").
+		Distinct("currency").
+		Order("currency ASC").
+		Pluck("currency", &currencies).Error
+
+	return currencies, err
+}
+
+// InsertTransactions stores transactions in a single database transaction,
+// returning models.ErrDuplicateOrder when an order ID is already taken
+func (r *AnalyticsRepository) InsertTransactions(ctx context.Context, transactions []models.Transaction) error {
+	// Rows go in batches by which of the nullable order and customer IDs they
+	// lack: a mixed batch needs the DEFAULT keyword for the missing IDs, which
+	// SQLite rejects in multi-row inserts
+	type nullableIDs struct{ order, customer bool }
+	var shapes []nullableIDs
+	batches := map[nullableIDs][]models.Transaction{}
+	for _, transaction := range transactions {
+		shape := nullableIDs{order: transaction.OrderID == "", customer: transaction.CustomerID == ""}
+		if _, seen := batches[shape]; !seen {
+			shapes = append(shapes, shape)
+		}
+		batches[shape] = append(batches[shape], transaction)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, shape := range shapes {
+			batch := batches[shape]
+			if err := tx.CreateInBatches(&batch, insertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if isDuplicateKey(err) {
+		return fmt.Errorf("%w: %v", models.ErrDuplicateOrder, err)
+	}
+	return err
+}
+
+// DeleteTransaction soft-deletes the transaction with the given ID, returning
+// models.ErrTransactionNotFound when there is none or it was already deleted
+func (r *AnalyticsRepository) DeleteTransaction(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Transaction{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// monthExpr returns the SQL expression formatting transaction_date as YYYY-MM for the active dialect
+func (r *AnalyticsRepository) monthExpr() string {
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		return "TO_CHAR(transaction_date, 'YYYY-MM')"
+	case "sqlite":
+		return "strftime('%Y-%m', transaction_date)"
+	default:
+		return "DATE_FORMAT(transaction_date, '%Y-%m')"
+	}
+}
+
+// dayExpr returns the SQL expression formatting transaction_date as YYYY-MM-DD for the active dialect
+func (r *AnalyticsRepository) dayExpr() string {
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		return "TO_CHAR(transaction_date, 'YYYY-MM-DD')"
+	case "sqlite":
+		return "strftime('%Y-%m-%d', transaction_date)"
+	default:
+		return "DATE_FORMAT(transaction_date, '%Y-%m-%d')"
+	}
+}
+
+// monthBucketExpr returns a CASE expression labelling transaction_date with its YYYY-MM
+// calendar month in loc, for the months from first to last. The month boundaries are
+// computed here and compared against the stored instants, which behaves the same on
+// every dialect, needs no time zone tables in the database and follows DST changes.
+func monthBucketExpr(first, last time.Time, loc *time.Location) (string, []interface{}) {
+	var expr strings.Builder
+	var args []interface{}
+
+	expr.WriteString("CASE")
+	start := first.In(loc)
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc); !month.After(last); {
+		next := month.AddDate(0, 1, 0)
+		fmt.Fprintf(&expr, " WHEN transaction_date < ? THEN '%s'", month.Format("2006-01"))
+		args = append(args, next.UTC())
+		month = next
+	}
+	expr.WriteString(" END")
+
+	return expr.String(), args
+}
+
+// revenueExpr returns the SQL expression converting a row's revenue into the base
+// currency by dividing it by the rate of the currency it was recorded in. Rows in
+// the base currency, without a currency or in one missing from rates are taken as
+// they are. The rates are written as numeric literals so every dialect types the
+// division as decimal; only the codes are bound.
+func revenueExpr(rates map[string]float64) (string, []interface{}) {
+	codes := make([]string, 0, len(rates))
+	for code, rate := range rates {
+		if rate != 1 {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "revenue", nil
+	}
+	sort.Strings(codes)
+
+	var expr strings.Builder
+	args := make([]interface{}, 0, len(codes))
+	expr.WriteString("revenue / CASE currency")
+	for _, code := range codes {
+		fmt.Fprintf(&expr, " WHEN ? THEN %s", strconv.FormatFloat(rates[code], 'f', -1, 64))
+		args = append(args, code)
+	}
+	expr.WriteString(" ELSE 1 END")
+
+	return expr.String(), args
+}
+
+// whereFolded restricts the query to rows whose dimension column equals value
+// ignoring case. Stored values keep the case they were ingested with; comparing
+// LOWER(column) makes filters match them however the client cases them, at the
+// cost of the column's index on drivers that compare case-sensitively.
+func whereFolded(query *gorm.DB, column, value string) *gorm.DB {
+	return query.Where("LOWER("+column+") = ?", strings.ToLower(value))
+}
+
+// whereFoldedIn is whereFolded for a set of values
+func whereFoldedIn(query *gorm.DB, column string, values []string) *gorm.DB {
+	folded := make([]string, len(values))
+	for i, value := range values {
+		folded[i] = strings.ToLower(value)
+	}
+	return query.Where("LOWER("+column+") IN ?", folded)
+}
+
+// applyDateRange restricts the query to transactions inside the range.
+// Both bounds are inclusive; a missing bound leaves that side open.
+func applyDateRange(query *gorm.DB, dateRange models.DateRange) *gorm.DB {
+	switch {
+	case dateRange.From != nil && dateRange.To != nil:
+		return query.Where("transaction_date BETWEEN ? AND ?", *dateRange.From, *dateRange.To)
+	case dateRange.From != nil:
+		return query.Where("transaction_date >= ?", *dateRange.From)
+	case dateRange.To != nil:
+		return query.Where("transaction_date <= ?", *dateRange.To)
+	default:
+		return query
+	}
+}
+
+// productOrder maps a product sort onto an ORDER BY clause, failing for a sort
+// key outside productSortColumns. Ties fall back to the product name so pages
+// stay stable.
+func productOrder(sort models.ProductSort) (string, error) {
+	column, err := resolveColumn(productSortColumns, sort.By)
+	if err != nil {
+		return "", err
+	}
+
+	direction := "DESC"
+	if sort.Ascending {
+		direction = "ASC"
+	}
+	return column + " " + direction + ", product ASC", nil
+}
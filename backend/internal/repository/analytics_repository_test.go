@@ -0,0 +1,458 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetCountryRevenueDateRangeIsInclusive(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2023-12-31T23:59:59Z", "US", "CA", "Laptop", "1"),
+		sale("2024-01-01T00:00:00Z", "US", "CA", "Laptop", "10"),
+		sale("2024-01-15T12:00:00Z", "US", "CA", "Laptop", "100"),
+		sale("2024-01-31T23:59:59Z", "US", "CA", "Laptop", "1000"),
+		sale("2024-02-01T00:00:00Z", "US", "CA", "Laptop", "10000"),
+	)
+
+	tests := []struct {
+		name      string
+		dateRange models.DateRange
+		want      string
+	}{
+		{"unbounded", models.DateRange{}, "11111"},
+		{"both bounds", models.DateRange{From: day("2024-01-01"), To: endOfDay("2024-01-31")}, "1110"},
+		{"from only", models.DateRange{From: day("2024-01-01")}, "11110"},
+		{"to only", models.DateRange{To: endOfDay("2024-01-31")}, "1111"},
+		{"single day", models.DateRange{From: day("2024-01-15"), To: endOfDay("2024-01-15")}, "100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.GetCountryRevenue(ctx, models.CountryFilter{DateRange: tt.dateRange})
+			if err != nil {
+				t.Fatalf("GetCountryRevenue: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("got %d countries, want 1", len(rows))
+			}
+			assertMoney(t, "revenue", rows[0].Revenue, tt.want)
+		})
+	}
+}
+
+func TestGetCountryRevenueEmptyRange(t *testing.T) {
+	repo := newTestRepository(t, sale("2024-01-15T12:00:00Z", "US", "CA", "Laptop", "100"))
+
+	rows, err := repo.GetCountryRevenue(ctx, models.CountryFilter{DateRange: models.DateRange{From: day("2025-01-01")}})
+	if err != nil {
+		t.Fatalf("GetCountryRevenue: %v", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("got %d countries outside the range, want none", len(rows))
+	}
+}
+
+func TestGetCountryRevenueCountryFilter(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "A", "500"),
+		sale("2024-01-02T10:00:00Z", "US", "TX", "A", "250"),
+		sale("2024-01-03T10:00:00Z", "UK", "London", "A", "300"),
+		sale("2024-01-04T10:00:00Z", "DE", "BE", "A", "100"),
+	)
+
+	tests := []struct {
+		name      string
+		countries []string
+		want      []string
+		revenue   []string
+	}{
+		{name: "unfiltered", want: []string{"US", "UK", "DE"}, revenue: []string{"750", "300", "100"}},
+		{name: "single country", countries: []string{"uk"}, want: []string{"UK"}, revenue: []string{"300"}},
+		{name: "several countries", countries: []string{"us", "de"}, want: []string{"US", "DE"}, revenue: []string{"750", "100"}},
+		{name: "country without sales", countries: []string{"fr"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.GetCountryRevenue(ctx, models.CountryFilter{Countries: tt.countries})
+			if err != nil {
+				t.Fatalf("GetCountryRevenue: %v", err)
+			}
+			got := make([]string, len(rows))
+			for i, row := range rows {
+				got[i] = row.Country
+			}
+			if !equalStrings(got, tt.want) {
+				t.Fatalf("countries = %v, want %v", got, tt.want)
+			}
+			for i, row := range rows {
+				assertMoney(t, row.Country+" revenue", row.Revenue, tt.revenue[i])
+			}
+		})
+	}
+}
+
+func TestGetTopProductsTotalIgnoresPageWindow(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "A", "500"),
+		sale("2024-01-02T10:00:00Z", "US", "CA", "B", "400"),
+		sale("2024-01-03T10:00:00Z", "US", "CA", "C", "300"),
+		sale("2024-01-04T10:00:00Z", "US", "CA", "C", "50"),
+		sale("2024-01-05T10:00:00Z", "DE", "BE", "D", "200"),
+		sale("2024-01-06T10:00:00Z", "DE", "BE", "E", "100"),
+	)
+	byRevenue := models.ProductSort{By: models.ProductSortRevenue}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          []string
+	}{
+		{"first page", 2, 0, []string{"A", "B"}},
+		{"middle page", 2, 2, []string{"C", "D"}},
+		{"last partial page", 2, 4, []string{"E"}},
+		{"past the end", 2, 10, nil},
+		{"everything", 10, 0, []string{"A", "B", "C", "D", "E"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, total, err := repo.GetTopProducts(ctx, models.ProductFilter{}, byRevenue, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetTopProducts: %v", err)
+			}
+			if total != 5 {
+				t.Errorf("total = %d, want 5 distinct products whatever the page", total)
+			}
+			if got := productNames(rows); !equalStrings(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTopProductsSortKeysAndDirections(t *testing.T) {
+	// B earns the most from few units, A the least from many
+	units := func(tx models.Transaction, quantity int) models.Transaction {
+		tx.Quantity = quantity
+		return tx
+	}
+	repo := newTestRepository(t,
+		units(sale("2024-01-01T10:00:00Z", "US", "CA", "A", "10"), 50),
+		units(sale("2024-01-02T10:00:00Z", "US", "CA", "B", "900"), 1),
+		units(sale("2024-01-03T10:00:00Z", "US", "CA", "C", "300"), 5),
+		units(sale("2024-01-04T10:00:00Z", "US", "CA", "C", "200"), 5),
+	)
+
+	tests := []struct {
+		sort models.ProductSort
+		want []string
+	}{
+		{sort: models.ProductSort{By: models.ProductSortRevenue}, want: []string{"B", "C", "A"}},
+		{sort: models.ProductSort{By: models.ProductSortRevenue, Ascending: true}, want: []string{"A", "C", "B"}},
+		{sort: models.ProductSort{By: models.ProductSortUnits}, want: []string{"A", "C", "B"}},
+		{sort: models.ProductSort{By: models.ProductSortUnits, Ascending: true}, want: []string{"B", "C", "A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s ascending=%v", tt.sort.By, tt.sort.Ascending), func(t *testing.T) {
+			rows, _, err := repo.GetTopProducts(ctx, models.ProductFilter{}, tt.sort, 10, 0)
+			if err != nil {
+				t.Fatalf("GetTopProducts: %v", err)
+			}
+			if got := productNames(rows); !equalStrings(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := repo.GetTopProducts(ctx, models.ProductFilter{}, models.ProductSort{By: "price"}, 10, 0); !errors.Is(err, models.ErrUnknownSortField) {
+		t.Errorf("sorting by price: err = %v, want ErrUnknownSortField", err)
+	}
+}
+
+func TestGetTopProductsByCountry(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "A", "500"),
+		sale("2024-02-01T10:00:00Z", "US", "CA", "B", "100"),
+		sale("2024-01-05T10:00:00Z", "DE", "BE", "A", "50"),
+		sale("2024-02-05T10:00:00Z", "DE", "BE", "C", "300"),
+	)
+	byRevenue := models.ProductSort{By: models.ProductSortRevenue}
+
+	tests := []struct {
+		name    string
+		filter  models.ProductFilter
+		limit   int
+		want    []string
+		revenue []string
+	}{
+		{name: "global", limit: 10, want: []string{"A", "C", "B"}, revenue: []string{"550", "300", "100"}},
+		{name: "one country", filter: models.ProductFilter{Country: "DE"}, limit: 10, want: []string{"C", "A"}, revenue: []string{"300", "50"}},
+		{name: "country and limit", filter: models.ProductFilter{Country: "US"}, limit: 1, want: []string{"A"}, revenue: []string{"500"}},
+		{
+			name:    "country and date range",
+			filter:  models.ProductFilter{Country: "US", DateRange: models.DateRange{From: day("2024-02-01"), To: endOfDay("2024-02-28")}},
+			limit:   10,
+			want:    []string{"B"},
+			revenue: []string{"100"},
+		},
+		{name: "country without sales", filter: models.ProductFilter{Country: "FR"}, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, _, err := repo.GetTopProducts(ctx, tt.filter, byRevenue, tt.limit, 0)
+			if err != nil {
+				t.Fatalf("GetTopProducts: %v", err)
+			}
+			if got := productNames(rows); !equalStrings(got, tt.want) {
+				t.Fatalf("products = %v, want %v", got, tt.want)
+			}
+			for i, row := range rows {
+				assertMoney(t, row.Product+" revenue", row.Revenue, tt.revenue[i])
+			}
+		})
+	}
+}
+
+func productNames(rows []models.ProductRevenue) []string {
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.Product)
+	}
+	return names
+}
+
+func TestGetTopRegionsReturnsNOrderedRows(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "A", "300"),
+		sale("2024-01-01T10:00:00Z", "US", "NY", "A", "500"),
+		sale("2024-01-01T10:00:00Z", "US", "TX", "A", "200"),
+		sale("2024-01-01T10:00:00Z", "US", "WA", "A", "200"),
+		sale("2024-01-01T10:00:00Z", "US", "FL", "A", "100"),
+		sale("2024-01-02T10:00:00Z", "US", "FL", "A", "100"),
+		sale("2024-01-01T10:00:00Z", "US", "OR", "A", "50"),
+	)
+
+	rows, err := repo.GetTopRegions(ctx, models.RegionFilter{}, 3)
+	if err != nil {
+		t.Fatalf("GetTopRegions: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d regions, want exactly 3", len(rows))
+	}
+	// FL, TX and WA tie at 200; the tie is broken by region name
+	want := []string{"NY", "CA", "FL"}
+	for i, row := range rows {
+		if row.Region != want[i] {
+			t.Errorf("row %d = %s, want %s", i, row.Region, want[i])
+		}
+	}
+
+	for run := 0; run < 5; run++ {
+		rows, err := repo.GetTopRegions(ctx, models.RegionFilter{}, 5)
+		if err != nil {
+			t.Fatalf("GetTopRegions: %v", err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Region
+		}
+		if want := []string{"NY", "CA", "FL", "TX", "WA"}; !equalStrings(got, want) {
+			t.Fatalf("run %d ordered %v, want %v", run, got, want)
+		}
+	}
+}
+
+func TestGetTopRegionsFilter(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "A", "300"),
+		sale("2024-01-31T23:59:59Z", "US", "NY", "A", "100"),
+		sale("2024-02-01T00:00:00Z", "US", "NY", "A", "500"),
+		sale("2024-01-15T10:00:00Z", "DE", "Bavaria", "A", "900"),
+	)
+
+	tests := []struct {
+		name    string
+		filter  models.RegionFilter
+		regions []string
+		revenue []string
+	}{
+		{name: "everything", regions: []string{"Bavaria", "NY", "CA"}, revenue: []string{"900", "600", "300"}},
+		{name: "one country", filter: models.RegionFilter{Country: "us"}, regions: []string{"NY", "CA"}, revenue: []string{"600", "300"}},
+		{
+			name:    "date range is inclusive",
+			filter:  models.RegionFilter{DateRange: models.DateRange{From: day("2024-01-01"), To: endOfDay("2024-01-31")}},
+			regions: []string{"Bavaria", "CA", "NY"},
+			revenue: []string{"900", "300", "100"},
+		},
+		{
+			name:    "country and date range",
+			filter:  models.RegionFilter{Country: "US", DateRange: models.DateRange{From: day("2024-02-01")}},
+			regions: []string{"NY"},
+			revenue: []string{"500"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.GetTopRegions(ctx, tt.filter, 10)
+			if err != nil {
+				t.Fatalf("GetTopRegions: %v", err)
+			}
+			got := make([]string, len(rows))
+			for i, row := range rows {
+				got[i] = row.Region
+			}
+			if !equalStrings(got, tt.regions) {
+				t.Fatalf("regions = %v, want %v", got, tt.regions)
+			}
+			for i, row := range rows {
+				assertMoney(t, row.Region, row.Revenue, tt.revenue[i])
+			}
+		})
+	}
+}
+
+func TestGetDataMeta(t *testing.T) {
+	empty, err := newTestRepository(t).GetDataMeta(ctx)
+	if err != nil {
+		t.Fatalf("GetDataMeta on an empty table: %v", err)
+	}
+	if empty.TotalTransactions != 0 || empty.LastTransactionDate != nil {
+		t.Errorf("empty table meta = %d up to %v, want 0 and no date", empty.TotalTransactions, empty.LastTransactionDate)
+	}
+
+	repo := newTestRepository(t,
+		sale("2024-03-10T08:00:00Z", "US", "CA", "A", "10"),
+		sale("2024-05-31T23:15:00Z", "DE", "BY", "B", "20"),
+		sale("2024-01-01T00:00:00Z", "FR", "IDF", "C", "30"),
+	)
+	meta, err := repo.GetDataMeta(ctx)
+	if err != nil {
+		t.Fatalf("GetDataMeta: %v", err)
+	}
+	if meta.TotalTransactions != 3 {
+		t.Errorf("total = %d, want 3", meta.TotalTransactions)
+	}
+	want := time.Date(2024, time.May, 31, 23, 15, 0, 0, time.UTC)
+	if meta.LastTransactionDate == nil || !meta.LastTransactionDate.Equal(want) {
+		t.Errorf("last transaction = %v, want %s", meta.LastTransactionDate, want)
+	}
+}
+
+func TestGetTopCustomersRanksAndPages(t *testing.T) {
+	bought := func(customer, date, revenue string) models.Transaction {
+		tx := sale(date, "US", "CA", "A", revenue)
+		tx.CustomerID = customer
+		return tx
+	}
+	// Inserted as one batch, which mixes rows with and without a customer ID
+	repo := newTestRepository(t)
+	if err := repo.InsertTransactions(ctx, []models.Transaction{
+		bought("c-ann", "2024-01-01T10:00:00Z", "100"),
+		bought("c-ann", "2024-02-01T10:00:00Z", "150"),
+		bought("c-bob", "2024-01-10T10:00:00Z", "400"),
+		bought("c-cat", "2024-02-10T10:00:00Z", "250"),
+		bought("c-dan", "2024-01-20T10:00:00Z", "50"),
+		// Anonymous sales are not ranked
+		bought("", "2024-01-15T10:00:00Z", "9999"),
+	}); err != nil {
+		t.Fatalf("InsertTransactions: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		dateRange     models.DateRange
+		limit, offset int
+		want          []string
+		revenue       []string
+		total         int64
+	}{
+		// c-ann and c-cat tie on 250 and are ordered by ID
+		{name: "all customers", limit: 10, want: []string{"c-bob", "c-ann", "c-cat", "c-dan"}, revenue: []string{"400", "250", "250", "50"}, total: 4},
+		{name: "first page", limit: 2, want: []string{"c-bob", "c-ann"}, revenue: []string{"400", "250"}, total: 4},
+		{name: "second page", limit: 2, offset: 2, want: []string{"c-cat", "c-dan"}, revenue: []string{"250", "50"}, total: 4},
+		{name: "past the end", limit: 2, offset: 4, total: 4},
+		{
+			name:      "date range",
+			dateRange: models.DateRange{From: day("2024-02-01"), To: endOfDay("2024-02-29")},
+			limit:     10,
+			want:      []string{"c-cat", "c-ann"},
+			revenue:   []string{"250", "150"},
+			total:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, total, err := repo.GetTopCustomers(ctx, tt.dateRange, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetTopCustomers: %v", err)
+			}
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+			var got []string
+			for _, row := range rows {
+				got = append(got, row.CustomerID)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Fatalf("customers = %v, want %v", got, tt.want)
+			}
+			for i, row := range rows {
+				assertMoney(t, row.CustomerID+" revenue", row.Revenue, tt.revenue[i])
+			}
+		})
+	}
+}
+
+func TestGetOrderValuePercentilesNearestRank(t *testing.T) {
+	// orders returns n orders worth 1 to n, inserted out of order
+	orders := func(n int) []models.Transaction {
+		rows := make([]models.Transaction, n)
+		for i := range rows {
+			value := (i*37)%n + 1
+			rows[i] = sale("2024-01-01T10:00:00Z", "US", "CA", "A", fmt.Sprint(value))
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name   string
+		orders int
+		want   []string
+	}{
+		{name: "a hundred orders", orders: 100, want: []string{"50", "90", "95", "99"}},
+		{name: "ten orders round up to the next rank", orders: 10, want: []string{"5", "9", "10", "10"}},
+		{name: "a single order", orders: 1, want: []string{"1", "1", "1", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, orders(tt.orders)...)
+			p, err := repo.GetOrderValuePercentiles(ctx, models.DateRange{})
+			if err != nil {
+				t.Fatalf("GetOrderValuePercentiles: %v", err)
+			}
+			if p.Orders != int64(tt.orders) {
+				t.Errorf("orders = %d, want %d", p.Orders, tt.orders)
+			}
+			names := []string{"p50", "p90", "p95", "p99"}
+			for i, got := range []*models.Money{p.P50, p.P90, p.P95, p.P99} {
+				if got == nil {
+					t.Fatalf("%s is null", names[i])
+				}
+				assertMoney(t, names[i], *got, tt.want[i])
+			}
+		})
+	}
+
+	t.Run("no orders", func(t *testing.T) {
+		p, err := newTestRepository(t).GetOrderValuePercentiles(ctx, models.DateRange{})
+		if err != nil {
+			t.Fatalf("GetOrderValuePercentiles: %v", err)
+		}
+		if p.Orders != 0 || p.P50 != nil || p.P90 != nil || p.P95 != nil || p.P99 != nil {
+			t.Errorf("percentiles = %+v, want zero orders and null percentiles", p)
+		}
+	})
+}
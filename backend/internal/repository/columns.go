@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"fmt"
+
+	"abt-analytics/internal/models"
+)
+
+// productSortColumns maps the fields top products can be ranked by, as the API
+// names them, to the result columns they are ordered on
+var productSortColumns = map[string]string{
+	models.ProductSortRevenue: "revenue",
+	models.ProductSortUnits:   "units",
+}
+
+// resolveColumn returns the column identifier field maps to in columns. Sort and
+// grouping keys only reach the SQL through this lookup, never as the string the
+// client sent, so a field outside the allowlist fails with an error wrapping
+// models.ErrUnknownSortField.
+func resolveColumn(columns map[string]string, field string) (string, error) {
+	column, ok := columns[field]
+	if !ok {
+		return "", fmt.Errorf("%w %q", models.ErrUnknownSortField, field)
+	}
+	return column, nil
+}
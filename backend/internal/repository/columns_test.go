@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+// injectionPayloads are sort keys that would change the statement if they were
+// spliced into ORDER BY
+var injectionPayloads = []string{
+	"revenue; DROP TABLE transactions",
+	"revenue DESC, (SELECT 1)",
+	"units--",
+	"revenue' OR '1'='1",
+	"(CASE WHEN 1=1 THEN revenue ELSE units END)",
+	"Revenue",
+	" revenue",
+	"",
+}
+
+func TestResolveColumnRejectsFieldsOutsideTheAllowlist(t *testing.T) {
+	for field, want := range productSortColumns {
+		if column, err := resolveColumn(productSortColumns, field); err != nil || column != want {
+			t.Errorf("resolveColumn(%q) = %q, %v; want %q", field, column, err, want)
+		}
+	}
+	for _, payload := range injectionPayloads {
+		if column, err := resolveColumn(productSortColumns, payload); !errors.Is(err, models.ErrUnknownSortField) || column != "" {
+			t.Errorf("resolveColumn(%q) = %q, %v; want ErrUnknownSortField", payload, column, err)
+		}
+	}
+}
+
+func TestGetTopProductsRejectsInjectedSortKeys(t *testing.T) {
+	repo := newTestRepository(t,
+		sale("2024-01-01T10:00:00Z", "US", "CA", "Widget", "10"),
+		sale("2024-01-02T10:00:00Z", "US", "NY", "Gadget", "20"),
+	)
+
+	for _, payload := range injectionPayloads {
+		t.Run(payload, func(t *testing.T) {
+			_, _, err := repo.GetTopProducts(ctx, models.ProductFilter{}, models.ProductSort{By: payload}, 10, 0)
+			if !errors.Is(err, models.ErrUnknownSortField) {
+				t.Errorf("err = %v, want ErrUnknownSortField", err)
+			}
+		})
+	}
+
+	var rows int64
+	if err := repo.db.Model(&models.Transaction{}).Count(&rows).Error; err != nil || rows != 2 {
+		t.Errorf("after the payloads: %d rows, %v; want the 2 rows untouched", rows, err)
+	}
+}
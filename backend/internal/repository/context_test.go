@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"abt-analytics/internal/models"
+)
+
+func TestQueriesReturnContextCancellation(t *testing.T) {
+	db := newTestDB(t, sale("2024-01-15T12:00:00Z", "US", "California", "Widget", "10"))
+	repo := NewAnalyticsRepository(db, nil)
+
+	// cancel is called once GORM has built a query and is about to run it
+	var cancel context.CancelFunc
+	cancelQuery := func(*gorm.DB) {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("test:cancel", cancelQuery); err != nil {
+		t.Fatalf("register query callback: %v", err)
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("test:cancel", cancelQuery); err != nil {
+		t.Fatalf("register row callback: %v", err)
+	}
+
+	queries := map[string]func(ctx context.Context) error{
+		"GetCountryRevenue": func(ctx context.Context) error {
+			_, err := repo.GetCountryRevenue(ctx, models.CountryFilter{})
+			return err
+		},
+		"GetTopProducts": func(ctx context.Context) error {
+			_, _, err := repo.GetTopProducts(ctx, models.ProductFilter{}, models.ProductSort{By: models.ProductSortRevenue}, 10, 0)
+			return err
+		},
+		"GetMonthlySales": func(ctx context.Context) error {
+			_, err := repo.GetMonthlySales(ctx, models.SalesFilter{})
+			return err
+		},
+		"ListTransactions": func(ctx context.Context) error {
+			_, _, err := repo.ListTransactions(ctx, models.TransactionFilter{}, nil, 10, 0)
+			return err
+		},
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancelQuery := context.WithCancel(context.Background())
+			defer cancelQuery()
+			cancel = cancelQuery
+			defer func() { cancel = nil }()
+
+			if err := query(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want context.Canceled", err)
+			}
+		})
+	}
+
+	if _, err := repo.GetCountryRevenue(context.Background(), models.CountryFilter{}); err != nil {
+		t.Errorf("uncanceled query failed: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+)
+
+// newTestDB opens a migrated in-memory SQLite database holding rows. It keeps a
+// single connection, since every connection to :memory: opens a database of its own.
+func newTestDB(t *testing.T, rows ...models.Transaction) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("access pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if len(rows) > 0 {
+		if err := db.Create(&rows).Error; err != nil {
+			t.Fatalf("insert rows: %v", err)
+		}
+	}
+	return db
+}
+
+// newTestRepository returns a repository over newTestDB(rows) without exchange rates
+func newTestRepository(t *testing.T, rows ...models.Transaction) *AnalyticsRepository {
+	t.Helper()
+	return NewAnalyticsRepository(newTestDB(t, rows...), nil)
+}
+
+// sale returns a single-unit transaction of product at the RFC3339 instant date
+func sale(date, country, region, product, revenue string) models.Transaction {
+	at, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		panic(err)
+	}
+	return models.Transaction{
+		TransactionDate: at,
+		Country:         country,
+		Region:          region,
+		Product:         product,
+		Quantity:        1,
+		Revenue:         money(revenue),
+	}
+}
+
+func money(amount string) models.Money {
+	return models.NewMoney(decimal.RequireFromString(amount))
+}
+
+func day(date string) *time.Time {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func endOfDay(date string) *time.Time {
+	t := day(date).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return &t
+}
+
+func assertMoney(t *testing.T, name string, got models.Money, want string) {
+	t.Helper()
+	if !got.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s = %s, want %s", name, got.Format(), want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var ctx = context.Background()
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"sort"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+// order returns tx with its OrderID set, so listings can be compared by ID
+func order(id string, tx models.Transaction) models.Transaction {
+	tx.OrderID = id
+	return tx
+}
+
+func orderIDs(rows []models.Transaction) []string {
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.OrderID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestListTransactionsFilters(t *testing.T) {
+	repo := newTestRepository(t,
+		order("a", sale("2024-01-05T10:00:00Z", "US", "California", "Laptop", "100")),
+		order("b", sale("2024-01-20T10:00:00Z", "US", "Texas", "Phone", "200")),
+		order("c", sale("2024-02-10T10:00:00Z", "DE", "Bavaria", "Laptop", "300")),
+		order("d", sale("2024-03-01T10:00:00Z", "DE", "Berlin", "Phone", "400")),
+		order("e", sale("2024-03-31T23:00:00Z", "US", "California", "Phone", "500")),
+	)
+
+	tests := []struct {
+		name   string
+		filter models.TransactionFilter
+		want   []string
+	}{
+		{name: "no filter", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "country", filter: models.TransactionFilter{Country: "US"}, want: []string{"a", "b", "e"}},
+		{name: "country ignores case", filter: models.TransactionFilter{Country: "de"}, want: []string{"c", "d"}},
+		{name: "product", filter: models.TransactionFilter{Product: "Laptop"}, want: []string{"a", "c"}},
+		{name: "region", filter: models.TransactionFilter{Region: "California"}, want: []string{"a", "e"}},
+		{
+			name:   "date range",
+			filter: models.TransactionFilter{DateRange: models.DateRange{From: day("2024-01-20"), To: endOfDay("2024-03-01")}},
+			want:   []string{"b", "c", "d"},
+		},
+		{name: "country and product", filter: models.TransactionFilter{Country: "US", Product: "Phone"}, want: []string{"b", "e"}},
+		{name: "country and region", filter: models.TransactionFilter{Country: "US", Region: "California"}, want: []string{"a", "e"}},
+		{
+			name: "all filters",
+			filter: models.TransactionFilter{
+				Country: "US", Product: "Phone", Region: "California",
+				DateRange: models.DateRange{From: day("2024-03-01"), To: endOfDay("2024-03-31")},
+			},
+			want: []string{"e"},
+		},
+		{name: "region outside the country", filter: models.TransactionFilter{Country: "DE", Region: "Texas"}, want: []string{}},
+		{name: "unknown product", filter: models.TransactionFilter{Product: "Tablet"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, total, err := repo.ListTransactions(ctx, tt.filter, nil, 100, 0)
+			if err != nil {
+				t.Fatalf("ListTransactions: %v", err)
+			}
+			if got := orderIDs(rows); !equalStrings(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestListTransactionsPaginates(t *testing.T) {
+	repo := newTestRepository(t,
+		order("a", sale("2024-01-01T10:00:00Z", "US", "Texas", "Phone", "1")),
+		order("b", sale("2024-01-02T10:00:00Z", "US", "Texas", "Phone", "1")),
+		order("c", sale("2024-01-03T10:00:00Z", "US", "Texas", "Phone", "1")),
+		order("d", sale("2024-01-04T10:00:00Z", "DE", "Berlin", "Phone", "1")),
+	)
+	filter := models.TransactionFilter{Country: "US"}
+
+	var seen []string
+	for offset := 0; offset < 3; offset += 2 {
+		rows, total, err := repo.ListTransactions(ctx, filter, nil, 2, offset)
+		if err != nil {
+			t.Fatalf("ListTransactions: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("offset %d: total = %d, want the 3 matches of every page", offset, total)
+		}
+		for _, row := range rows {
+			seen = append(seen, row.OrderID)
+		}
+	}
+	if want := []string{"c", "b", "a"}; !equalStrings(seen, want) {
+		t.Errorf("pages = %v, want %v, newest first", seen, want)
+	}
+}
+
+func TestListTransactionsUpdatedSince(t *testing.T) {
+	changed := func(id, updatedAt string) models.Transaction {
+		tx := order(id, sale("2024-01-01T10:00:00Z", "US", "Texas", "Phone", "1"))
+		tx.UpdatedAt = *day(updatedAt)
+		return tx
+	}
+	db := newTestDB(t,
+		changed("edited", "2024-03-01"),
+		changed("cutoff", "2024-03-10"),
+		changed("later", "2024-03-20"),
+		changed("sooner", "2024-03-15"),
+	)
+	// Editing a row moves its update time to now
+	if err := db.Model(&models.Transaction{}).Where("order_id = ?", "edited").Update("quantity", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := NewAnalyticsRepository(db, nil)
+	filter := models.TransactionFilter{UpdatedSince: day("2024-03-10")}
+
+	rows, total, err := repo.ListTransactions(ctx, filter, nil, 10, 0)
+	if err != nil {
+		t.Fatalf("ListTransactions: %v", err)
+	}
+	var got []string
+	for _, row := range rows {
+		got = append(got, row.OrderID)
+	}
+	// The row updated exactly at the cutoff is not after it
+	if want := []string{"sooner", "later", "edited"}; !equalStrings(got, want) || total != 3 {
+		t.Fatalf("changes = %v of %d, want %v, oldest change first", got, total, want)
+	}
+
+	// A cursor resumes after the last change seen
+	last := rows[0]
+	after := &models.TransactionCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	rest, _, err := repo.ListTransactions(ctx, filter, after, 10, 0)
+	if err != nil {
+		t.Fatalf("ListTransactions after %s: %v", last.OrderID, err)
+	}
+	got = got[:0]
+	for _, row := range rest {
+		got = append(got, row.OrderID)
+	}
+	if want := []string{"later", "edited"}; !equalStrings(got, want) {
+		t.Errorf("changes after the cursor = %v, want %v", got, want)
+	}
+}
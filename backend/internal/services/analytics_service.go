@@ -0,0 +1,533 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+// Supported sales granularities
+const (
+	GranularityMonth   = "month"
+	GranularityQuarter = "quarter"
+)
+
+// CacheScopes names the groups of cached results FlushCache can clear on their
+// own. Each is the route the results are served by and prefixes their cache keys;
+// top-regions also covers the per-country region breakdown and dimensions the
+// currencies transactions are recorded in.
+var CacheScopes = []string{
+	"country-revenue", "category-revenue", "avg-order-value", "order-value-percentiles", "top-products", "top-customers",
+	"monthly-sales", "growth", "daily-revenue", "top-regions", "region-trends", "compare", "meta", "dimensions",
+	"revenue-concentration", "forecast",
+}
+
+// AnalyticsRepository is the data access the service relies on
+type AnalyticsRepository interface {
+	Ping(ctx context.Context) error
+	GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error)
+	GetCountryRevenue(ctx context.Context, filter models.CountryFilter) ([]models.CountryRevenue, error)
+	GetCategoryRevenue(ctx context.Context, dateRange models.DateRange) ([]models.CategoryRevenue, error)
+	GetAverageOrderValue(ctx context.Context, dateRange models.DateRange) ([]models.CountryOrderValue, error)
+	GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange) (*models.OrderValuePercentiles, error)
+	GetPeriodTotals(ctx context.Context, dateRange models.DateRange) (*models.PeriodTotals, error)
+	GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int) ([]models.ProductRevenue, int64, error)
+	GetRankedProducts(ctx context.Context, dateRange models.DateRange) ([]models.ProductRevenue, error)
+	GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int) ([]models.CustomerRevenue, int64, error)
+	GetMonthlySales(ctx context.Context, filter models.SalesFilter) ([]models.MonthlySales, error)
+	GetDailyRevenue(ctx context.Context, dateRange models.DateRange) ([]models.DailyRevenue, error)
+	GetTopRegions(ctx context.Context, filter models.RegionFilter, n int) ([]models.RegionRevenue, error)
+	GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int) ([]models.RegionMonthRevenue, error)
+	ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) ([]models.Transaction, int64, error)
+	GetTransaction(ctx context.Context, id uint) (*models.Transaction, error)
+	StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error
+	InsertTransactions(ctx context.Context, transactions []models.Transaction) error
+	DeleteTransaction(ctx context.Context, id uint) error
+	GetDataMeta(ctx context.Context) (*models.DataMeta, error)
+	GetDimensions(ctx context.Context) (*models.Dimensions, error)
+	GetCurrencies(ctx context.Context) ([]string, error)
+}
+
+// AnalyticsService exposes the analytics aggregations to the controllers.
+// Results are cached as JSON per endpoint and query parameters for the configured TTL;
+// the boolean returned by each getter reports whether the cache served it.
+// Revenue getters take the models.Conversion to report revenue with.
+type AnalyticsService struct {
+	repo     AnalyticsRepository
+	cache    cache.Cache
+	cacheTTL time.Duration
+	rates    map[string]float64
+	// baseCurrency is the currency the repository sums revenue in
+	baseCurrency string
+	// exportTimeout bounds how long an export may stream from the database
+	exportTimeout time.Duration
+	// defaultRangeDays is the trailing window requests without date bounds cover
+	defaultRangeDays int
+}
+
+// NewAnalyticsService creates a new analytics service caching results in c for cfg.CacheTTL
+// and converting revenue with cfg.Rates. A nil cache or non-positive TTL disables caching.
+// With cfg.DebugTiming every repository call is timed for the Server-Timing header.
+func NewAnalyticsService(repo AnalyticsRepository, c cache.Cache, cfg *config.Config) *AnalyticsService {
+	if cfg.DebugTiming {
+		repo = timedRepository{repo: repo}
+	}
+	return &AnalyticsService{
+		repo:     repo,
+		cache:    c,
+		cacheTTL: cfg.CacheTTL,
+		rates:    cfg.Rates,
+
+		baseCurrency:     cfg.BaseCurrency,
+		exportTimeout:    cfg.ExportTimeout,
+		defaultRangeDays: cfg.DefaultRangeDays,
+	}
+}
+
+// Ping checks that the underlying database is reachable
+func (s *AnalyticsService) Ping(ctx context.Context) error {
+	return s.repo.Ping(ctx)
+}
+
+// GetMigrationStatus reports whether the database schema is current. It is never
+// cached, so it reflects migrations run by other replicas right away.
+func (s *AnalyticsService) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	return s.repo.GetMigrationStatus(ctx)
+}
+
+// GetCountryRevenue returns the revenue per country matching the filter, each with
+// its share of the matching total revenue rounded to two decimals.
+// An empty window yields an empty list.
+func (s *AnalyticsService) GetCountryRevenue(ctx context.Context, filter models.CountryFilter, conversion models.Conversion) ([]models.CountryRevenue, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.CountryRevenue
+	countries := append([]string(nil), filter.Countries...)
+	sort.Strings(countries)
+	key := fmt.Sprintf("country-revenue:%s:%s", strings.Join(countries, ","), dateRangeKey(filter.DateRange))
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		return s.repo.GetCountryRevenue(ctx, filter)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+
+	total := decimal.Zero
+	for _, row := range data {
+		total = total.Add(row.Revenue.Decimal)
+	}
+	for i := range data {
+		if !total.IsZero() {
+			data[i].Percentage = percentOf(data[i].Revenue.Decimal, total)
+		}
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	if data == nil {
+		data = []models.CountryRevenue{}
+	}
+	return data, hit, nil
+}
+
+// GetCategoryRevenue returns the revenue per product category within the given date range
+func (s *AnalyticsService) GetCategoryRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CategoryRevenue, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.CategoryRevenue
+	key := "category-revenue:" + dateRangeKey(dateRange)
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		return s.repo.GetCategoryRevenue(ctx, dateRange)
+	})
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	return data, hit, err
+}
+
+// GetAverageOrderValue returns the average order value per country within the given
+// date range, rounded to two decimals after any currency conversion
+func (s *AnalyticsService) GetAverageOrderValue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.CountryOrderValue, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.CountryOrderValue
+	key := "avg-order-value:" + dateRangeKey(dateRange)
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		return s.repo.GetAverageOrderValue(ctx, dateRange)
+	})
+	for i := range data {
+		data[i].AverageOrderValue = convert(data[i].AverageOrderValue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	return data, hit, err
+}
+
+// GetOrderValuePercentiles returns the p50, p90, p95 and p99 order values within the
+// date range, rounded to two decimals after any currency conversion
+func (s *AnalyticsService) GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) (*models.OrderValuePercentiles, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data *models.OrderValuePercentiles
+	key := "order-value-percentiles:" + dateRangeKey(dateRange)
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		return s.repo.GetOrderValuePercentiles(ctx, dateRange)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for _, value := range []**models.Money{&data.P50, &data.P90, &data.P95, &data.P99} {
+		if *value != nil {
+			converted := convert(**value, rate)
+			*value = &converted
+		}
+	}
+	data.Currency = conversion.Currency
+	return data, hit, nil
+}
+
+// GetTopProducts returns one page of products ranked by revenue or units sold,
+// optionally scoped to one country and a date range
+func (s *AnalyticsService) GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int, conversion models.Conversion) (*models.ProductRevenuePage, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var page *models.ProductRevenuePage
+	key := fmt.Sprintf("top-products:%s:%s:%s:%t:%d:%d",
+		filter.Country, dateRangeKey(filter.DateRange), sort.By, sort.Ascending, limit, offset)
+	hit, err := s.cached(ctx, key, &page, func() (interface{}, error) {
+		products, total, err := s.repo.GetTopProducts(ctx, filter, sort, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		if products == nil {
+			products = []models.ProductRevenue{}
+		}
+
+		return &models.ProductRevenuePage{
+			Data:   products,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}, nil
+	})
+	if page != nil {
+		for i := range page.Data {
+			page.Data[i].Revenue = convert(page.Data[i].Revenue, rate)
+			page.Data[i].Currency = conversion.Currency
+		}
+	}
+	return page, hit, err
+}
+
+// GetTopCustomers returns one page of customers ranked by revenue within the date range
+func (s *AnalyticsService) GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int, conversion models.Conversion) (*models.CustomerRevenuePage, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var page *models.CustomerRevenuePage
+	key := fmt.Sprintf("top-customers:%s:%d:%d", dateRangeKey(dateRange), limit, offset)
+	hit, err := s.cached(ctx, key, &page, func() (interface{}, error) {
+		customers, total, err := s.repo.GetTopCustomers(ctx, dateRange, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		if customers == nil {
+			customers = []models.CustomerRevenue{}
+		}
+
+		return &models.CustomerRevenuePage{
+			Data:   customers,
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}, nil
+	})
+	if page != nil {
+		for i := range page.Data {
+			page.Data[i].Revenue = convert(page.Data[i].Revenue, rate)
+			page.Data[i].Currency = conversion.Currency
+		}
+	}
+	return page, hit, err
+}
+
+// GetMonthlySales returns the revenue per month, or per quarter when requested, of the
+// sales matching the filter. With compareYoY each period also carries the revenue of
+// the same period one year earlier and the percent change between the two.
+func (s *AnalyticsService) GetMonthlySales(ctx context.Context, filter models.SalesFilter, granularity string, compareYoY bool, conversion models.Conversion) ([]models.MonthlySales, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.MonthlySales
+	key := fmt.Sprintf("monthly-sales:%s:%t:%s:%s:%s:%s",
+		granularity, compareYoY, filter.Product, filter.Category, dateRangeKey(filter.DateRange), filter.TimeZone())
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		current, err := s.loadSales(ctx, filter, granularity)
+		if err != nil || !compareYoY {
+			return current, err
+		}
+
+		priorFilter := filter
+		priorFilter.DateRange = shiftDateRange(filter.DateRange, -1)
+		prior, err := s.loadSales(ctx, priorFilter, granularity)
+		if err != nil {
+			return nil, err
+		}
+		from, to := salesBounds(current, filter.DateRange, filter.TimeZone(), granularity)
+		return compareYearOverYear(current, prior, from, to)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		if data[i].PriorRevenue != nil {
+			prior := convert(*data[i].PriorRevenue, rate)
+			data[i].PriorRevenue = &prior
+		}
+		data[i].Currency = conversion.Currency
+	}
+	if data == nil {
+		data = []models.MonthlySales{}
+	}
+	return data, hit, nil
+}
+
+// loadSales fetches the monthly totals matching filter, rolled up to quarters when requested
+func (s *AnalyticsService) loadSales(ctx context.Context, filter models.SalesFilter, granularity string) ([]models.MonthlySales, error) {
+	monthly, err := s.repo.GetMonthlySales(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if granularity == GranularityQuarter {
+		return rollUpQuarters(monthly)
+	}
+	return monthly, nil
+}
+
+// GetTopRegions returns the n regions ranked by revenue inside the filter's date
+// range, across all countries when filter.Country is empty and within that country otherwise
+func (s *AnalyticsService) GetTopRegions(ctx context.Context, filter models.RegionFilter, n int, conversion models.Conversion) ([]models.RegionRevenue, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.RegionRevenue
+	key := fmt.Sprintf("top-regions:%s:%s:%d", filter.Country, dateRangeKey(filter.DateRange), n)
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		return s.repo.GetTopRegions(ctx, filter, n)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	if data == nil {
+		data = []models.RegionRevenue{}
+	}
+	return data, hit, nil
+}
+
+// GetDataMeta returns the freshness of the underlying data, cached like the aggregates it
+// describes, and the default window requests without date bounds currently cover
+func (s *AnalyticsService) GetDataMeta(ctx context.Context) (*models.DataMeta, bool, error) {
+	var meta *models.DataMeta
+	hit, err := s.cached(ctx, "meta:", &meta, func() (interface{}, error) {
+		return s.repo.GetDataMeta(ctx)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	meta.DefaultRange = s.defaultRange(time.Now()).Window()
+	return meta, hit, nil
+}
+
+// GetDimensions returns the distinct values of each filterable field. They change
+// rarely, so they are cached like the aggregates.
+func (s *AnalyticsService) GetDimensions(ctx context.Context) (*models.Dimensions, bool, error) {
+	var dimensions *models.Dimensions
+	hit, err := s.cached(ctx, "dimensions:", &dimensions, func() (interface{}, error) {
+		return s.repo.GetDimensions(ctx)
+	})
+	return dimensions, hit, err
+}
+
+// ListTransactions returns one page of raw transactions matching the filter, starting
+// after the cursor when one is given and at offset otherwise. Listings are not cached
+// since they are not aggregates.
+func (s *AnalyticsService) ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) (*models.TransactionPage, error) {
+	// Fetching one row more than requested tells whether another page follows
+	transactions, total, err := s.repo.ListTransactions(ctx, filter, after, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TransactionPage{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		cursor := models.TransactionCursor{TransactionDate: last.TransactionDate, ID: last.ID}
+		if filter.UpdatedSince != nil {
+			cursor = models.TransactionCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+		}
+		page.NextCursor = EncodeCursor(cursor)
+	}
+	page.Data = make([]models.TransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		page.Data[i] = models.NewTransactionResponse(transaction)
+	}
+	return page, nil
+}
+
+// GetTransaction returns the live transaction with the given ID, or
+// models.ErrTransactionNotFound when there is none
+func (s *AnalyticsService) GetTransaction(ctx context.Context, id uint) (*models.TransactionResponse, error) {
+	transaction, err := s.repo.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := models.NewTransactionResponse(*transaction)
+	return &response, nil
+}
+
+// ExportTransactions calls fn with every transaction matching the filter, newest
+// first, streaming them from the repository rather than loading them all. The
+// stream is abandoned with an error once the export timeout passes, so a slow
+// client cannot hold a database connection indefinitely.
+func (s *AnalyticsService) ExportTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error {
+	if s.exportTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.exportTimeout)
+		defer cancel()
+	}
+	return s.repo.StreamTransactions(ctx, filter, fn)
+}
+
+// FlushCache evicts the cached results of one of CacheScopes, or every cached
+// result for an empty scope, and returns how many entries were evicted. Use it
+// after changing data out of band so the aggregates do not wait for their TTL.
+func (s *AnalyticsService) FlushCache(ctx context.Context, scope string) (int, error) {
+	if s.cache == nil {
+		return 0, nil
+	}
+
+	prefix := ""
+	if scope != "" {
+		prefix = scope + ":"
+	}
+	return s.cache.Clear(ctx, prefix)
+}
+
+// cached decodes the value cached under key into dest, or calls load, caches
+// its JSON encoding and decodes that into dest. Cache failures are logged and
+// treated as misses so a cache outage never fails a request. Under a context
+// from refreshing the cached value is skipped so load always runs and replaces it.
+func (s *AnalyticsService) cached(ctx context.Context, key string, dest interface{}, load func() (interface{}, error)) (bool, error) {
+	enabled := s.cache != nil && s.cacheTTL > 0
+
+	if enabled && !isRefresh(ctx) {
+		payload, found, err := s.cache.Get(ctx, key)
+		if err != nil {
+			log.Printf("Warning: cache get %q failed: %v", key, err)
+		} else if found && json.Unmarshal(payload, dest) == nil {
+			return true, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return false, err
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	if enabled {
+		if err := s.cache.Set(ctx, key, payload, s.cacheTTL); err != nil {
+			log.Printf("Warning: cache set %q failed: %v", key, err)
+		}
+	}
+	return false, json.Unmarshal(payload, dest)
+}
+
+// defaultRange returns the window requests giving no date bounds cover at now:
+// the trailing DEFAULT_RANGE_DAYS, or all history when it is not set
+func (s *AnalyticsService) defaultRange(now time.Time) models.DateRange {
+	if s.defaultRangeDays <= 0 {
+		return models.DateRange{}
+	}
+	return models.TrailingDays(s.defaultRangeDays, now)
+}
+
+// dateRangeKey renders a date range as a stable cache key fragment
+func dateRangeKey(dateRange models.DateRange) string {
+	from, to := "", ""
+	if dateRange.From != nil {
+		from = dateRange.From.UTC().Format(time.RFC3339Nano)
+	}
+	if dateRange.To != nil {
+		to = dateRange.To.UTC().Format(time.RFC3339Nano)
+	}
+	return from + ".." + to
+}
+
+// rollUpQuarters sums chronologically ordered monthly rows into YYYY-Qn buckets.
+// Quarters only partially covered by the data sum whatever months are present.
+func rollUpQuarters(monthly []models.MonthlySales) ([]models.MonthlySales, error) {
+	var quarters []models.MonthlySales
+	index := make(map[string]int)
+
+	for _, row := range monthly {
+		var year, month int
+		if _, err := fmt.Sscanf(row.Period, "%d-%d", &year, &month); err != nil {
+			return nil, fmt.Errorf("unexpected month label %q: %w", row.Period, err)
+		}
+
+		label := fmt.Sprintf("%d-Q%d", year, (month-1)/3+1)
+		if i, ok := index[label]; ok {
+			quarters[i].Revenue = models.NewMoney(quarters[i].Revenue.Add(row.Revenue.Decimal))
+			continue
+		}
+		index[label] = len(quarters)
+		quarters = append(quarters, models.MonthlySales{Period: label, Revenue: row.Revenue})
+	}
+
+	return quarters, nil
+}
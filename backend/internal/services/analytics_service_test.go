@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/models"
+)
+
+func TestRollUpQuarters(t *testing.T) {
+	monthly := []models.MonthlySales{
+		// The data starts in the last month of Q4 2023 and ends mid Q2 2024
+		{Period: "2023-12", Revenue: money("10.10")},
+		{Period: "2024-01", Revenue: money("100")},
+		{Period: "2024-02", Revenue: money("200.25")},
+		{Period: "2024-03", Revenue: money("300.75")},
+		{Period: "2024-05", Revenue: money("55.55")},
+	}
+
+	quarters, err := rollUpQuarters(monthly)
+	if err != nil {
+		t.Fatalf("rollUpQuarters: %v", err)
+	}
+
+	want := []struct{ period, revenue string }{
+		{"2023-Q4", "10.10"},
+		{"2024-Q1", "601.00"},
+		{"2024-Q2", "55.55"},
+	}
+	if len(quarters) != len(want) {
+		t.Fatalf("got %d quarters, want %d: %+v", len(quarters), len(want), quarters)
+	}
+	for i, w := range want {
+		if quarters[i].Period != w.period {
+			t.Errorf("quarter %d = %s, want %s", i, quarters[i].Period, w.period)
+		}
+		assertMoney(t, w.period, quarters[i].Revenue, w.revenue)
+	}
+}
+
+func TestRollUpQuartersRejectsBadLabels(t *testing.T) {
+	if _, err := rollUpQuarters([]models.MonthlySales{{Period: "January"}}); err == nil {
+		t.Error("rollUpQuarters accepted a label that is not YYYY-MM")
+	}
+}
+
+func TestGetMonthlySalesByQuarter(t *testing.T) {
+	repo := &stubRepository{monthly: []models.MonthlySales{
+		{Period: "2024-03", Revenue: money("30")},
+		{Period: "2024-04", Revenue: money("40")},
+		{Period: "2024-05", Revenue: money("50")},
+		{Period: "2024-06", Revenue: money("60")},
+		{Period: "2024-07", Revenue: money("70")},
+	}}
+
+	rows, _, err := newTestService(repo).GetMonthlySales(context.Background(), models.SalesFilter{}, GranularityQuarter, false, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetMonthlySales: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d quarters, want 3: %+v", len(rows), rows)
+	}
+	assertMoney(t, "2024-Q1", rows[0].Revenue, "30")
+	assertMoney(t, "2024-Q2", rows[1].Revenue, "150")
+	assertMoney(t, "2024-Q3", rows[2].Revenue, "70")
+}
+
+func TestGetCountryRevenueServedFromCacheWithinTTL(t *testing.T) {
+	repo := &stubRepository{countryRevenue: []models.CountryRevenue{{Country: "US", Revenue: money("10"), Orders: 1}}}
+	service := newTestService(repo)
+	filter := models.CountryFilter{Countries: []string{"US"}}
+
+	if _, hit, err := service.GetCountryRevenue(ctx, filter, models.Conversion{}); err != nil || hit {
+		t.Fatalf("first call: hit = %v, err = %v; want a miss", hit, err)
+	}
+	data, hit, err := service.GetCountryRevenue(ctx, filter, models.Conversion{})
+	if err != nil || !hit {
+		t.Fatalf("second call: hit = %v, err = %v; want a hit", hit, err)
+	}
+	if got := repo.callCount("GetCountryRevenue"); got != 1 {
+		t.Errorf("repository called %d times, want 1", got)
+	}
+	if len(data) != 1 || data[0].Country != "US" {
+		t.Errorf("cached data = %+v", data)
+	}
+
+	if _, hit, _ := service.GetCountryRevenue(ctx, models.CountryFilter{Countries: []string{"DE"}}, models.Conversion{}); hit {
+		t.Error("a different filter was served from the cache")
+	}
+	if got := repo.callCount("GetCountryRevenue"); got != 2 {
+		t.Errorf("repository called %d times, want 2", got)
+	}
+}
+
+func TestGetCountryRevenueCacheExpires(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testConfig()
+	cfg.CacheTTL = 20 * time.Millisecond
+	service := NewAnalyticsService(repo, cache.NewMemoryCache(), cfg)
+
+	service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+	time.Sleep(2 * cfg.CacheTTL)
+	if _, hit, _ := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{}); hit {
+		t.Error("an expired entry was served from the cache")
+	}
+	if got := repo.callCount("GetCountryRevenue"); got != 2 {
+		t.Errorf("repository called %d times, want 2", got)
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+// newSeededService returns a service over the real repository on a database
+// holding the built-in sample set
+func newSeededService(t *testing.T) *AnalyticsService {
+	t.Helper()
+	db := newTestDB(t)
+	if err := NewDataSeeder(db, &config.Config{}).SeedData(ctx); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return newTestService(repository.NewAnalyticsRepository(db, testConfig().Rates))
+}
+
+// sampleRevenue sums the revenue of the sample transactions keep accepts
+func sampleRevenue(keep func(models.Transaction) bool) decimal.Decimal {
+	total := decimal.Zero
+	for _, tx := range generateSampleTransactions() {
+		if keep(tx) {
+			total = total.Add(tx.Revenue.Decimal)
+		}
+	}
+	return total
+}
+
+func TestCategoryRevenueSumsToGrandTotal(t *testing.T) {
+	service := newSeededService(t)
+	from := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.June, 30, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		dateRange models.DateRange
+		keep      func(models.Transaction) bool
+	}{
+		{name: "all time", keep: func(models.Transaction) bool { return true }},
+		{
+			name:      "date range",
+			dateRange: models.DateRange{From: &from, To: &to},
+			keep: func(tx models.Transaction) bool {
+				return !tx.TransactionDate.Before(from) && !tx.TransactionDate.After(to)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := service.GetCategoryRevenue(ctx, tt.dateRange, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetCategoryRevenue: %v", err)
+			}
+
+			categories := make(map[string]bool)
+			for _, p := range sampleProducts {
+				categories[p.Category] = true
+			}
+			if len(data) != len(categories) {
+				t.Errorf("got %d categories, want %d", len(data), len(categories))
+			}
+
+			total := decimal.Zero
+			for i, row := range data {
+				if !categories[row.Category] {
+					t.Errorf("unexpected category %q", row.Category)
+				}
+				if i > 0 && row.Revenue.GreaterThan(data[i-1].Revenue.Decimal) {
+					t.Errorf("%s is ranked below a lower revenue", row.Category)
+				}
+				total = total.Add(row.Revenue.Decimal)
+			}
+			if want := sampleRevenue(tt.keep); !total.Equal(want) {
+				t.Errorf("categories sum to %s, want the grand total %s", total, want)
+			}
+		})
+	}
+}
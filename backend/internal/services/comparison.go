@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// compareYearOverYear pairs every period in current with the same period one year
+// earlier from prior. A period missing on either side counts as zero revenue;
+// prior-only periods are kept when, shifted a year forward, they fall within
+// [from, to]. The result is in chronological order.
+func compareYearOverYear(current, prior []models.MonthlySales, from, to string) ([]models.MonthlySales, error) {
+	revenue := make(map[string]models.Money, len(current))
+	for _, row := range current {
+		revenue[row.Period] = row.Revenue
+	}
+
+	priorRevenue := make(map[string]models.Money, len(prior))
+	for _, row := range prior {
+		period, err := shiftPeriod(row.Period, 1)
+		if err != nil {
+			return nil, err
+		}
+		priorRevenue[period] = row.Revenue
+		if _, ok := revenue[period]; !ok && period >= from && period <= to {
+			revenue[period] = models.Money{}
+		}
+	}
+
+	periods := make([]string, 0, len(revenue))
+	for period := range revenue {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+
+	rows := make([]models.MonthlySales, 0, len(periods))
+	for _, period := range periods {
+		previous := priorRevenue[period]
+		row := models.MonthlySales{
+			Period:       period,
+			Revenue:      revenue[period],
+			PriorRevenue: &previous,
+		}
+		if !previous.IsZero() {
+			change := percentOf(row.Revenue.Sub(previous.Decimal), previous.Decimal)
+			row.PercentChange = &change
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// salesBounds returns the first and last period label a comparison may cover:
+// the date range bounds where set, read in loc, otherwise the extent of the current rows
+func salesBounds(current []models.MonthlySales, dateRange models.DateRange, loc *time.Location, granularity string) (string, string) {
+	var from, to string
+	if len(current) > 0 {
+		from, to = current[0].Period, current[len(current)-1].Period
+	}
+	if dateRange.From != nil {
+		from = periodLabel(dateRange.From.In(loc), granularity)
+	}
+	if dateRange.To != nil {
+		to = periodLabel(dateRange.To.In(loc), granularity)
+	}
+	return from, to
+}
+
+// periodLabel formats t as the YYYY-MM or YYYY-Qn label of its period
+func periodLabel(t time.Time, granularity string) string {
+	if granularity == GranularityQuarter {
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	}
+	return t.Format("2006-01")
+}
+
+// shiftPeriod moves a YYYY-MM or YYYY-Qn label by the given number of years
+func shiftPeriod(period string, years int) (string, error) {
+	if len(period) < 5 || period[4] != '-' {
+		return "", fmt.Errorf("unexpected period label %q", period)
+	}
+	year, err := strconv.Atoi(period[:4])
+	if err != nil {
+		return "", fmt.Errorf("unexpected period label %q: %w", period, err)
+	}
+	return fmt.Sprintf("%04d%s", year+years, period[4:]), nil
+}
+
+// shiftDateRange moves both bounds of dateRange by the given number of years
+func shiftDateRange(dateRange models.DateRange, years int) models.DateRange {
+	var shifted models.DateRange
+	if dateRange.From != nil {
+		from := dateRange.From.AddDate(years, 0, 0)
+		shifted.From = &from
+	}
+	if dateRange.To != nil {
+		to := dateRange.To.AddDate(years, 0, 0)
+		shifted.To = &to
+	}
+	return shifted
+}
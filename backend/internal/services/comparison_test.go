@@ -0,0 +1,184 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetMonthlySalesYearOverYear(t *testing.T) {
+	db := newTestDB(t)
+	at := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 15, 12, 0, 0, 0, time.UTC)
+	}
+	rows := []models.Transaction{
+		{OrderID: "1", Country: "US", TransactionDate: at(2023, time.January), Quantity: 1, Revenue: money("100")},
+		{OrderID: "2", Country: "US", TransactionDate: at(2023, time.February), Quantity: 1, Revenue: money("50")},
+		{OrderID: "3", Country: "US", TransactionDate: at(2023, time.March), Quantity: 1, Revenue: money("40")},
+		{OrderID: "4", Country: "US", TransactionDate: at(2024, time.January), Quantity: 1, Revenue: money("120")},
+		{OrderID: "5", Country: "DE", TransactionDate: at(2024, time.January), Quantity: 1, Revenue: money("30")},
+		{OrderID: "6", Country: "US", TransactionDate: at(2024, time.February), Quantity: 1, Revenue: money("25")},
+		{OrderID: "7", Country: "US", TransactionDate: at(2024, time.April), Quantity: 1, Revenue: money("80")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 30, 23, 59, 59, 0, time.UTC)
+	filter := models.SalesFilter{DateRange: models.DateRange{From: &from, To: &to}}
+
+	type period struct {
+		period, revenue, prior string
+		change                 *float64
+	}
+	change := func(pct float64) *float64 { return &pct }
+	tests := []struct {
+		granularity string
+		want        []period
+	}{
+		{
+			granularity: GranularityMonth,
+			want: []period{
+				{"2024-01", "150", "100", change(50)},
+				{"2024-02", "25", "50", change(-50)},
+				// March only sold in the prior year, April only in the current one
+				{"2024-03", "0", "40", change(-100)},
+				{"2024-04", "80", "0", nil},
+			},
+		},
+		{
+			granularity: GranularityQuarter,
+			want: []period{
+				{"2024-Q1", "175", "190", change(-7.89)},
+				{"2024-Q2", "80", "0", nil},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.granularity, func(t *testing.T) {
+			data, _, err := service.GetMonthlySales(ctx, filter, tt.granularity, true, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetMonthlySales: %v", err)
+			}
+			if len(data) != len(tt.want) {
+				t.Fatalf("got %+v, want %d periods", data, len(tt.want))
+			}
+			for i, w := range tt.want {
+				row := data[i]
+				if row.Period != w.period {
+					t.Errorf("row %d period = %s, want %s", i, row.Period, w.period)
+					continue
+				}
+				assertMoney(t, w.period+" revenue", row.Revenue, w.revenue)
+				if row.PriorRevenue == nil {
+					t.Errorf("%s has no prior revenue", w.period)
+				} else {
+					assertMoney(t, w.period+" prior revenue", *row.PriorRevenue, w.prior)
+				}
+				switch {
+				case w.change == nil && row.PercentChange != nil:
+					t.Errorf("%s percent change = %v, want none against a zero prior year", w.period, *row.PercentChange)
+				case w.change != nil && (row.PercentChange == nil || *row.PercentChange != *w.change):
+					t.Errorf("%s percent change = %v, want %v", w.period, row.PercentChange, *w.change)
+				}
+			}
+		})
+	}
+
+	plain, _, err := service.GetMonthlySales(ctx, filter, GranularityMonth, false, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetMonthlySales without comparison: %v", err)
+	}
+	for _, row := range plain {
+		if row.PriorRevenue != nil || row.PercentChange != nil {
+			t.Errorf("%s carries a comparison without compare=yoy: %+v", row.Period, row)
+		}
+	}
+}
+
+func TestComparePeriodsEachMetric(t *testing.T) {
+	db := newTestDB(t)
+	at := func(month time.Month) time.Time {
+		return time.Date(2024, month, 10, 12, 0, 0, 0, time.UTC)
+	}
+	rows := []models.Transaction{
+		{OrderID: "1", Country: "US", TransactionDate: at(time.January), Quantity: 1, Revenue: money("100")},
+		{OrderID: "2", Country: "US", TransactionDate: at(time.March), Quantity: 1, Revenue: money("50")},
+		{OrderID: "3", Country: "US", TransactionDate: at(time.April), Quantity: 1, Revenue: money("60")},
+		{OrderID: "4", Country: "DE", TransactionDate: at(time.May), Quantity: 1, Revenue: money("90")},
+		{OrderID: "5", Country: "US", TransactionDate: at(time.June), Quantity: 1, Revenue: money("150")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	quarter := func(first, last time.Month) models.DateRange {
+		from := time.Date(2024, first, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, last+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+		return models.DateRange{From: &from, To: &to}
+	}
+	q1, q2 := quarter(time.January, time.March), quarter(time.April, time.June)
+	empty := quarter(time.October, time.December)
+
+	str := func(s string) *string { return &s }
+	pct := func(p float64) *float64 { return &p }
+	tests := []struct {
+		name       string
+		a, b       models.DateRange
+		metric     string
+		valueA     *string
+		valueB     *string
+		difference *string
+		change     *float64
+	}{
+		// Q1 holds 2 orders worth 150, Q2 3 orders worth 300
+		{name: "revenue", a: q1, b: q2, metric: MetricRevenue, valueA: str("150.00"), valueB: str("300.00"), difference: str("150.00"), change: pct(100)},
+		{name: "orders", a: q1, b: q2, metric: MetricOrders, valueA: str("2"), valueB: str("3"), difference: str("1"), change: pct(50)},
+		{name: "average order value", a: q1, b: q2, metric: MetricAOV, valueA: str("75.00"), valueB: str("100.00"), difference: str("25.00"), change: pct(33.33)},
+		{name: "decline", a: q2, b: q1, metric: MetricRevenue, valueA: str("300.00"), valueB: str("150.00"), difference: str("-150.00"), change: pct(-50)},
+		{name: "from nothing", a: empty, b: q1, metric: MetricRevenue, valueA: str("0.00"), valueB: str("150.00"), difference: str("150.00")},
+		{name: "no orders to average", a: empty, b: q1, metric: MetricAOV, valueB: str("75.00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := service.ComparePeriods(ctx, tt.a, tt.b, tt.metric, models.Conversion{})
+			if err != nil {
+				t.Fatalf("ComparePeriods: %v", err)
+			}
+			assertOptional(t, "a", got.A.Value, tt.valueA)
+			assertOptional(t, "b", got.B.Value, tt.valueB)
+			assertOptional(t, "difference", got.Difference, tt.difference)
+			if (got.PercentChange == nil) != (tt.change == nil) || (tt.change != nil && *got.PercentChange != *tt.change) {
+				t.Errorf("percent change = %s, want %s", optionalFloat(got.PercentChange), optionalFloat(tt.change))
+			}
+		})
+	}
+}
+
+func assertOptional(t *testing.T, name string, got, want *string) {
+	t.Helper()
+	if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+		t.Errorf("%s = %s, want %s", name, optional(got), optional(want))
+	}
+}
+
+// optional and optionalFloat print a nullable value, or null
+func optional(p *string) string {
+	if p == nil {
+		return "null"
+	}
+	return *p
+}
+
+func optionalFloat(p *float64) string {
+	if p == nil {
+		return "null"
+	}
+	return strconv.FormatFloat(*p, 'f', -1, 64)
+}
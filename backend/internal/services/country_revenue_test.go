@@ -0,0 +1,71 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetCountryRevenuePercentages(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []models.CountryRevenue
+	}{
+		{
+			name: "thirds",
+			rows: []models.CountryRevenue{
+				{Country: "US", Revenue: money("10"), Orders: 1},
+				{Country: "DE", Revenue: money("10"), Orders: 1},
+				{Country: "FR", Revenue: money("10"), Orders: 1},
+			},
+		},
+		{
+			name: "uneven shares",
+			rows: []models.CountryRevenue{
+				{Country: "US", Revenue: money("1234.57"), Orders: 4},
+				{Country: "DE", Revenue: money("98.10"), Orders: 2},
+				{Country: "FR", Revenue: money("0.33"), Orders: 1},
+				{Country: "JP", Revenue: money("7"), Orders: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{currencies: []string{"USD"}, countryRevenue: tt.rows}
+			data, _, err := newTestService(repo).GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetCountryRevenue: %v", err)
+			}
+
+			sum := 0.0
+			for _, row := range data {
+				if row.Percentage <= 0 || row.Percentage > 100 {
+					t.Errorf("%s percentage = %v, want a share in (0, 100]", row.Country, row.Percentage)
+				}
+				sum += row.Percentage
+			}
+			// each share is rounded to two places, so the sum may be off by a
+			// hundredth per row
+			if tolerance := 0.01 * float64(len(data)); math.Abs(sum-100) > tolerance {
+				t.Errorf("percentages sum to %v, want 100 ± %v", sum, tolerance)
+			}
+		})
+	}
+}
+
+func TestGetCountryRevenueEmptyIsAnEmptyList(t *testing.T) {
+	repo := &stubRepository{currencies: []string{"USD"}}
+	data, _, err := newTestService(repo).GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetCountryRevenue: %v", err)
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != "[]" {
+		t.Errorf("empty result encodes as %s, want []", encoded)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/models"
+)
+
+// ErrUnknownCurrency is returned when a currency has no configured exchange rate
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ErrMixedCurrencies is returned when revenue recorded in other currencies than the
+// base currency would be summed without normalization, or cannot be normalized
+var ErrMixedCurrencies = errors.New("transactions are recorded in several currencies")
+
+// conversionRate returns the multiplier converting base-currency revenue into the
+// currency of conversion, after checking that the recorded revenue may be summed.
+// Revenue recorded in other currencies than the base currency is only summed when
+// conversion asks to normalize it, and every such currency then needs a rate.
+func (s *AnalyticsService) conversionRate(ctx context.Context, conversion models.Conversion) (decimal.Decimal, error) {
+	rate, err := s.exchangeRate(conversion.Currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	currencies, err := s.recordedCurrencies(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var foreign, unrated []string
+	for _, code := range currencies {
+		if code == s.baseCurrency {
+			continue
+		}
+		foreign = append(foreign, code)
+		if _, ok := s.rates[code]; !ok {
+			unrated = append(unrated, code)
+		}
+	}
+
+	switch {
+	case !conversion.Normalize && len(foreign) > 0:
+		return decimal.Zero, fmt.Errorf("%w: revenue recorded in %s cannot be added to %s without normalizing it",
+			ErrMixedCurrencies, strings.Join(foreign, ", "), s.baseCurrency)
+	case conversion.Normalize && len(unrated) > 0:
+		return decimal.Zero, fmt.Errorf("%w: revenue recorded in %s cannot be normalized: no exchange rate is configured",
+			ErrMixedCurrencies, strings.Join(unrated, ", "))
+	}
+	return rate, nil
+}
+
+// recordedCurrencies returns the currencies transactions are recorded in, cached
+// with the dimensions since it changes as rarely
+func (s *AnalyticsService) recordedCurrencies(ctx context.Context) ([]string, error) {
+	var currencies []string
+	_, err := s.cached(ctx, "dimensions:currencies", &currencies, func() (interface{}, error) {
+		return s.repo.GetCurrencies(ctx)
+	})
+	return currencies, err
+}
+
+// exchangeRate returns the multiplier converting base-currency revenue into currency.
+// An empty currency means the base currency itself. Results are decoded fresh
+// from the cache on every call, so callers may convert them in place.
+func (s *AnalyticsService) exchangeRate(currency string) (decimal.Decimal, error) {
+	if currency == "" {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, ok := s.rates[currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w %q: supported currencies are %s", ErrUnknownCurrency, currency, s.supportedCurrencies())
+	}
+	return decimal.NewFromFloat(rate), nil
+}
+
+// convert applies an exchange rate to amount, rounding the result to cents
+func convert(amount models.Money, rate decimal.Decimal) models.Money {
+	return models.NewMoney(amount.Mul(rate).Round(2))
+}
+
+// percentOf returns part as a percentage of whole, rounded to two decimals.
+// whole must not be zero.
+func percentOf(part, whole decimal.Decimal) float64 {
+	return part.Mul(decimal.NewFromInt(100)).Div(whole).Round(2).InexactFloat64()
+}
+
+func (s *AnalyticsService) supportedCurrencies() string {
+	codes := make([]string, 0, len(s.rates))
+	for code := range s.rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetCountryRevenueConvertsCurrency(t *testing.T) {
+	repo := &stubRepository{
+		currencies: []string{"USD"},
+		countryRevenue: []models.CountryRevenue{
+			{Country: "US", Revenue: money("10.05"), Orders: 2},
+			{Country: "DE", Revenue: money("30.15"), Orders: 1},
+		},
+	}
+	service := newTestService(repo)
+
+	tests := []struct {
+		name     string
+		currency string
+		revenue  []string
+	}{
+		{name: "base currency by default", revenue: []string{"10.05", "30.15"}},
+		{name: "base currency by code", currency: "USD", revenue: []string{"10.05", "30.15"}},
+		{name: "converted and rounded to cents", currency: "EUR", revenue: []string{"5.03", "15.08"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{Currency: tt.currency})
+			if err != nil {
+				t.Fatalf("GetCountryRevenue: %v", err)
+			}
+			for i, row := range data {
+				assertMoney(t, row.Country+" revenue", row.Revenue, tt.revenue[i])
+				if row.Currency != tt.currency {
+					t.Errorf("%s currency = %q, want %q", row.Country, row.Currency, tt.currency)
+				}
+			}
+			// Shares are taken before conversion, so they do not move with the rate
+			if data[0].Percentage != 25 || data[1].Percentage != 75 {
+				t.Errorf("percentages = %v, %v; want 25, 75", data[0].Percentage, data[1].Percentage)
+			}
+		})
+	}
+}
+
+func TestGetCountryRevenueRejectsUnknownCurrency(t *testing.T) {
+	repo := &stubRepository{currencies: []string{"USD"}}
+
+	_, _, err := newTestService(repo).GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{Currency: "GBP"})
+	if !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("err = %v, want ErrUnknownCurrency", err)
+	}
+	if repo.callCount("GetCountryRevenue") != 0 {
+		t.Error("the repository was queried for an unknown currency")
+	}
+}
+
+func TestGetCountryRevenueMixedCurrencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		currencies []string
+		normalize  bool
+		wantErr    error
+	}{
+		{name: "foreign revenue without normalizing", currencies: []string{"EUR", "USD"}, wantErr: ErrMixedCurrencies},
+		{name: "foreign revenue normalized", currencies: []string{"EUR", "USD"}, normalize: true},
+		{name: "unrated revenue normalized", currencies: []string{"JPY", "USD"}, normalize: true, wantErr: ErrMixedCurrencies},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{currencies: tt.currencies}
+			_, _, err := newTestService(repo).GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{Normalize: tt.normalize})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetCountryRevenueMixedCurrenciesOnSQLite(t *testing.T) {
+	cfg := testConfig()
+	db := newTestDB(t)
+	date := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	rows := []models.Transaction{
+		{TransactionDate: date, Country: "US", Region: "Texas", Product: "Widget", Quantity: 1, Revenue: money("10"), Currency: "USD"},
+		{TransactionDate: date, Country: "US", Region: "Texas", Product: "Widget", Quantity: 1, Revenue: money("10"), Currency: "EUR"},
+		{TransactionDate: date, Country: "DE", Region: "Bavaria", Product: "Widget", Quantity: 1, Revenue: money("5"), Currency: "EUR"},
+		// Stored before currencies were recorded, so in the base currency
+		{TransactionDate: date, Country: "DE", Region: "Bavaria", Product: "Widget", Quantity: 1, Revenue: money("2")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, cfg.Rates))
+
+	if _, _, err := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{}); !errors.Is(err, ErrMixedCurrencies) {
+		t.Fatalf("summing without normalizing: err = %v, want ErrMixedCurrencies", err)
+	}
+
+	// At 0.5 EUR to the dollar, euro revenue counts double in the base currency
+	data, _, err := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{Normalize: true})
+	if err != nil {
+		t.Fatalf("normalized: %v", err)
+	}
+	want := map[string]string{"US": "30", "DE": "12"}
+	if len(data) != len(want) {
+		t.Fatalf("normalized rows = %+v, want %v", data, want)
+	}
+	for _, row := range data {
+		assertMoney(t, row.Country+" revenue", row.Revenue, want[row.Country])
+	}
+}
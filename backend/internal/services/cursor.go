@@ -0,0 +1,59 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// ErrInvalidCursor is returned for pagination cursors not produced by EncodeCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorToken is the serialized form of a TransactionCursor; exactly one of Date
+// and Updated is set
+type cursorToken struct {
+	Date    *time.Time `json:"d,omitempty"`
+	Updated *time.Time `json:"u,omitempty"`
+	ID      uint       `json:"id"`
+}
+
+// EncodeCursor turns a cursor into the opaque URL-safe token handed to clients
+func EncodeCursor(cursor models.TransactionCursor) string {
+	token := cursorToken{ID: cursor.ID}
+	if cursor.UpdatedAt.IsZero() {
+		date := cursor.TransactionDate.UTC()
+		token.Date = &date
+	} else {
+		updated := cursor.UpdatedAt.UTC()
+		token.Updated = &updated
+	}
+	body, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor
+func DecodeCursor(token string) (models.TransactionCursor, error) {
+	body, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.TransactionCursor{}, ErrInvalidCursor
+	}
+
+	var decoded cursorToken
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.ID == 0 {
+		return models.TransactionCursor{}, ErrInvalidCursor
+	}
+
+	cursor := models.TransactionCursor{ID: decoded.ID}
+	switch {
+	case decoded.Date != nil && decoded.Updated == nil && !decoded.Date.IsZero():
+		cursor.TransactionDate = *decoded.Date
+	case decoded.Updated != nil && decoded.Date == nil && !decoded.Updated.IsZero():
+		cursor.UpdatedAt = *decoded.Updated
+	default:
+		return models.TransactionCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// dayLayout labels the days of the daily revenue series
+const dayLayout = "2006-01-02"
+
+// GetDailyRevenue returns the revenue per UTC day across the range in chronological
+// order. Both bounds must be set; every day between them is present, with zero revenue
+// when nothing sold, so the series can be charted without gaps.
+func (s *AnalyticsService) GetDailyRevenue(ctx context.Context, dateRange models.DateRange, conversion models.Conversion) ([]models.DailyRevenue, bool, error) {
+	if dateRange.From == nil || dateRange.To == nil {
+		return nil, false, fmt.Errorf("daily revenue needs a bounded date range")
+	}
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.DailyRevenue
+	key := fmt.Sprintf("daily-revenue:%s", dateRangeKey(dateRange))
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		daily, err := s.repo.GetDailyRevenue(ctx, dateRange)
+		if err != nil {
+			return nil, err
+		}
+		return fillDays(daily, *dateRange.From, *dateRange.To), nil
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	return data, hit, nil
+}
+
+// fillDays expands chronologically ordered daily rows to one row per UTC day from
+// the day of from to the day of to, with zero revenue for the missing days
+func fillDays(daily []models.DailyRevenue, from, to time.Time) []models.DailyRevenue {
+	revenue := make(map[string]models.Money, len(daily))
+	for _, row := range daily {
+		revenue[row.Date] = row.Revenue
+	}
+
+	from = from.UTC()
+	rows := []models.DailyRevenue{}
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC); !day.After(to); day = day.AddDate(0, 0, 1) {
+		rows = append(rows, models.DailyRevenue{Date: day.Format(dayLayout), Revenue: revenue[day.Format(dayLayout)]})
+	}
+	return rows
+}
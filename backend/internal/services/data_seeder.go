@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+// DataSeeder populates the database with transactions, read from cfg.SeedFile
+// when it exists and generated otherwise: the built-in sample set, or
+// cfg.SeedCount random transactions when that is set
+type DataSeeder struct {
+	db        *gorm.DB
+	seedFile  string
+	strict    bool
+	reseed    bool
+	seedCount int
+}
+
+// NewDataSeeder creates a new data seeder
+func NewDataSeeder(db *gorm.DB, cfg *config.Config) *DataSeeder {
+	return &DataSeeder{
+		db:       db,
+		seedFile: cfg.SeedFile,
+		strict:   cfg.SeedStrict,
+		reseed:   cfg.Reseed,
+
+		seedCount: cfg.SeedCount,
+	}
+}
+
+type sampleLocation struct {
+	Country string
+	Region  string
+}
+
+type sampleProduct struct {
+	Name     string
+	Category string
+	Price    float64
+}
+
+const (
+	// sampleCustomers is the number of distinct customers the sample orders are spread over
+	sampleCustomers = 40
+	// seedBatchSize is the number of rows sent per INSERT statement while seeding
+	seedBatchSize = 500
+	// randomSeed seeds the generator of random transactions, so a given count
+	// always produces the same data
+	randomSeed = 20230101
+)
+
+var sampleLocations = []sampleLocation{
+	{"United States", "California"},
+	{"United States", "New York"},
+	{"United States", "Texas"},
+	{"United Kingdom", "England"},
+	{"United Kingdom", "Scotland"},
+	{"Germany", "Bavaria"},
+	{"Germany", "Berlin"},
+	{"France", "Ile-de-France"},
+	{"France", "Provence"},
+	{"Japan", "Tokyo"},
+	{"Japan", "Osaka"},
+	{"Australia", "New South Wales"},
+	{"Australia", "Victoria"},
+	{"Sri Lanka", "Western"},
+	{"Sri Lanka", "Central"},
+}
+
+var sampleProducts = []sampleProduct{
+	{"Laptop Pro 15", "Computers", 1499.99},
+	{"Wireless Mouse", "Accessories", 29.99},
+	{"Mechanical Keyboard", "Accessories", 119.99},
+	{"4K Monitor", "Displays", 399.99},
+	{"USB-C Hub", "Accessories", 49.99},
+	{"Noise Cancelling Headphones", "Audio", 249.99},
+	{"Smartphone X", "Mobile", 999.99},
+	{"Tablet Air", "Mobile", 599.99},
+}
+
+// Run reseeds from scratch when cfg.Reseed was set and upserts the seed data otherwise
+func (s *DataSeeder) Run(ctx context.Context) error {
+	if s.reseed {
+		log.Println("Reseeding database from scratch...")
+		return s.Reseed(ctx)
+	}
+
+	// Seeding upserts on order ID, so it is safe to run against existing data
+	log.Println("Seeding database with sample data...")
+	return s.SeedData(ctx)
+}
+
+// SeedData upserts the seed transactions. Rows are keyed on OrderID, so running
+// it repeatedly never duplicates data and restores any seeded row that has drifted.
+func (s *DataSeeder) SeedData(ctx context.Context) error {
+	transactions, err := s.loadTransactions()
+	if err != nil {
+		return err
+	}
+	return upsertTransactions(s.db.WithContext(ctx), transactions)
+}
+
+// Reseed deletes every transaction and seeds from scratch
+func (s *DataSeeder) Reseed(ctx context.Context) error {
+	transactions, err := s.loadTransactions()
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Transaction{}).Error; err != nil {
+			return err
+		}
+		return upsertTransactions(tx, transactions)
+	})
+}
+
+// loadTransactions reads the seed file, falling back to generated transactions when it is absent
+func (s *DataSeeder) loadTransactions() ([]models.Transaction, error) {
+	if s.seedFile == "" {
+		return s.generateTransactions(), nil
+	}
+
+	file, err := os.Open(s.seedFile)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Seed file %s not found, using generated data", s.seedFile)
+		return s.generateTransactions(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	transactions, rowErrs, err := parseTransactionsCSV(file, s.strict)
+	if err != nil {
+		return nil, fmt.Errorf("seed file %s: %w", s.seedFile, err)
+	}
+	for _, rowErr := range rowErrs {
+		log.Printf("Warning: seed file %s: skipped %v", s.seedFile, rowErr)
+	}
+	log.Printf("Loaded %d transactions from %s", len(transactions), s.seedFile)
+	return transactions, nil
+}
+
+// generateTransactions returns the built-in sample set, or seedCount random
+// transactions when a count was configured
+func (s *DataSeeder) generateTransactions() []models.Transaction {
+	if s.seedCount > 0 {
+		log.Printf("Generating %d random transactions", s.seedCount)
+		return generateRandomTransactions(s.seedCount)
+	}
+	return generateSampleTransactions()
+}
+
+// upsertTransactions inserts or refreshes transactions by order ID, seedBatchSize
+// rows per statement so large seeds stay within the databases' parameter limits
+func upsertTransactions(db *gorm.DB, transactions []models.Transaction) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "order_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"customer_id", "transaction_date", "country", "region", "product", "category", "quantity", "revenue", "currency", "updated_at"}),
+	}).CreateInBatches(&transactions, seedBatchSize).Error
+}
+
+func generateSampleTransactions() []models.Transaction {
+	var transactions []models.Transaction
+
+	for month := 1; month <= 12; month++ {
+		for i, location := range sampleLocations {
+			for j, product := range sampleProducts {
+				units := (i+month)%5 + 1 + j%4
+				day := (i*7+j*3+month)%28 + 1
+
+				transactions = append(transactions, models.Transaction{
+					OrderID:         fmt.Sprintf("SEED-2023%02d-%02d-%02d", month, i, j),
+					CustomerID:      fmt.Sprintf("CUST-%03d", (i*11+j*5+month)%sampleCustomers+1),
+					TransactionDate: time.Date(2023, time.Month(month), day, 12, 0, 0, 0, time.UTC),
+					Country:         location.Country,
+					Region:          location.Region,
+					Product:         product.Name,
+					Category:        product.Category,
+					Quantity:        units,
+					Revenue:         models.NewMoney(decimal.NewFromFloat(product.Price).Mul(decimal.NewFromInt(int64(units)))),
+				})
+			}
+		}
+	}
+
+	return transactions
+}
+
+// generateRandomTransactions returns n random transactions dated across 2023, drawn
+// reproducibly from the sample locations and products. Order sizes lean towards a
+// single unit and customers repeat, roughly twenty orders each.
+func generateRandomTransactions(n int) []models.Transaction {
+	rng := rand.New(rand.NewSource(randomSeed))
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearSeconds := int64(start.AddDate(1, 0, 0).Sub(start) / time.Second)
+	customers := n / 20
+	if customers < sampleCustomers {
+		customers = sampleCustomers
+	}
+
+	transactions := make([]models.Transaction, n)
+	for i := range transactions {
+		location := sampleLocations[rng.Intn(len(sampleLocations))]
+		product := sampleProducts[rng.Intn(len(sampleProducts))]
+		units := 1 + rng.Intn(2)*rng.Intn(5)
+
+		transactions[i] = models.Transaction{
+			OrderID:         fmt.Sprintf("GEN-%08d", i+1),
+			CustomerID:      fmt.Sprintf("CUST-%06d", rng.Intn(customers)+1),
+			TransactionDate: start.Add(time.Duration(rng.Int63n(yearSeconds)) * time.Second),
+			Country:         location.Country,
+			Region:          location.Region,
+			Product:         product.Name,
+			Category:        product.Category,
+			Quantity:        units,
+			Revenue:         models.NewMoney(decimal.NewFromFloat(product.Price).Mul(decimal.NewFromInt(int64(units)))),
+		}
+	}
+	return transactions
+}
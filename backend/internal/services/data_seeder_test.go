@@ -0,0 +1,182 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+// countTransactions returns the number of live transactions and of distinct order IDs among them
+func countTransactions(t *testing.T, seeder *DataSeeder) (rows, orders int64) {
+	t.Helper()
+	if err := seeder.db.Model(&models.Transaction{}).Count(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := seeder.db.Model(&models.Transaction{}).Distinct("order_id").Count(&orders).Error; err != nil {
+		t.Fatal(err)
+	}
+	return rows, orders
+}
+
+func TestSeedDataIsIdempotent(t *testing.T) {
+	seeder := NewDataSeeder(newTestDB(t), &config.Config{})
+
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatalf("first seed: %v", err)
+	}
+	first, _ := countTransactions(t, seeder)
+	if want := int64(len(generateSampleTransactions())); first != want {
+		t.Fatalf("first seed stored %d rows, want %d", first, want)
+	}
+
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatalf("second seed: %v", err)
+	}
+	rows, orders := countTransactions(t, seeder)
+	if rows != first || orders != rows {
+		t.Errorf("after seeding twice: %d rows over %d order IDs, want %d of each", rows, orders, first)
+	}
+}
+
+func TestSeedDataRestoresDrift(t *testing.T) {
+	seeder := NewDataSeeder(newTestDB(t), &config.Config{})
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sample := generateSampleTransactions()[0]
+	if err := seeder.db.Model(&models.Transaction{}).Where("order_id = ?", sample.OrderID).
+		Update("quantity", sample.Quantity+100).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var restored models.Transaction
+	if err := seeder.db.Where("order_id = ?", sample.OrderID).First(&restored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if restored.Quantity != sample.Quantity {
+		t.Errorf("quantity = %d after reseeding, want the seeded %d", restored.Quantity, sample.Quantity)
+	}
+}
+
+func TestReseedDropsOtherRows(t *testing.T) {
+	seeder := NewDataSeeder(newTestDB(t), &config.Config{SeedCount: 50})
+	extra := models.Transaction{OrderID: "MANUAL-1", Country: "US", Quantity: 1, Revenue: money("1")}
+	if err := seeder.db.Create(&extra).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := seeder.Reseed(ctx); err != nil {
+		t.Fatalf("Reseed: %v", err)
+	}
+	if rows, orders := countTransactions(t, seeder); rows != 50 || orders != 50 {
+		t.Errorf("after reseeding: %d rows over %d order IDs, want 50 of each", rows, orders)
+	}
+}
+
+func TestSeedCountGeneratesReproducibleRows(t *testing.T) {
+	const n = 1000
+	if !reflect.DeepEqual(generateRandomTransactions(n), generateRandomTransactions(n)) {
+		t.Fatal("generated transactions differ between runs")
+	}
+
+	db := newTestDB(t)
+	if err := NewDataSeeder(db, &config.Config{SeedCount: n}).SeedData(ctx); err != nil {
+		t.Fatalf("SeedData: %v", err)
+	}
+	var rows int64
+	if err := db.Model(&models.Transaction{}).Count(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rows != n {
+		t.Fatalf("seeded %d rows, want %d", rows, n)
+	}
+
+	repo := repository.NewAnalyticsRepository(db, nil)
+	countries, err := repo.GetCountryRevenue(ctx, models.CountryFilter{})
+	if err != nil || len(countries) == 0 {
+		t.Errorf("country revenue = %v, %v; want rows", countries, err)
+	}
+	var orders int64
+	for _, country := range countries {
+		orders += country.Orders
+	}
+	if orders != n {
+		t.Errorf("country revenue counts %d orders, want %d", orders, n)
+	}
+	if products, _, err := repo.GetTopProducts(ctx, models.ProductFilter{}, models.ProductSort{By: models.ProductSortRevenue}, 10, 0); err != nil || len(products) == 0 {
+		t.Errorf("top products = %v, %v; want rows", products, err)
+	}
+	if months, err := repo.GetMonthlySales(ctx, models.SalesFilter{}); err != nil || len(months) != 12 {
+		t.Errorf("monthly sales = %d months, %v; want all of 2023", len(months), err)
+	}
+	if customers, total, err := repo.GetTopCustomers(ctx, models.DateRange{}, 10, 0); err != nil || len(customers) == 0 || total == 0 {
+		t.Errorf("top customers = %v of %d, %v; want rows", customers, total, err)
+	}
+}
+
+func TestSeededRevenueAndOrderCountsAgree(t *testing.T) {
+	db := newTestDB(t)
+	if err := NewDataSeeder(db, &config.Config{SeedCount: 1000}).SeedData(ctx); err != nil {
+		t.Fatalf("SeedData: %v", err)
+	}
+	var rows []models.Transaction
+	if err := db.Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	type totals struct {
+		revenue models.Money
+		orders  int64
+	}
+	byCountry, byRegion := map[string]totals{}, map[string]totals{}
+	add := func(group map[string]totals, key string, row models.Transaction) {
+		sum := group[key]
+		sum.revenue = models.NewMoney(sum.revenue.Add(row.Revenue.Decimal))
+		sum.orders++
+		group[key] = sum
+	}
+	for _, row := range rows {
+		add(byCountry, row.Country, row)
+		add(byRegion, row.Region, row)
+	}
+
+	repo := repository.NewAnalyticsRepository(db, nil)
+	countries, err := repo.GetCountryRevenue(ctx, models.CountryFilter{})
+	if err != nil {
+		t.Fatalf("GetCountryRevenue: %v", err)
+	}
+	if len(countries) != len(byCountry) {
+		t.Fatalf("got %d countries, want %d", len(countries), len(byCountry))
+	}
+	for _, country := range countries {
+		want := byCountry[country.Country]
+		if country.Orders == 0 || country.Orders != want.orders {
+			t.Errorf("%s: %d orders, want %d", country.Country, country.Orders, want.orders)
+		}
+		if got := country.Revenue.Format(); got != want.revenue.Format() {
+			t.Errorf("%s: revenue %s, want %s", country.Country, got, want.revenue.Format())
+		}
+	}
+
+	regions, err := repo.GetTopRegions(ctx, models.RegionFilter{}, len(byRegion))
+	if err != nil {
+		t.Fatalf("GetTopRegions: %v", err)
+	}
+	if len(regions) != len(byRegion) {
+		t.Fatalf("got %d regions, want %d", len(regions), len(byRegion))
+	}
+	for _, region := range regions {
+		want := byRegion[region.Region]
+		if region.Orders == 0 || region.Orders != want.orders {
+			t.Errorf("%s: %d orders, want %d", region.Region, region.Orders, want.orders)
+		}
+		if got := region.Revenue.Format(); got != want.revenue.Format() {
+			t.Errorf("%s: revenue %s, want %s", region.Region, got, want.revenue.Format())
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/models"
+)
+
+// ErrDemoReadOnly is returned when demo mode is asked to store or delete transactions
+var ErrDemoReadOnly = errors.New("demo data is read-only")
+
+// ErrDemoNoDatabase is returned when demo mode is asked about the database it runs without
+var ErrDemoNoDatabase = errors.New("demo mode runs without a database")
+
+// DemoAnalyticsService answers every analytics request from the built-in sample
+// transactions held in memory, so the dashboard stays usable without a database.
+// It runs the same aggregation, conversion and comparison logic as the real
+// service; the data never changes and writes fail with ErrDemoReadOnly.
+type DemoAnalyticsService struct {
+	*AnalyticsService
+}
+
+// NewDemoAnalyticsService creates a demo service converting revenue with cfg.Rates
+func NewDemoAnalyticsService(cfg *config.Config) *DemoAnalyticsService {
+	return &DemoAnalyticsService{AnalyticsService: NewAnalyticsService(newDemoRepository(), nil, cfg)}
+}
+
+var _ AnalyticsRepository = (*demoRepository)(nil)
+
+// demoRepository implements AnalyticsRepository over an in-memory copy of the
+// sample transactions, kept newest first like the transaction listing
+type demoRepository struct {
+	transactions []models.Transaction
+}
+
+// demoTotals accumulates one group of an in-memory aggregation
+type demoTotals struct {
+	revenue models.Money
+	units   int64
+	orders  int64
+}
+
+func newDemoRepository() *demoRepository {
+	transactions := generateSampleTransactions()
+	loaded := time.Now().UTC()
+	for i := range transactions {
+		transactions[i].ID = uint(i + 1)
+		transactions[i].CreatedAt, transactions[i].UpdatedAt = loaded, loaded
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return listedBefore(transactions[i], transactions[j])
+	})
+	return &demoRepository{transactions: transactions}
+}
+
+// listedBefore orders transactions newest first, then by descending ID
+func listedBefore(a, b models.Transaction) bool {
+	if !a.TransactionDate.Equal(b.TransactionDate) {
+		return a.TransactionDate.After(b.TransactionDate)
+	}
+	return a.ID > b.ID
+}
+
+// changedBefore orders transactions oldest change first, then by ascending ID
+func changedBefore(a, b models.Transaction) bool {
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// inRange reports whether t falls within the inclusive date range
+func inRange(t time.Time, dateRange models.DateRange) bool {
+	if dateRange.From != nil && t.Before(*dateRange.From) {
+		return false
+	}
+	if dateRange.To != nil && t.After(*dateRange.To) {
+		return false
+	}
+	return true
+}
+
+// listingMatches reports whether t passes the filter of a transaction listing
+func listingMatches(t models.Transaction, filter models.TransactionFilter) bool {
+	return (filter.Country == "" || strings.EqualFold(t.Country, filter.Country)) &&
+		(filter.Product == "" || strings.EqualFold(t.Product, filter.Product)) &&
+		(filter.Region == "" || strings.EqualFold(t.Region, filter.Region)) &&
+		(filter.UpdatedSince == nil || t.UpdatedAt.After(*filter.UpdatedSince)) &&
+		inRange(t.TransactionDate, filter.DateRange)
+}
+
+// continuesAfter reports whether t follows the cursor in the listing order of filter
+func continuesAfter(t models.Transaction, after models.TransactionCursor, filter models.TransactionFilter) bool {
+	if filter.UpdatedSince != nil {
+		return changedBefore(models.Transaction{UpdatedAt: after.UpdatedAt, ID: after.ID}, t)
+	}
+	return listedBefore(models.Transaction{TransactionDate: after.TransactionDate, ID: after.ID}, t)
+}
+
+// listing returns the transactions in the listing order of filter
+func (r *demoRepository) listing(filter models.TransactionFilter) []models.Transaction {
+	if filter.UpdatedSince == nil {
+		return r.transactions
+	}
+	changed := append([]models.Transaction(nil), r.transactions...)
+	sort.SliceStable(changed, func(i, j int) bool { return changedBefore(changed[i], changed[j]) })
+	return changed
+}
+
+// aggregate totals the transactions accepted by keep, grouped by key
+func (r *demoRepository) aggregate(keep func(t *models.Transaction) bool, key func(t *models.Transaction) string) map[string]*demoTotals {
+	groups := make(map[string]*demoTotals)
+	for i := range r.transactions {
+		t := &r.transactions[i]
+		if !keep(t) {
+			continue
+		}
+		totals, ok := groups[key(t)]
+		if !ok {
+			totals = &demoTotals{}
+			groups[key(t)] = totals
+		}
+		totals.revenue = models.NewMoney(totals.revenue.Add(t.Revenue.Decimal))
+		totals.units += int64(t.Quantity)
+		totals.orders++
+	}
+	return groups
+}
+
+func (r *demoRepository) Ping(_ context.Context) error {
+	return nil
+}
+
+func (r *demoRepository) GetMigrationStatus(_ context.Context) (*models.MigrationStatus, error) {
+	return nil, ErrDemoNoDatabase
+}
+
+func (r *demoRepository) GetCountryRevenue(_ context.Context, filter models.CountryFilter) ([]models.CountryRevenue, error) {
+	countries := make(map[string]bool, len(filter.Countries))
+	for _, country := range filter.Countries {
+		countries[strings.ToLower(country)] = true
+	}
+	groups := r.aggregate(
+		func(t *models.Transaction) bool {
+			return (len(countries) == 0 || countries[strings.ToLower(t.Country)]) && inRange(t.TransactionDate, filter.DateRange)
+		},
+		func(t *models.Transaction) string { return t.Country },
+	)
+
+	results := make([]models.CountryRevenue, 0, len(groups))
+	for country, totals := range groups {
+		results = append(results, models.CountryRevenue{Country: country, Revenue: totals.revenue, Orders: totals.orders})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Revenue.Equal(results[j].Revenue.Decimal) {
+			return results[i].Revenue.GreaterThan(results[j].Revenue.Decimal)
+		}
+		return results[i].Country < results[j].Country
+	})
+	return results, nil
+}
+
+func (r *demoRepository) GetCategoryRevenue(_ context.Context, dateRange models.DateRange) ([]models.CategoryRevenue, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return t.Category },
+	)
+
+	results := make([]models.CategoryRevenue, 0, len(groups))
+	for category, totals := range groups {
+		results = append(results, models.CategoryRevenue{Category: category, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Revenue.Equal(results[j].Revenue.Decimal) {
+			return results[i].Revenue.GreaterThan(results[j].Revenue.Decimal)
+		}
+		return results[i].Category < results[j].Category
+	})
+	return results, nil
+}
+
+func (r *demoRepository) GetAverageOrderValue(_ context.Context, dateRange models.DateRange) ([]models.CountryOrderValue, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return t.Country },
+	)
+
+	results := make([]models.CountryOrderValue, 0, len(groups))
+	for country, totals := range groups {
+		results = append(results, models.CountryOrderValue{
+			Country:           country,
+			AverageOrderValue: models.NewMoney(totals.revenue.Div(decimal.NewFromInt(totals.orders))),
+			Orders:            totals.orders,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].AverageOrderValue.Equal(results[j].AverageOrderValue.Decimal) {
+			return results[i].AverageOrderValue.GreaterThan(results[j].AverageOrderValue.Decimal)
+		}
+		return results[i].Country < results[j].Country
+	})
+	return results, nil
+}
+
+func (r *demoRepository) GetOrderValuePercentiles(_ context.Context, dateRange models.DateRange) (*models.OrderValuePercentiles, error) {
+	var values []models.Money
+	for _, t := range r.transactions {
+		if inRange(t.TransactionDate, dateRange) {
+			values = append(values, t.Revenue)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].LessThan(values[j].Decimal) })
+
+	return models.NewOrderValuePercentiles(int64(len(values)), func(rank int64) (models.Money, error) {
+		return values[rank-1], nil
+	})
+}
+
+func (r *demoRepository) GetPeriodTotals(_ context.Context, dateRange models.DateRange) (*models.PeriodTotals, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return "" },
+	)
+
+	var totals models.PeriodTotals
+	if group, ok := groups[""]; ok {
+		totals.Orders, totals.Revenue = group.orders, group.revenue
+	}
+	return &totals, nil
+}
+
+func (r *demoRepository) GetTopProducts(_ context.Context, filter models.ProductFilter, productSort models.ProductSort, limit, offset int) ([]models.ProductRevenue, int64, error) {
+	if productSort.By != models.ProductSortRevenue && productSort.By != models.ProductSortUnits {
+		return nil, 0, fmt.Errorf("%w %q", models.ErrUnknownSortField, productSort.By)
+	}
+	groups := r.aggregate(
+		func(t *models.Transaction) bool {
+			return (filter.Country == "" || strings.EqualFold(t.Country, filter.Country)) && inRange(t.TransactionDate, filter.DateRange)
+		},
+		func(t *models.Transaction) string { return t.Product },
+	)
+
+	results := make([]models.ProductRevenue, 0, len(groups))
+	for product, totals := range groups {
+		results = append(results, models.ProductRevenue{Product: product, Units: totals.units, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if productSort.By == models.ProductSortUnits && a.Units != b.Units {
+			return (a.Units < b.Units) == productSort.Ascending
+		}
+		if productSort.By != models.ProductSortUnits && !a.Revenue.Equal(b.Revenue.Decimal) {
+			return a.Revenue.LessThan(b.Revenue.Decimal) == productSort.Ascending
+		}
+		return a.Product < b.Product
+	})
+
+	total := int64(len(results))
+	if offset > len(results) {
+		offset = len(results)
+	}
+	results = results[offset:]
+	if limit < len(results) {
+		results = results[:limit]
+	}
+	return results, total, nil
+}
+
+func (r *demoRepository) GetRankedProducts(_ context.Context, dateRange models.DateRange) ([]models.ProductRevenue, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return t.Product },
+	)
+
+	results := make([]models.ProductRevenue, 0, len(groups))
+	for product, totals := range groups {
+		results = append(results, models.ProductRevenue{Product: product, Units: totals.units, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Revenue.Equal(results[j].Revenue.Decimal) {
+			return results[i].Revenue.GreaterThan(results[j].Revenue.Decimal)
+		}
+		return results[i].Product < results[j].Product
+	})
+	return results, nil
+}
+
+func (r *demoRepository) GetTopCustomers(_ context.Context, dateRange models.DateRange, limit, offset int) ([]models.CustomerRevenue, int64, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return t.CustomerID != "" && inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return t.CustomerID },
+	)
+
+	results := make([]models.CustomerRevenue, 0, len(groups))
+	for customer, totals := range groups {
+		results = append(results, models.CustomerRevenue{CustomerID: customer, Orders: totals.orders, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if !a.Revenue.Equal(b.Revenue.Decimal) {
+			return a.Revenue.GreaterThan(b.Revenue.Decimal)
+		}
+		return a.CustomerID < b.CustomerID
+	})
+
+	total := int64(len(results))
+	if offset > len(results) {
+		offset = len(results)
+	}
+	results = results[offset:]
+	if limit < len(results) {
+		results = results[:limit]
+	}
+	return results, total, nil
+}
+
+func (r *demoRepository) GetMonthlySales(_ context.Context, filter models.SalesFilter) ([]models.MonthlySales, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool {
+			return (filter.Product == "" || strings.EqualFold(t.Product, filter.Product)) &&
+				(filter.Category == "" || strings.EqualFold(t.Category, filter.Category)) &&
+				inRange(t.TransactionDate, filter.DateRange)
+		},
+		func(t *models.Transaction) string { return t.TransactionDate.In(filter.TimeZone()).Format("2006-01") },
+	)
+
+	results := make([]models.MonthlySales, 0, len(groups))
+	for period, totals := range groups {
+		results = append(results, models.MonthlySales{Period: period, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Period < results[j].Period })
+	return results, nil
+}
+
+func (r *demoRepository) GetDailyRevenue(_ context.Context, dateRange models.DateRange) ([]models.DailyRevenue, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) },
+		func(t *models.Transaction) string { return t.TransactionDate.UTC().Format(dayLayout) },
+	)
+
+	results := make([]models.DailyRevenue, 0, len(groups))
+	for date, totals := range groups {
+		results = append(results, models.DailyRevenue{Date: date, Revenue: totals.revenue})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date < results[j].Date })
+	return results, nil
+}
+
+func (r *demoRepository) GetTopRegions(_ context.Context, filter models.RegionFilter, n int) ([]models.RegionRevenue, error) {
+	groups := r.aggregate(
+		func(t *models.Transaction) bool {
+			return (filter.Country == "" || strings.EqualFold(t.Country, filter.Country)) && inRange(t.TransactionDate, filter.DateRange)
+		},
+		func(t *models.Transaction) string { return t.Region },
+	)
+
+	results := make([]models.RegionRevenue, 0, len(groups))
+	for region, totals := range groups {
+		results = append(results, models.RegionRevenue{Region: region, Revenue: totals.revenue, Orders: totals.orders})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Revenue.Equal(results[j].Revenue.Decimal) {
+			return results[i].Revenue.GreaterThan(results[j].Revenue.Decimal)
+		}
+		return results[i].Region < results[j].Region
+	})
+	if n < len(results) {
+		results = results[:n]
+	}
+	return results, nil
+}
+
+func (r *demoRepository) GetRegionTrends(_ context.Context, dateRange models.DateRange, n int) ([]models.RegionMonthRevenue, error) {
+	keep := func(t *models.Transaction) bool { return inRange(t.TransactionDate, dateRange) }
+
+	regions := r.aggregate(keep, func(t *models.Transaction) string { return t.Region })
+	top := make([]models.RegionRevenue, 0, len(regions))
+	for region, totals := range regions {
+		top = append(top, models.RegionRevenue{Region: region, Revenue: totals.revenue})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if !top[i].Revenue.Equal(top[j].Revenue.Decimal) {
+			return top[i].Revenue.GreaterThan(top[j].Revenue.Decimal)
+		}
+		return top[i].Region < top[j].Region
+	})
+	if n < len(top) {
+		top = top[:n]
+	}
+	results := []models.RegionMonthRevenue{}
+	for _, region := range top {
+		groups := r.aggregate(
+			func(t *models.Transaction) bool { return t.Region == region.Region && keep(t) },
+			func(t *models.Transaction) string { return t.TransactionDate.UTC().Format(monthLayout) },
+		)
+		for period, totals := range groups {
+			results = append(results, models.RegionMonthRevenue{Region: region.Region, Period: period, Revenue: totals.revenue})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Period != results[j].Period {
+			return results[i].Period < results[j].Period
+		}
+		return results[i].Region < results[j].Region
+	})
+	return results, nil
+}
+
+func (r *demoRepository) ListTransactions(_ context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) ([]models.Transaction, int64, error) {
+	results := []models.Transaction{}
+	var total int64
+
+	for _, t := range r.listing(filter) {
+		if !listingMatches(t, filter) {
+			continue
+		}
+		total++
+
+		if after != nil && !continuesAfter(t, *after, filter) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(results) < limit {
+			results = append(results, t)
+		}
+	}
+	return results, total, nil
+}
+
+func (r *demoRepository) GetTransaction(_ context.Context, id uint) (*models.Transaction, error) {
+	for i := range r.transactions {
+		if r.transactions[i].ID == id {
+			transaction := r.transactions[i]
+			return &transaction, nil
+		}
+	}
+	return nil, models.ErrTransactionNotFound
+}
+
+func (r *demoRepository) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error {
+	for _, t := range r.listing(filter) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !listingMatches(t, filter) {
+			continue
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *demoRepository) InsertTransactions(_ context.Context, _ []models.Transaction) error {
+	return ErrDemoReadOnly
+}
+
+func (r *demoRepository) DeleteTransaction(_ context.Context, _ uint) error {
+	return ErrDemoReadOnly
+}
+
+func (r *demoRepository) GetDataMeta(_ context.Context) (*models.DataMeta, error) {
+	meta := &models.DataMeta{TotalTransactions: int64(len(r.transactions))}
+	if len(r.transactions) > 0 {
+		last := r.transactions[0].TransactionDate
+		meta.LastTransactionDate = &last
+	}
+	return meta, nil
+}
+
+func (r *demoRepository) GetDimensions(_ context.Context) (*models.Dimensions, error) {
+	values := func(field func(t *models.Transaction) string) []string {
+		seen := map[string]bool{}
+		result := []string{}
+		for i := range r.transactions {
+			if value := field(&r.transactions[i]); value != "" && !seen[value] {
+				seen[value] = true
+				result = append(result, value)
+			}
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	return &models.Dimensions{
+		Countries:  values(func(t *models.Transaction) string { return t.Country }),
+		Regions:    values(func(t *models.Transaction) string { return t.Region }),
+		Products:   values(func(t *models.Transaction) string { return t.Product }),
+		Categories: values(func(t *models.Transaction) string { return t.Category }),
+	}, nil
+}
+
+// GetCurrencies reports no currencies: the sample transactions are all recorded in the base currency
+func (r *demoRepository) GetCurrencies(_ context.Context) ([]string, error) {
+	return []string{}, nil
+}
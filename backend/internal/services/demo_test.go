@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+func TestDemoServiceReturnsStableData(t *testing.T) {
+	byRevenue := models.ProductSort{By: models.ProductSortRevenue}
+	endpoints := map[string]func(s *DemoAnalyticsService) (interface{}, error){
+		"country revenue": func(s *DemoAnalyticsService) (interface{}, error) {
+			data, _, err := s.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+			return data, err
+		},
+		"top products": func(s *DemoAnalyticsService) (interface{}, error) {
+			page, _, err := s.GetTopProducts(ctx, models.ProductFilter{}, byRevenue, 20, 0, models.Conversion{})
+			if err != nil {
+				return nil, err
+			}
+			return page.Data, nil
+		},
+		"monthly sales": func(s *DemoAnalyticsService) (interface{}, error) {
+			data, _, err := s.GetMonthlySales(ctx, models.SalesFilter{}, GranularityMonth, false, models.Conversion{})
+			return data, err
+		},
+		"top regions": func(s *DemoAnalyticsService) (interface{}, error) {
+			data, _, err := s.GetTopRegions(ctx, models.RegionFilter{}, 30, models.Conversion{})
+			return data, err
+		},
+	}
+
+	first, second := NewDemoAnalyticsService(testConfig()), NewDemoAnalyticsService(testConfig())
+	for name, load := range endpoints {
+		t.Run(name, func(t *testing.T) {
+			var bodies []string
+			for _, service := range []*DemoAnalyticsService{first, first, second} {
+				data, err := load(service)
+				if err != nil {
+					t.Fatalf("load: %v", err)
+				}
+				body, err := json.Marshal(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				bodies = append(bodies, string(body))
+			}
+			if bodies[0] == "[]" || bodies[0] == "null" {
+				t.Fatalf("demo data is empty: %s", bodies[0])
+			}
+			if bodies[1] != bodies[0] {
+				t.Error("a repeated request returned different data")
+			}
+			if bodies[2] != bodies[0] {
+				t.Error("a second demo service returned different data")
+			}
+		})
+	}
+}
+
+func TestDemoServiceIsReadOnly(t *testing.T) {
+	service := NewDemoAnalyticsService(testConfig())
+	input := models.TransactionInput{TransactionDate: time.Now(), Country: "US", Region: "Texas", Product: "Widget", Revenue: money("1")}
+
+	if _, err := service.IngestTransactions(ctx, []models.TransactionInput{input}); !errors.Is(err, ErrDemoReadOnly) {
+		t.Errorf("ingest error = %v, want ErrDemoReadOnly", err)
+	}
+	if err := service.DeleteTransaction(ctx, 1); !errors.Is(err, ErrDemoReadOnly) {
+		t.Errorf("delete error = %v, want ErrDemoReadOnly", err)
+	}
+}
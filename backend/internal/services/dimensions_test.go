@@ -0,0 +1,63 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetDimensionsListsSeededValuesSorted(t *testing.T) {
+	db := newTestDB(t)
+	if err := NewDataSeeder(db, &config.Config{}).SeedData(ctx); err != nil {
+		t.Fatalf("SeedData: %v", err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	// The expected values come straight from the sample set, with duplicates dropped
+	distinct := func(values []string) []string {
+		seen := map[string]bool{}
+		var unique []string
+		for _, value := range values {
+			if !seen[value] {
+				seen[value] = true
+				unique = append(unique, value)
+			}
+		}
+		sort.Strings(unique)
+		return unique
+	}
+	var countries, regions, products, categories []string
+	for _, location := range sampleLocations {
+		countries = append(countries, location.Country)
+		regions = append(regions, location.Region)
+	}
+	for _, product := range sampleProducts {
+		products = append(products, product.Name)
+		categories = append(categories, product.Category)
+	}
+
+	dimensions, hit, err := service.GetDimensions(ctx)
+	if err != nil || hit {
+		t.Fatalf("GetDimensions = hit %v, %v; want a fresh result", hit, err)
+	}
+	for _, tt := range []struct {
+		name      string
+		got, want []string
+	}{
+		{"countries", dimensions.Countries, distinct(countries)},
+		{"regions", dimensions.Regions, distinct(regions)},
+		{"products", dimensions.Products, distinct(products)},
+		{"categories", dimensions.Categories, distinct(categories)},
+	} {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if _, hit, err := service.GetDimensions(ctx); err != nil || !hit {
+		t.Errorf("second GetDimensions = hit %v, %v; want it served from the cache", hit, err)
+	}
+}
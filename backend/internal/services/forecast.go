@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/models"
+)
+
+// minForecastWindow is the fewest months a trend can be fitted through
+const minForecastWindow = 2
+
+// ErrInsufficientHistory is returned when there are fewer months of sales than
+// a forecast is asked to fit its trend through
+var ErrInsufficientHistory = errors.New("not enough monthly sales history to forecast")
+
+// GetRevenueForecast projects the monthly revenue of the sales matching the filter
+// months ahead. A straight line is fitted by least squares through the last window
+// calendar months up to the last month with sales, months without sales counting as
+// zero revenue, and extended past it; projections below zero are reported as zero.
+// Those window months are returned as recorded, followed by the projected ones with
+// Forecast set. A month still in progress counts as recorded so far, so end the date
+// range at the last complete month to keep it out of the trend.
+func (s *AnalyticsService) GetRevenueForecast(ctx context.Context, filter models.SalesFilter, months, window int, conversion models.Conversion) ([]models.RevenueForecast, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.RevenueForecast
+	key := fmt.Sprintf("forecast:%d:%d:%s:%s:%s:%s",
+		months, window, filter.Product, filter.Category, dateRangeKey(filter.DateRange), filter.TimeZone())
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		monthly, err := s.repo.GetMonthlySales(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		history, err := fillMonths(monthly)
+		if err != nil {
+			return nil, err
+		}
+		return forecastRevenue(history, months, window)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	return data, hit, nil
+}
+
+// forecastRevenue fits the trend of the last window of the zero-filled months in
+// history and extends it months past them, rounding projections to two decimals
+func forecastRevenue(history []models.MonthlySales, months, window int) ([]models.RevenueForecast, error) {
+	if window < minForecastWindow {
+		window = minForecastWindow
+	}
+	if len(history) < window {
+		return nil, fmt.Errorf("%w: %d month(s) of sales where the trend needs %d", ErrInsufficientHistory, len(history), window)
+	}
+	history = history[len(history)-window:]
+
+	last, err := time.Parse("2006-01", history[len(history)-1].Period)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected month label %q: %w", history[len(history)-1].Period, err)
+	}
+
+	// Months are numbered 0..window-1, so their mean sits halfway along
+	n := decimal.NewFromInt(int64(window))
+	meanX := decimal.NewFromInt(int64(window - 1)).Div(decimal.NewFromInt(2))
+	sumY := decimal.Zero
+	for _, month := range history {
+		sumY = sumY.Add(month.Revenue.Decimal)
+	}
+	meanY := sumY.Div(n)
+
+	var sxx, sxy decimal.Decimal
+	for i, month := range history {
+		dx := decimal.NewFromInt(int64(i)).Sub(meanX)
+		sxx = sxx.Add(dx.Mul(dx))
+		sxy = sxy.Add(dx.Mul(month.Revenue.Sub(meanY)))
+	}
+	slope := sxy.Div(sxx)
+	intercept := meanY.Sub(slope.Mul(meanX))
+
+	rows := make([]models.RevenueForecast, 0, window+months)
+	for _, month := range history {
+		rows = append(rows, models.RevenueForecast{Period: month.Period, Revenue: month.Revenue})
+	}
+	for step := 1; step <= months; step++ {
+		x := decimal.NewFromInt(int64(window - 1 + step))
+		projected := intercept.Add(slope.Mul(x)).Round(2)
+		if projected.IsNegative() {
+			projected = decimal.Zero
+		}
+		rows = append(rows, models.RevenueForecast{
+			Period:   last.AddDate(0, step, 0).Format("2006-01"),
+			Revenue:  models.NewMoney(projected),
+			Forecast: true,
+		})
+	}
+	return rows, nil
+}
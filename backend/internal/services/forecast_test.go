@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+// monthlySeries returns consecutive months of 2024 from January with the given revenue
+func monthlySeries(revenue ...string) []models.MonthlySales {
+	months := make([]models.MonthlySales, len(revenue))
+	for i, amount := range revenue {
+		months[i] = models.MonthlySales{Period: fmt.Sprintf("2024-%02d", i+1), Revenue: money(amount)}
+	}
+	return months
+}
+
+func TestGetRevenueForecastProjectsTheTrend(t *testing.T) {
+	type row struct {
+		period   string
+		revenue  string
+		forecast bool
+	}
+	tests := []struct {
+		name           string
+		monthly        []models.MonthlySales
+		months, window int
+		want           []row
+	}{
+		{
+			name:    "linear input continues exactly",
+			monthly: monthlySeries("100", "110", "120", "130", "140", "150"),
+			months:  3,
+			window:  6,
+			want: []row{
+				{"2024-01", "100", false}, {"2024-02", "110", false}, {"2024-03", "120", false},
+				{"2024-04", "130", false}, {"2024-05", "140", false}, {"2024-06", "150", false},
+				{"2024-07", "160", true}, {"2024-08", "170", true}, {"2024-09", "180", true},
+			},
+		},
+		{
+			name:    "only the last window months are fitted",
+			monthly: monthlySeries("1", "1", "1", "100", "200", "300"),
+			months:  2,
+			window:  3,
+			want: []row{
+				{"2024-04", "100", false}, {"2024-05", "200", false}, {"2024-06", "300", false},
+				{"2024-07", "400", true}, {"2024-08", "500", true},
+			},
+		},
+		{
+			name: "months without sales count as zero",
+			monthly: []models.MonthlySales{
+				{Period: "2024-01", Revenue: money("10")},
+				{Period: "2024-03", Revenue: money("30")},
+			},
+			months: 1,
+			window: 3,
+			// y = 10, 0, 30 fits 10x + 3.333..., so the fourth month is 33.33
+			want: []row{{"2024-01", "10", false}, {"2024-02", "0", false}, {"2024-03", "30", false}, {"2024-04", "33.33", true}},
+		},
+		{
+			name:    "projections below zero are clamped",
+			monthly: monthlySeries("300", "200", "100"),
+			months:  2,
+			window:  3,
+			want:    []row{{"2024-01", "300", false}, {"2024-02", "200", false}, {"2024-03", "100", false}, {"2024-04", "0", true}, {"2024-05", "0", true}},
+		},
+		{
+			name:    "the window is at least two months",
+			monthly: monthlySeries("5", "10"),
+			months:  1,
+			window:  1,
+			want:    []row{{"2024-01", "5", false}, {"2024-02", "10", false}, {"2024-03", "15", true}},
+		},
+		{
+			name:    "projections cross the year",
+			monthly: []models.MonthlySales{{Period: "2024-11", Revenue: money("50")}, {Period: "2024-12", Revenue: money("60")}},
+			months:  2,
+			window:  2,
+			want:    []row{{"2024-11", "50", false}, {"2024-12", "60", false}, {"2025-01", "70", true}, {"2025-02", "80", true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(&stubRepository{monthly: tt.monthly})
+			rows, _, err := service.GetRevenueForecast(ctx, models.SalesFilter{}, tt.months, tt.window, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetRevenueForecast: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, want := range tt.want {
+				got := rows[i]
+				if got.Period != want.period || got.Forecast != want.forecast {
+					t.Errorf("row %d = %s forecast %v, want %s forecast %v", i, got.Period, got.Forecast, want.period, want.forecast)
+				}
+				assertMoney(t, got.Period, got.Revenue, want.revenue)
+			}
+		})
+	}
+}
+
+func TestGetRevenueForecastNeedsEnoughHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		monthly []models.MonthlySales
+		window  int
+	}{
+		{name: "no sales", window: 3},
+		{name: "a single month", monthly: monthlySeries("100"), window: 2},
+		{name: "fewer months than the window", monthly: monthlySeries("100", "110", "120"), window: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(&stubRepository{monthly: tt.monthly})
+			if _, _, err := service.GetRevenueForecast(ctx, models.SalesFilter{}, 3, tt.window, models.Conversion{}); !errors.Is(err, ErrInsufficientHistory) {
+				t.Errorf("err = %v, want ErrInsufficientHistory", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// GetRevenueGrowth returns the month-over-month revenue growth of the sales matching
+// the filter in chronological order. Months without sales between the first and
+// last month with sales are filled in with zero revenue rather than skipped, so
+// every growth figure compares consecutive calendar months.
+func (s *AnalyticsService) GetRevenueGrowth(ctx context.Context, filter models.SalesFilter, conversion models.Conversion) ([]models.RevenueGrowth, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.RevenueGrowth
+	key := fmt.Sprintf("growth:%s:%s:%s:%s", filter.Product, filter.Category, dateRangeKey(filter.DateRange), filter.TimeZone())
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		monthly, err := s.repo.GetMonthlySales(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return monthOverMonthGrowth(monthly)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+	}
+	if data == nil {
+		data = []models.RevenueGrowth{}
+	}
+	return data, hit, nil
+}
+
+// monthOverMonthGrowth zero-fills the gaps in chronologically ordered YYYY-MM rows and
+// computes each month's percent change from the previous one, rounded to two decimals.
+// The change is left nil for the first month and wherever the previous month had no
+// revenue, since growth from zero is undefined.
+func monthOverMonthGrowth(monthly []models.MonthlySales) ([]models.RevenueGrowth, error) {
+	months, err := fillMonths(monthly)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []models.RevenueGrowth
+	for _, month := range months {
+		row := models.RevenueGrowth{Period: month.Period, Revenue: month.Revenue}
+		if len(rows) > 0 {
+			if previous := rows[len(rows)-1].Revenue; !previous.IsZero() {
+				growth := percentOf(row.Revenue.Sub(previous.Decimal), previous.Decimal)
+				row.GrowthPct = &growth
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// fillMonths returns chronologically ordered YYYY-MM rows with every calendar month
+// between the first and the last present, months missing from monthly carrying zero
+// revenue
+func fillMonths(monthly []models.MonthlySales) ([]models.MonthlySales, error) {
+	if len(monthly) == 0 {
+		return nil, nil
+	}
+
+	revenue := make(map[string]models.Money, len(monthly))
+	for _, row := range monthly {
+		revenue[row.Period] = row.Revenue
+	}
+
+	first, err := time.Parse("2006-01", monthly[0].Period)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected month label %q: %w", monthly[0].Period, err)
+	}
+	last, err := time.Parse("2006-01", monthly[len(monthly)-1].Period)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected month label %q: %w", monthly[len(monthly)-1].Period, err)
+	}
+
+	var months []models.MonthlySales
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		months = append(months, models.MonthlySales{Period: month.Format("2006-01"), Revenue: revenue[month.Format("2006-01")]})
+	}
+	return months, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetRevenueGrowth(t *testing.T) {
+	repo := &stubRepository{
+		currencies: []string{"USD"},
+		monthly: []models.MonthlySales{
+			{Period: "2023-12", Revenue: money("80")},
+			{Period: "2024-01", Revenue: money("100")},
+			{Period: "2024-02", Revenue: money("150")},
+			// March has no sales
+			{Period: "2024-04", Revenue: money("75")},
+			{Period: "2024-05", Revenue: money("60")},
+			{Period: "2024-06", Revenue: money("80")},
+		},
+	}
+	data, _, err := newTestService(repo).GetRevenueGrowth(ctx, models.SalesFilter{}, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetRevenueGrowth: %v", err)
+	}
+
+	growth := func(pct float64) *float64 { return &pct }
+	want := []struct {
+		period, revenue string
+		growth          *float64
+	}{
+		{"2023-12", "80", nil},
+		{"2024-01", "100", growth(25)},
+		{"2024-02", "150", growth(50)},
+		{"2024-03", "0", growth(-100)},
+		// growth from a month without revenue is undefined
+		{"2024-04", "75", nil},
+		{"2024-05", "60", growth(-20)},
+		{"2024-06", "80", growth(33.33)},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("got %+v, want %d months", data, len(want))
+	}
+	for i, w := range want {
+		row := data[i]
+		if row.Period != w.period {
+			t.Fatalf("row %d period = %s, want %s", i, row.Period, w.period)
+		}
+		assertMoney(t, w.period+" revenue", row.Revenue, w.revenue)
+		switch {
+		case w.growth == nil && row.GrowthPct != nil:
+			t.Errorf("%s growth = %v, want none", w.period, *row.GrowthPct)
+		case w.growth != nil && row.GrowthPct == nil:
+			t.Errorf("%s growth is missing, want %v", w.period, *w.growth)
+		case w.growth != nil && *row.GrowthPct != *w.growth:
+			t.Errorf("%s growth = %v, want %v", w.period, *row.GrowthPct, *w.growth)
+		}
+	}
+
+	empty, _, err := newTestService(&stubRepository{currencies: []string{"USD"}}).GetRevenueGrowth(ctx, models.SalesFilter{}, models.Conversion{})
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("no sales = %v, %v; want an empty list", empty, err)
+	}
+}
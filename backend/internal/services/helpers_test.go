@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/config"
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+)
+
+var ctx = context.Background()
+
+// stubRepository serves canned results and counts the calls made to it. The
+// embedded interface is left nil, so a method a test did not expect panics.
+type stubRepository struct {
+	AnalyticsRepository
+
+	mu    sync.Mutex
+	calls map[string]int
+	// onCall, when set, runs at the start of every call, outside the lock
+	onCall func(method string)
+	// errs holds the error each method returns instead of its canned result
+	errs map[string]error
+
+	currencies     []string
+	countryRevenue []models.CountryRevenue
+	products       []models.ProductRevenue
+	monthly        []models.MonthlySales
+	regions        []models.RegionRevenue
+}
+
+// record counts a call to method and returns the error set for it
+func (r *stubRepository) record(method string) error {
+	if r.onCall != nil {
+		r.onCall(method)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.calls == nil {
+		r.calls = make(map[string]int)
+	}
+	r.calls[method]++
+	return r.errs[method]
+}
+
+// callCount returns how often method was called
+func (r *stubRepository) callCount(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+func (r *stubRepository) GetCurrencies(context.Context) ([]string, error) {
+	if err := r.record("GetCurrencies"); err != nil {
+		return nil, err
+	}
+	return r.currencies, nil
+}
+
+func (r *stubRepository) GetCountryRevenue(context.Context, models.CountryFilter) ([]models.CountryRevenue, error) {
+	if err := r.record("GetCountryRevenue"); err != nil {
+		return nil, err
+	}
+	return append([]models.CountryRevenue(nil), r.countryRevenue...), nil
+}
+
+func (r *stubRepository) GetTopProducts(_ context.Context, _ models.ProductFilter, _ models.ProductSort, limit, offset int) ([]models.ProductRevenue, int64, error) {
+	if err := r.record("GetTopProducts"); err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(r.products))
+	if offset > len(r.products) {
+		offset = len(r.products)
+	}
+	end := offset + limit
+	if end > len(r.products) {
+		end = len(r.products)
+	}
+	return append([]models.ProductRevenue(nil), r.products[offset:end]...), total, nil
+}
+
+func (r *stubRepository) GetRankedProducts(context.Context, models.DateRange) ([]models.ProductRevenue, error) {
+	if err := r.record("GetRankedProducts"); err != nil {
+		return nil, err
+	}
+	return append([]models.ProductRevenue(nil), r.products...), nil
+}
+
+func (r *stubRepository) GetMonthlySales(context.Context, models.SalesFilter) ([]models.MonthlySales, error) {
+	if err := r.record("GetMonthlySales"); err != nil {
+		return nil, err
+	}
+	return append([]models.MonthlySales(nil), r.monthly...), nil
+}
+
+func (r *stubRepository) GetTopRegions(_ context.Context, _ models.RegionFilter, n int) ([]models.RegionRevenue, error) {
+	if err := r.record("GetTopRegions"); err != nil {
+		return nil, err
+	}
+	if n > len(r.regions) {
+		n = len(r.regions)
+	}
+	return append([]models.RegionRevenue(nil), r.regions[:n]...), nil
+}
+
+// testConfig returns the settings the service reads, with caching for a minute
+func testConfig() *config.Config {
+	return &config.Config{
+		CacheTTL:     time.Minute,
+		Rates:        map[string]float64{"USD": 1, "EUR": 0.5},
+		BaseCurrency: "USD",
+	}
+}
+
+// newTestService returns a service over repo caching in memory with testConfig
+func newTestService(repo AnalyticsRepository) *AnalyticsService {
+	return NewAnalyticsService(repo, cache.NewMemoryCache(), testConfig())
+}
+
+func money(amount string) models.Money {
+	return models.NewMoney(decimal.RequireFromString(amount))
+}
+
+func assertMoney(t *testing.T, name string, got models.Money, want string) {
+	t.Helper()
+	if !got.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s = %s, want %s", name, got.Format(), want)
+	}
+}
+
+// newTestDB opens a migrated in-memory SQLite database on a single connection,
+// since every connection to :memory: opens a database of its own
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("access pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
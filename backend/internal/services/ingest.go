@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"abt-analytics/internal/models"
+)
+
+// BatchValidationError lists every invalid row of a submitted batch
+type BatchValidationError struct {
+	Rows []models.RowError
+}
+
+func (e *BatchValidationError) Error() string {
+	return fmt.Sprintf("batch failed validation with %d errors", len(e.Rows))
+}
+
+// DeleteTransaction voids the transaction with the given ID by soft-deleting it,
+// returning models.ErrTransactionNotFound when there is no such live transaction.
+// The whole cache is flushed after the delete, so aggregates drop the row at once.
+func (s *AnalyticsService) DeleteTransaction(ctx context.Context, id uint) error {
+	if err := s.repo.DeleteTransaction(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// IngestTransactions validates every input and stores the batch atomically.
+// Invalid rows are reported together as a *BatchValidationError and nothing is
+// stored. Once the batch is stored the whole cache is flushed, so aggregates
+// include the new rows on the next request.
+func (s *AnalyticsService) IngestTransactions(ctx context.Context, inputs []models.TransactionInput) (int, error) {
+	if rows := s.validateInputs(inputs); len(rows) > 0 {
+		return 0, &BatchValidationError{Rows: rows}
+	}
+
+	transactions := make([]models.Transaction, len(inputs))
+	for i, input := range inputs {
+		quantity := input.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		transactions[i] = models.Transaction{
+			OrderID:         input.OrderID,
+			CustomerID:      input.CustomerID,
+			TransactionDate: input.TransactionDate.UTC(),
+			Country:         input.Country,
+			Region:          input.Region,
+			Product:         input.Product,
+			Category:        input.Category,
+			Quantity:        quantity,
+			Revenue:         input.Revenue,
+			Currency:        strings.ToUpper(input.Currency),
+		}
+	}
+
+	if err := s.repo.InsertTransactions(ctx, transactions); err != nil {
+		return 0, err
+	}
+	s.invalidateCache(ctx)
+	return len(transactions), nil
+}
+
+// invalidateCache flushes every cached result after a write. The write has
+// already succeeded, so a failing cache is logged rather than returned.
+func (s *AnalyticsService) invalidateCache(ctx context.Context) {
+	if _, err := s.FlushCache(ctx, ""); err != nil {
+		log.Printf("Warning: cache flush after write failed: %v", err)
+	}
+}
+
+func (s *AnalyticsService) validateInputs(inputs []models.TransactionInput) []models.RowError {
+	var rows []models.RowError
+	reject := func(index int, field, message string) {
+		rows = append(rows, models.RowError{Index: index, Field: field, Message: message})
+	}
+
+	orderIDs := make(map[string]int, len(inputs))
+	for i, input := range inputs {
+		if input.TransactionDate.IsZero() {
+			reject(i, "transaction_date", "is required")
+		}
+		if input.Country == "" {
+			reject(i, "country", "is required")
+		}
+		if input.Region == "" {
+			reject(i, "region", "is required")
+		}
+		if input.Product == "" {
+			reject(i, "product", "is required")
+		}
+		if !input.Revenue.IsPositive() {
+			reject(i, "revenue", "must be positive")
+		} else if !input.Revenue.Equal(input.Revenue.Round(2)) {
+			reject(i, "revenue", "must not have more than two decimal places")
+		}
+		if input.Quantity < 0 {
+			reject(i, "quantity", "must not be negative")
+		}
+		if currency := strings.ToUpper(input.Currency); currency != "" && currency != s.baseCurrency {
+			if _, ok := s.rates[currency]; !ok {
+				reject(i, "currency", fmt.Sprintf("%q is not supported: supported currencies are %s", input.Currency, s.supportedCurrencies()))
+			}
+		}
+
+		if input.OrderID == "" {
+			continue
+		}
+		if first, ok := orderIDs[input.OrderID]; ok {
+			reject(i, "order_id", fmt.Sprintf("duplicates row %d", first))
+		} else {
+			orderIDs[input.OrderID] = i
+		}
+	}
+	return rows
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestIngestTransactionsRefreshesCachedMeta(t *testing.T) {
+	service := newTestService(repository.NewAnalyticsRepository(newTestDB(t), nil))
+
+	before, _, err := service.GetDataMeta(ctx)
+	if err != nil {
+		t.Fatalf("GetDataMeta: %v", err)
+	}
+	if before.TotalTransactions != 0 {
+		t.Fatalf("empty database reports %d transactions", before.TotalTransactions)
+	}
+
+	last := time.Date(2024, time.May, 2, 9, 30, 0, 0, time.UTC)
+	inputs := []models.TransactionInput{
+		{TransactionDate: last.AddDate(0, 0, -1), Country: "US", Region: "Texas", Product: "Widget", Revenue: money("10")},
+		{TransactionDate: last, Country: "DE", Region: "Bavaria", Product: "Gadget", Revenue: money("20")},
+	}
+	if _, err := service.IngestTransactions(ctx, inputs); err != nil {
+		t.Fatalf("IngestTransactions: %v", err)
+	}
+
+	after, hit, err := service.GetDataMeta(ctx)
+	if err != nil {
+		t.Fatalf("GetDataMeta after ingest: %v", err)
+	}
+	if hit {
+		t.Error("meta was served from the cache filled before the ingest")
+	}
+	if after.TotalTransactions != 2 || after.LastTransactionDate == nil || !after.LastTransactionDate.Equal(last) {
+		t.Errorf("meta = %d transactions up to %v, want 2 up to %s", after.TotalTransactions, after.LastTransactionDate, last)
+	}
+}
+
+func TestIngestTransactionsStoresNothingForAnInvalidBatch(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	inputs := []models.TransactionInput{
+		{TransactionDate: time.Now(), Country: "US", Region: "Texas", Product: "Widget", Revenue: money("10")},
+		{TransactionDate: time.Now(), Country: "US", Region: "Texas", Product: "Widget", Revenue: money("0")},
+	}
+	_, err := service.IngestTransactions(ctx, inputs)
+
+	var validationErr *BatchValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want a *BatchValidationError", err)
+	}
+	var stored int64
+	if err := db.Model(&models.Transaction{}).Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 0 {
+		t.Errorf("%d rows stored from a batch with an invalid row", stored)
+	}
+}
+
+func TestDeleteTransactionDropsOutOfCachedTotals(t *testing.T) {
+	db := newTestDB(t)
+	at := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	rows := []models.Transaction{
+		{OrderID: "keep", TransactionDate: at, Country: "US", Region: "Texas", Product: "Widget", Quantity: 1, Revenue: money("100")},
+		{OrderID: "void", TransactionDate: at, Country: "US", Region: "Texas", Product: "Widget", Quantity: 1, Revenue: money("40")},
+		{OrderID: "only", TransactionDate: at, Country: "DE", Region: "Bavaria", Product: "Widget", Quantity: 1, Revenue: money("60")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	revenue := func() map[string]models.CountryRevenue {
+		t.Helper()
+		data, _, err := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+		if err != nil {
+			t.Fatalf("GetCountryRevenue: %v", err)
+		}
+		byCountry := make(map[string]models.CountryRevenue, len(data))
+		for _, row := range data {
+			byCountry[row.Country] = row
+		}
+		return byCountry
+	}
+
+	// the first call fills the cache the delete has to flush
+	before := revenue()
+	assertMoney(t, "US revenue before", before["US"].Revenue, "140")
+
+	for _, row := range rows[1:] {
+		if err := service.DeleteTransaction(ctx, row.ID); err != nil {
+			t.Fatalf("DeleteTransaction(%s): %v", row.OrderID, err)
+		}
+	}
+
+	after := revenue()
+	if us := after["US"]; us.Orders != 1 {
+		t.Errorf("US orders = %d, want 1 after the delete", us.Orders)
+	}
+	assertMoney(t, "US revenue after", after["US"].Revenue, "100")
+	if _, ok := after["DE"]; ok {
+		t.Error("Germany still listed after its only sale was deleted")
+	}
+
+	var stored int64
+	if err := db.Unscoped().Model(&models.Transaction{}).Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 3 {
+		t.Errorf("%d rows stored, want all 3 kept for auditing", stored)
+	}
+
+	if err := service.DeleteTransaction(ctx, rows[1].ID); !errors.Is(err, models.ErrTransactionNotFound) {
+		t.Errorf("deleting twice: err = %v, want ErrTransactionNotFound", err)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestRevenueSumsAreExact(t *testing.T) {
+	// 0.1 has no exact float representation: adding it a thousand times in
+	// float64 gives 99.9999999999986, not 100
+	floatSum := 0.0
+	for i := 0; i < 1000; i++ {
+		floatSum += 0.1
+	}
+	if floatSum == 100 {
+		t.Fatal("the float sum came out exact, so this test proves nothing")
+	}
+
+	db := newTestDB(t)
+	rows := make([]models.Transaction, 1000)
+	for i := range rows {
+		rows[i] = models.Transaction{
+			TransactionDate: time.Date(2024, time.January, 1+i%28, 12, 0, 0, 0, time.UTC),
+			Country:         "US",
+			Region:          "Texas",
+			Product:         "Sticker",
+			Quantity:        1,
+			Revenue:         money("0.10"),
+		}
+	}
+	if err := db.CreateInBatches(rows, 100).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	data, _, err := service.GetCountryRevenue(ctx, models.CountryFilter{}, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetCountryRevenue: %v", err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("got %+v, want one country", data)
+	}
+	if !data[0].Revenue.Equal(money("100").Decimal) {
+		t.Errorf("revenue = %s, want exactly 100", data[0].Revenue.String())
+	}
+
+	encoded, err := json.Marshal(data[0].Revenue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `"100.00"` {
+		t.Errorf("revenue encodes as %s, want \"100.00\"", encoded)
+	}
+}
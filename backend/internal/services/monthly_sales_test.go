@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetMonthlySalesProductAndCategoryFilters(t *testing.T) {
+	db := newTestDB(t)
+	sale := func(month time.Month, product, category, revenue string) models.Transaction {
+		return models.Transaction{
+			TransactionDate: time.Date(2024, month, 10, 12, 0, 0, 0, time.UTC),
+			Country:         "US",
+			Region:          "Texas",
+			Product:         product,
+			Category:        category,
+			Quantity:        1,
+			Revenue:         money(revenue),
+		}
+	}
+	rows := []models.Transaction{
+		sale(time.January, "Laptop", "Electronics", "1000"),
+		sale(time.January, "Phone", "Electronics", "500"),
+		sale(time.February, "Laptop", "Electronics", "1200"),
+		sale(time.February, "Desk", "Furniture", "300"),
+		sale(time.March, "Laptop", "Clearance", "400"),
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	type month struct{ period, revenue string }
+	tests := []struct {
+		name   string
+		filter models.SalesFilter
+		want   []month
+	}{
+		{name: "unfiltered", want: []month{{"2024-01", "1500"}, {"2024-02", "1500"}, {"2024-03", "400"}}},
+		{
+			name:   "product only",
+			filter: models.SalesFilter{Product: "laptop"},
+			want:   []month{{"2024-01", "1000"}, {"2024-02", "1200"}, {"2024-03", "400"}},
+		},
+		{
+			name:   "category only",
+			filter: models.SalesFilter{Category: "Electronics"},
+			want:   []month{{"2024-01", "1500"}, {"2024-02", "1200"}},
+		},
+		{
+			name:   "product and category",
+			filter: models.SalesFilter{Product: "Laptop", Category: "Clearance"},
+			want:   []month{{"2024-03", "400"}},
+		},
+		{name: "no match", filter: models.SalesFilter{Product: "Desk", Category: "Electronics"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := service.GetMonthlySales(ctx, tt.filter, GranularityMonth, false, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetMonthlySales: %v", err)
+			}
+			if len(data) != len(tt.want) {
+				t.Fatalf("got %+v, want %v", data, tt.want)
+			}
+			for i, w := range tt.want {
+				if data[i].Period != w.period {
+					t.Errorf("row %d period = %s, want %s", i, data[i].Period, w.period)
+				}
+				assertMoney(t, w.period+" revenue", data[i].Revenue, w.revenue)
+			}
+			if encoded, _ := json.Marshal(data); len(tt.want) == 0 && string(encoded) != "[]" {
+				t.Errorf("no match encodes as %s, want []", encoded)
+			}
+		})
+	}
+}
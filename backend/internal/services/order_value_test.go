@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetAverageOrderValue(t *testing.T) {
+	db := newTestDB(t)
+	at := func(date string) time.Time {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.Add(12 * time.Hour)
+	}
+	rows := []models.Transaction{
+		{OrderID: "1", Country: "US", TransactionDate: at("2024-01-02"), Quantity: 1, Revenue: money("10")},
+		{OrderID: "2", Country: "US", TransactionDate: at("2024-01-03"), Quantity: 1, Revenue: money("20")},
+		{OrderID: "3", Country: "US", TransactionDate: at("2024-01-04"), Quantity: 1, Revenue: money("5.01")},
+		{OrderID: "4", Country: "DE", TransactionDate: at("2024-01-05"), Quantity: 2, Revenue: money("100")},
+		{OrderID: "5", Country: "DE", TransactionDate: at("2024-01-06"), Quantity: 1, Revenue: money("50")},
+		{OrderID: "6", Country: "FR", TransactionDate: at("2024-03-01"), Quantity: 1, Revenue: money("999")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	from, to := at("2024-01-01"), at("2024-01-31")
+	data, _, err := service.GetAverageOrderValue(ctx, models.DateRange{From: &from, To: &to}, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetAverageOrderValue: %v", err)
+	}
+
+	// France has no orders in January, so it is left out rather than divided by zero
+	if len(data) != 2 {
+		t.Fatalf("got %+v, want Germany and the United States", data)
+	}
+	want := []struct {
+		country string
+		average string
+		orders  int64
+	}{
+		{"DE", "75", 2},
+		// 35.01 over 3 orders is 11.67 once rounded to cents
+		{"US", "11.67", 3},
+	}
+	for i, w := range want {
+		if data[i].Country != w.country || data[i].Orders != w.orders {
+			t.Errorf("row %d = %s with %d orders, want %s with %d", i, data[i].Country, data[i].Orders, w.country, w.orders)
+		}
+		assertMoney(t, w.country+" average", data[i].AverageOrderValue, w.average)
+	}
+
+	empty, _, err := service.GetAverageOrderValue(ctx, models.DateRange{From: &to, To: &to}, models.Conversion{})
+	if err != nil || len(empty) != 0 {
+		t.Errorf("empty window = %+v, %v; want no rows", empty, err)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
+
+	"abt-analytics/internal/models"
+)
+
+// Metrics two periods can be compared on
+const (
+	MetricRevenue = "revenue"
+	MetricOrders  = "orders"
+	MetricAOV     = "aov"
+)
+
+// ComparePeriods compares metric over the date ranges a and b, loading the totals
+// of both concurrently. The boolean reports whether the cache served both.
+func (s *AnalyticsService) ComparePeriods(ctx context.Context, a, b models.DateRange, metric string, conversion models.Conversion) (*models.PeriodComparison, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		totalsA, totalsB models.PeriodTotals
+		hitA, hitB       bool
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		hitA, err = s.periodTotals(gctx, a, &totalsA)
+		return err
+	})
+	g.Go(func() (err error) {
+		hitB, err = s.periodTotals(gctx, b, &totalsB)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, hitA && hitB, err
+	}
+
+	comparison := &models.PeriodComparison{
+		Metric: metric,
+		A:      models.PeriodValue{From: a.From, To: a.To},
+		B:      models.PeriodValue{From: b.From, To: b.To},
+	}
+	if metric != MetricOrders {
+		comparison.Currency = conversion.Currency
+	}
+
+	valueA, okA := metricValue(totalsA, metric, rate)
+	valueB, okB := metricValue(totalsB, metric, rate)
+	if okA {
+		comparison.A.Value = formatMetric(valueA, metric)
+	}
+	if okB {
+		comparison.B.Value = formatMetric(valueB, metric)
+	}
+	if okA && okB {
+		difference := valueB.Sub(valueA)
+		comparison.Difference = formatMetric(difference, metric)
+		if !valueA.IsZero() {
+			change := percentOf(difference, valueA)
+			comparison.PercentChange = &change
+		}
+	}
+	return comparison, hitA && hitB, nil
+}
+
+// periodTotals loads the cached totals of one date range into dest
+func (s *AnalyticsService) periodTotals(ctx context.Context, dateRange models.DateRange, dest *models.PeriodTotals) (bool, error) {
+	var data *models.PeriodTotals
+	hit, err := s.cached(ctx, "compare:"+dateRangeKey(dateRange), &data, func() (interface{}, error) {
+		return s.repo.GetPeriodTotals(ctx, dateRange)
+	})
+	if err == nil && data != nil {
+		*dest = *data
+	}
+	return hit, err
+}
+
+// metricValue returns metric over totals, with revenue converted at rate and
+// rounded to two decimals. It reports false for the average order value of a
+// period without orders.
+func metricValue(totals models.PeriodTotals, metric string, rate decimal.Decimal) (decimal.Decimal, bool) {
+	switch metric {
+	case MetricOrders:
+		return decimal.NewFromInt(totals.Orders), true
+	case MetricAOV:
+		if totals.Orders == 0 {
+			return decimal.Decimal{}, false
+		}
+		return totals.Revenue.Mul(rate).Div(decimal.NewFromInt(totals.Orders)).Round(2), true
+	default:
+		return convert(totals.Revenue, rate).Decimal, true
+	}
+}
+
+// formatMetric renders an order count as a whole number and amounts with two decimals
+func formatMetric(value decimal.Decimal, metric string) *string {
+	places := int32(2)
+	if metric == MetricOrders {
+		places = 0
+	}
+	formatted := value.StringFixed(places)
+	return &formatted
+}
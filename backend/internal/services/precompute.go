@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// refreshKey marks contexts under which cached lookups reload their value
+type refreshKey struct{}
+
+// refreshing returns a context under which every cached lookup recomputes its
+// value and overwrites the cache entry
+func refreshing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func isRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+// Precompute recomputes the heaviest aggregates as the dashboard requests them
+// by default, right away and then every interval, writing them to the cache so
+// those requests are served warm. productLimit and regionCount size the summary
+// as in GetSummary. It returns once ctx is done; without a cache it returns at once.
+func (s *AnalyticsService) Precompute(ctx context.Context, interval time.Duration, productLimit, regionCount int) {
+	if s.cache == nil || s.cacheTTL <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.precompute(refreshing(ctx), productLimit, regionCount)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// precompute runs one round of Precompute, logging the aggregates that failed.
+// Date-ranged aggregates are warmed for the window the controllers give requests
+// without bounds, so their cache keys match.
+func (s *AnalyticsService) precompute(ctx context.Context, productLimit, regionCount int) {
+	conversion := models.Conversion{}
+	dateRange := s.defaultRange(time.Now())
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"summary", func() error {
+			_, _, err := s.GetSummary(ctx, dateRange, productLimit, regionCount, conversion)
+			return err
+		}},
+		{"category revenue", func() error {
+			_, _, err := s.GetCategoryRevenue(ctx, dateRange, conversion)
+			return err
+		}},
+		{"average order value", func() error {
+			_, _, err := s.GetAverageOrderValue(ctx, dateRange, conversion)
+			return err
+		}},
+		{"dimensions", func() error {
+			_, _, err := s.GetDimensions(ctx)
+			return err
+		}},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: precomputing %s failed: %v", step.name, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/cache"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+// signalingCache reports the key of every entry written to it on sets
+type signalingCache struct {
+	cache.Cache
+	sets chan string
+}
+
+func (c *signalingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := c.Cache.Set(ctx, key, value, ttl)
+	c.sets <- key
+	return err
+}
+
+func TestPrecomputeWarmsTheCacheOnItsFirstTick(t *testing.T) {
+	db := newTestDB(t)
+	rows := []models.Transaction{
+		{TransactionDate: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC), Country: "US", Region: "Texas", Product: "Widget", Category: "Tools", Quantity: 1, Revenue: money("10")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	appCache := &signalingCache{Cache: cache.NewMemoryCache(), sets: make(chan string, 100)}
+	service := NewAnalyticsService(repository.NewAnalyticsRepository(db, nil), appCache, testConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		// An interval far past the test: only the first round can run
+		service.Precompute(ctx, time.Hour, 5, 3)
+		close(done)
+	}()
+
+	// Dimensions are the last aggregate of a round
+	timeout := time.After(5 * time.Second)
+	for warmed := false; !warmed; {
+		select {
+		case key := <-appCache.sets:
+			warmed = key == "dimensions:"
+		case <-timeout:
+			t.Fatal("the first round did not finish")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Precompute did not stop once its context was canceled")
+	}
+
+	checks := []struct {
+		name string
+		hit  func() (bool, error)
+	}{
+		{"summary", func() (bool, error) {
+			_, hit, err := service.GetSummary(context.Background(), models.DateRange{}, 5, 3, models.Conversion{})
+			return hit, err
+		}},
+		{"category revenue", func() (bool, error) {
+			_, hit, err := service.GetCategoryRevenue(context.Background(), models.DateRange{}, models.Conversion{})
+			return hit, err
+		}},
+		{"average order value", func() (bool, error) {
+			_, hit, err := service.GetAverageOrderValue(context.Background(), models.DateRange{}, models.Conversion{})
+			return hit, err
+		}},
+		{"dimensions", func() (bool, error) {
+			_, hit, err := service.GetDimensions(context.Background())
+			return hit, err
+		}},
+	}
+	for _, check := range checks {
+		if hit, err := check.hit(); err != nil || !hit {
+			t.Errorf("%s: hit %v, %v; want it served warm", check.name, hit, err)
+		}
+	}
+}
+
+func TestPrecomputeWarmsTheDefaultRange(t *testing.T) {
+	db := newTestDB(t)
+	rows := []models.Transaction{
+		{TransactionDate: time.Now().UTC(), Country: "US", Region: "Texas", Product: "Widget", Category: "Tools", Quantity: 1, Revenue: money("10")},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	cfg := testConfig()
+	cfg.DefaultRangeDays = 30
+	service := NewAnalyticsService(repository.NewAnalyticsRepository(db, nil), cache.NewMemoryCache(), cfg)
+
+	service.precompute(refreshing(context.Background()), 5, 3)
+
+	// the window a request without bounds gets from the controllers
+	window := models.TrailingDays(cfg.DefaultRangeDays, time.Now())
+	if _, hit, err := service.GetSummary(context.Background(), window, 5, 3, models.Conversion{}); err != nil || !hit {
+		t.Errorf("summary over the default window: hit %v, %v; want it served warm", hit, err)
+	}
+	if _, hit, err := service.GetCategoryRevenue(context.Background(), window, models.Conversion{}); err != nil || !hit {
+		t.Errorf("category revenue over the default window: hit %v, %v; want it served warm", hit, err)
+	}
+	if _, hit, err := service.GetAverageOrderValue(context.Background(), window, models.Conversion{}); err != nil || !hit {
+		t.Errorf("average order value over the default window: hit %v, %v; want it served warm", hit, err)
+	}
+	// full history is only asked for with all=true and is left cold
+	if _, hit, err := service.GetCategoryRevenue(context.Background(), models.DateRange{}, models.Conversion{}); err != nil || hit {
+		t.Errorf("category revenue over all history: hit %v, %v; want a miss", hit, err)
+	}
+}
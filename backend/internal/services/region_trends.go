@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// monthLayout labels the months of the region trend series
+const monthLayout = "2006-01"
+
+// GetRegionTrends returns the monthly revenue series of the n regions with the
+// highest revenue inside the date range, highest first. Every series spans the
+// months of the range, or of the data where a bound is open, without gaps.
+func (s *AnalyticsService) GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int, conversion models.Conversion) ([]models.RegionTrend, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data []models.RegionTrend
+	key := fmt.Sprintf("region-trends:%s:%d", dateRangeKey(dateRange), n)
+	hit, err := s.cached(ctx, key, &data, func() (interface{}, error) {
+		rows, err := s.repo.GetRegionTrends(ctx, dateRange, n)
+		if err != nil {
+			return nil, err
+		}
+		return regionTrends(rows, dateRange)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+	for i := range data {
+		data[i].Revenue = convert(data[i].Revenue, rate)
+		data[i].Currency = conversion.Currency
+		for j := range data[i].Series {
+			data[i].Series[j].Revenue = convert(data[i].Series[j].Revenue, rate)
+		}
+	}
+	return data, hit, nil
+}
+
+// regionTrends groups YYYY-MM rows ordered by month into one series per region,
+// ranked by total revenue with ties broken by region name. The series share one
+// axis of consecutive months: from the month of dateRange.From, or the first month
+// with data, to the month of dateRange.To, or the last month with data.
+func regionTrends(rows []models.RegionMonthRevenue, dateRange models.DateRange) ([]models.RegionTrend, error) {
+	trends := []models.RegionTrend{}
+	if len(rows) == 0 {
+		return trends, nil
+	}
+
+	first, err := time.Parse(monthLayout, rows[0].Period)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected month label %q: %w", rows[0].Period, err)
+	}
+	last, err := time.Parse(monthLayout, rows[len(rows)-1].Period)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected month label %q: %w", rows[len(rows)-1].Period, err)
+	}
+	if from := dateRange.From; from != nil {
+		first = time.Date(from.UTC().Year(), from.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to := dateRange.To; to != nil {
+		last = time.Date(to.UTC().Year(), to.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	revenue := make(map[string]map[string]models.Money)
+	for _, row := range rows {
+		months, ok := revenue[row.Region]
+		if !ok {
+			months = make(map[string]models.Money)
+			revenue[row.Region] = months
+			trends = append(trends, models.RegionTrend{Region: row.Region})
+		}
+		months[row.Period] = row.Revenue
+	}
+
+	for i := range trends {
+		trend := &trends[i]
+		trend.Series = []models.MonthlySales{}
+		for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+			period := month.Format(monthLayout)
+			amount := revenue[trend.Region][period]
+			trend.Series = append(trend.Series, models.MonthlySales{Period: period, Revenue: amount})
+			trend.Revenue = models.NewMoney(trend.Revenue.Add(amount.Decimal))
+		}
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		if !trends[i].Revenue.Equal(trends[j].Revenue.Decimal) {
+			return trends[i].Revenue.GreaterThan(trends[j].Revenue.Decimal)
+		}
+		return trends[i].Region < trends[j].Region
+	})
+	return trends, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+)
+
+func TestGetRegionTrendsSeriesAreContiguous(t *testing.T) {
+	sold := func(region string, month time.Month, revenue string) models.Transaction {
+		return models.Transaction{
+			TransactionDate: time.Date(2024, month, 15, 12, 0, 0, 0, time.UTC),
+			Country:         "US",
+			Region:          region,
+			Product:         "Widget",
+			Quantity:        1,
+			Revenue:         money(revenue),
+		}
+	}
+	db := newTestDB(t)
+	rows := []models.Transaction{
+		// Texas skips February and March, California sells in March only
+		sold("Texas", time.January, "100"),
+		sold("Texas", time.April, "300"),
+		sold("California", time.March, "250"),
+		sold("Ohio", time.February, "10"),
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(repository.NewAnalyticsRepository(db, nil))
+
+	day := func(month time.Month, d int) *time.Time {
+		date := time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	tests := []struct {
+		name      string
+		dateRange models.DateRange
+		n         int
+		regions   []string
+		months    []string
+	}{
+		{
+			name:    "months of the data",
+			n:       2,
+			regions: []string{"Texas", "California"},
+			months:  []string{"2024-01", "2024-02", "2024-03", "2024-04"},
+		},
+		{
+			name:      "months of the range",
+			dateRange: models.DateRange{From: day(time.February, 1), To: day(time.June, 30)},
+			n:         5,
+			regions:   []string{"Texas", "California", "Ohio"},
+			months:    []string{"2024-02", "2024-03", "2024-04", "2024-05", "2024-06"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends, _, err := service.GetRegionTrends(ctx, tt.dateRange, tt.n, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetRegionTrends: %v", err)
+			}
+			if len(trends) != len(tt.regions) {
+				t.Fatalf("got %d regions, want %v", len(trends), tt.regions)
+			}
+			for i, trend := range trends {
+				if trend.Region != tt.regions[i] {
+					t.Errorf("region %d = %s, want %s", i, trend.Region, tt.regions[i])
+				}
+				if len(trend.Series) != len(tt.months) {
+					t.Errorf("%s series = %+v, want the months %v", trend.Region, trend.Series, tt.months)
+					continue
+				}
+				total := money("0")
+				for j, point := range trend.Series {
+					if point.Period != tt.months[j] {
+						t.Errorf("%s month %d = %s, want %s", trend.Region, j, point.Period, tt.months[j])
+					}
+					total = models.NewMoney(total.Add(point.Revenue.Decimal))
+				}
+				if !total.Equal(trend.Revenue.Decimal) {
+					t.Errorf("%s series sums to %s, want its revenue %s", trend.Region, total.Format(), trend.Revenue.Format())
+				}
+			}
+		})
+	}
+}
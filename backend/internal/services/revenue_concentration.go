@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/models"
+)
+
+// GetRevenueConcentration ranks the products sold within the date range by revenue
+// and reports, for each threshold percentage, how many of the top products it takes
+// for their cumulative revenue to reach that share of the total. Shares are taken
+// before any currency conversion, which scales every product alike.
+func (s *AnalyticsService) GetRevenueConcentration(ctx context.Context, dateRange models.DateRange, thresholds []float64, conversion models.Conversion) (*models.RevenueConcentration, bool, error) {
+	rate, err := s.conversionRate(ctx, conversion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var products []models.ProductRevenue
+	hit, err := s.cached(ctx, "revenue-concentration:"+dateRangeKey(dateRange), &products, func() (interface{}, error) {
+		return s.repo.GetRankedProducts(ctx, dateRange)
+	})
+	if err != nil {
+		return nil, hit, err
+	}
+
+	total := decimal.Zero
+	for _, product := range products {
+		total = total.Add(product.Revenue.Decimal)
+	}
+
+	thresholds = append([]float64(nil), thresholds...)
+	sort.Float64s(thresholds)
+	concentration := &models.RevenueConcentration{
+		TotalRevenue: convert(models.NewMoney(total), rate),
+		Products:     make([]models.ProductShare, len(products)),
+		Thresholds:   make([]models.ConcentrationThreshold, len(thresholds)),
+		Currency:     conversion.Currency,
+	}
+	for i, threshold := range thresholds {
+		concentration.Thresholds[i].Threshold = threshold
+	}
+
+	cumulative := decimal.Zero
+	reached := 0
+	for i, product := range products {
+		cumulative = cumulative.Add(product.Revenue.Decimal)
+		share := &concentration.Products[i]
+		share.Product = product.Product
+		share.Revenue = convert(product.Revenue, rate)
+		if total.IsZero() {
+			continue
+		}
+		share.Share = percentOf(product.Revenue.Decimal, total)
+		share.CumulativeShare = percentOf(cumulative, total)
+
+		// Compared unrounded, as cumulative*100 >= threshold*total, so a share
+		// rounding up to the threshold does not count as reaching it
+		for reached < len(thresholds) &&
+			cumulative.Mul(decimal.NewFromInt(100)).GreaterThanOrEqual(decimal.NewFromFloat(thresholds[reached]).Mul(total)) {
+			concentration.Thresholds[reached].Products = i + 1
+			reached++
+		}
+	}
+	return concentration, hit, nil
+}
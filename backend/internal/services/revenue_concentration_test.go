@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"abt-analytics/internal/models"
+)
+
+func TestGetRevenueConcentrationCountsProductsPerThreshold(t *testing.T) {
+	// ten products making up 100, so cumulative shares are 40, 60, 75, 85, 90, 94, 97, 98, 99, 100
+	var products []models.ProductRevenue
+	for i, revenue := range []string{"40", "20", "15", "10", "5", "4", "3", "1", "1", "1"} {
+		products = append(products, models.ProductRevenue{Product: fmt.Sprintf("P%d", i+1), Revenue: money(revenue)})
+	}
+
+	tests := []struct {
+		name       string
+		thresholds []float64
+		want       []models.ConcentrationThreshold
+	}{
+		{
+			name:       "defaults",
+			thresholds: []float64{50, 80, 95},
+			want:       []models.ConcentrationThreshold{{Threshold: 50, Products: 2}, {Threshold: 80, Products: 4}, {Threshold: 95, Products: 7}},
+		},
+		{
+			name:       "unsorted thresholds are reported in order",
+			thresholds: []float64{95, 50, 80},
+			want:       []models.ConcentrationThreshold{{Threshold: 50, Products: 2}, {Threshold: 80, Products: 4}, {Threshold: 95, Products: 7}},
+		},
+		{
+			name:       "a share landing on the threshold reaches it",
+			thresholds: []float64{40, 75, 100},
+			want:       []models.ConcentrationThreshold{{Threshold: 40, Products: 1}, {Threshold: 75, Products: 3}, {Threshold: 100, Products: 10}},
+		},
+		{
+			name:       "fractional thresholds",
+			thresholds: []float64{74.9, 94.5},
+			want:       []models.ConcentrationThreshold{{Threshold: 74.9, Products: 3}, {Threshold: 94.5, Products: 7}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(&stubRepository{products: products})
+			data, _, err := service.GetRevenueConcentration(ctx, models.DateRange{}, tt.thresholds, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetRevenueConcentration: %v", err)
+			}
+			if fmt.Sprint(data.Thresholds) != fmt.Sprint(tt.want) {
+				t.Errorf("thresholds = %v, want %v", data.Thresholds, tt.want)
+			}
+		})
+	}
+
+	service := newTestService(&stubRepository{products: products})
+	data, _, err := service.GetRevenueConcentration(ctx, models.DateRange{}, []float64{80}, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetRevenueConcentration: %v", err)
+	}
+	assertMoney(t, "total revenue", data.TotalRevenue, "100")
+	cumulative := []float64{40, 60, 75, 85, 90, 94, 97, 98, 99, 100}
+	for i, share := range data.Products {
+		if share.CumulativeShare != cumulative[i] || share.Share != products[i].Revenue.InexactFloat64() {
+			t.Errorf("%s: share %v, cumulative %v; want %v, %v", share.Product, share.Share, share.CumulativeShare, products[i].Revenue, cumulative[i])
+		}
+	}
+}
+
+func TestGetRevenueConcentrationWithoutRevenue(t *testing.T) {
+	tests := []struct {
+		name     string
+		products []models.ProductRevenue
+	}{
+		{name: "no products"},
+		{name: "only zero revenue", products: []models.ProductRevenue{{Product: "Free", Revenue: money("0")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(&stubRepository{products: tt.products})
+			data, _, err := service.GetRevenueConcentration(ctx, models.DateRange{}, []float64{50, 80}, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetRevenueConcentration: %v", err)
+			}
+			if len(data.Products) != len(tt.products) {
+				t.Errorf("got %d products, want %d", len(data.Products), len(tt.products))
+			}
+			for _, threshold := range data.Thresholds {
+				if threshold.Products != 0 {
+					t.Errorf("threshold %v reached by %d products, want none", threshold.Threshold, threshold.Products)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"abt-analytics/internal/models"
+)
+
+// seedColumns are the header names a seed CSV must contain, in any order.
+// Optional "category", "customer_id", "quantity" and "currency" columns may also
+// be present; quantity defaults to 1 and currency to the base currency.
+var seedColumns = []string{"order_id", "transaction_date", "country", "region", "product", "revenue"}
+
+// parseTransactionsCSV reads seed transactions from a CSV with a header row.
+// Dates may be RFC3339 or YYYY-MM-DD. In strict mode the first malformed row
+// aborts parsing; otherwise malformed rows are skipped and returned as errors
+// carrying their line numbers.
+func parseTransactionsCSV(r io.Reader, strict bool) ([]models.Transaction, []error, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range seedColumns {
+		if _, ok := index[column]; !ok {
+			return nil, nil, fmt.Errorf("missing required column %q", column)
+		}
+	}
+
+	var transactions []models.Transaction
+	var rowErrs []error
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var line int
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			line = parseErr.Line
+		} else if err != nil {
+			return nil, nil, err
+		} else {
+			line, _ = reader.FieldPos(0)
+			var transaction models.Transaction
+			transaction, err = parseSeedRecord(record, index)
+			if err == nil {
+				transactions = append(transactions, transaction)
+				continue
+			}
+		}
+
+		rowErr := fmt.Errorf("line %d: %w", line, err)
+		if strict {
+			return nil, nil, rowErr
+		}
+		rowErrs = append(rowErrs, rowErr)
+	}
+
+	return transactions, rowErrs, nil
+}
+
+func parseSeedRecord(record []string, index map[string]int) (models.Transaction, error) {
+	field := func(name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	for _, column := range seedColumns {
+		if field(column) == "" {
+			return models.Transaction{}, fmt.Errorf("missing %s", column)
+		}
+	}
+
+	date, err := parseSeedDate(field("transaction_date"))
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid transaction_date %q", field("transaction_date"))
+	}
+
+	revenue, err := decimal.NewFromString(field("revenue"))
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("invalid revenue %q", field("revenue"))
+	}
+
+	quantity := 1
+	if value := field("quantity"); value != "" {
+		quantity, err = strconv.Atoi(value)
+		if err != nil || quantity < 1 {
+			return models.Transaction{}, fmt.Errorf("invalid quantity %q", value)
+		}
+	}
+
+	currency := strings.ToUpper(field("currency"))
+	if currency != "" && !isCurrencyCode(currency) {
+		return models.Transaction{}, fmt.Errorf("invalid currency %q", field("currency"))
+	}
+
+	return models.Transaction{
+		OrderID:         field("order_id"),
+		CustomerID:      field("customer_id"),
+		TransactionDate: date,
+		Country:         field("country"),
+		Region:          field("region"),
+		Product:         field("product"),
+		Category:        field("category"),
+		Quantity:        quantity,
+		Revenue:         models.NewMoney(revenue),
+		Currency:        currency,
+	}, nil
+}
+
+// isCurrencyCode reports whether code is three upper-case letters, like an ISO 4217 code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSeedDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
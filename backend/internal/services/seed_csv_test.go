@@ -0,0 +1,99 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/config"
+)
+
+const seedFixture = "testdata/seed.csv"
+
+func openFixture(t *testing.T) *os.File {
+	t.Helper()
+	file, err := os.Open(seedFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestParseTransactionsCSVSkipsMalformedRows(t *testing.T) {
+	transactions, rowErrs, err := parseTransactionsCSV(openFixture(t), false)
+	if err != nil {
+		t.Fatalf("parseTransactionsCSV: %v", err)
+	}
+
+	var orders []string
+	for _, tx := range transactions {
+		orders = append(orders, tx.OrderID)
+	}
+	if want := []string{"ORD-1", "ORD-3", "ORD-5"}; strings.Join(orders, ",") != strings.Join(want, ",") {
+		t.Errorf("parsed orders %v, want %v", orders, want)
+	}
+
+	if len(rowErrs) != 2 {
+		t.Fatalf("row errors = %v, want 2", rowErrs)
+	}
+	for i, want := range []string{"line 3: invalid revenue", "line 5:"} {
+		if !strings.HasPrefix(rowErrs[i].Error(), want) {
+			t.Errorf("row error %d = %q, want it to start with %q", i, rowErrs[i], want)
+		}
+	}
+
+	first := transactions[0]
+	if !first.TransactionDate.Equal(time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)) || first.Currency != "USD" {
+		t.Errorf("first row = %+v", first)
+	}
+	assertMoney(t, "first revenue", first.Revenue, "1499.99")
+	if second := transactions[1]; second.Quantity != 1 || second.Currency != "" ||
+		!second.TransactionDate.Equal(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("row with defaults = %+v, want quantity 1 and no currency on 2024-01-07", second)
+	}
+}
+
+func TestParseTransactionsCSVStrictAborts(t *testing.T) {
+	transactions, _, err := parseTransactionsCSV(openFixture(t), true)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Fatalf("err = %v, want the malformed row on line 3", err)
+	}
+	if transactions != nil {
+		t.Errorf("strict parse returned %d transactions", len(transactions))
+	}
+}
+
+func TestParseTransactionsCSVMissingColumn(t *testing.T) {
+	_, _, err := parseTransactionsCSV(strings.NewReader("order_id,country\nORD-1,US\n"), false)
+	if err == nil || !strings.Contains(err.Error(), `"transaction_date"`) {
+		t.Errorf("err = %v, want the missing transaction_date column", err)
+	}
+}
+
+func TestSeedDataFromFile(t *testing.T) {
+	seeder := NewDataSeeder(newTestDB(t), &config.Config{SeedFile: seedFixture})
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatalf("SeedData: %v", err)
+	}
+	if rows, _ := countTransactions(t, seeder); rows != 3 {
+		t.Errorf("seeded %d rows, want the 3 well-formed ones", rows)
+	}
+
+	strict := NewDataSeeder(newTestDB(t), &config.Config{SeedFile: seedFixture, SeedStrict: true})
+	if err := strict.SeedData(ctx); err == nil {
+		t.Error("strict seeding accepted a malformed file")
+	}
+}
+
+func TestSeedDataFallsBackWithoutFile(t *testing.T) {
+	seeder := NewDataSeeder(newTestDB(t), &config.Config{SeedFile: filepath.Join(t.TempDir(), "missing.csv")})
+	if err := seeder.SeedData(ctx); err != nil {
+		t.Fatalf("SeedData: %v", err)
+	}
+	if rows, _ := countTransactions(t, seeder); rows != int64(len(generateSampleTransactions())) {
+		t.Errorf("seeded %d rows, want the built-in sample set", rows)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"abt-analytics/internal/models"
+)
+
+// Dashboard summary section names, as reported in SectionError
+const (
+	SectionCountryRevenue = "country_revenue"
+	SectionTopProducts    = "top_products"
+	SectionMonthlySales   = "monthly_sales"
+	SectionTopRegions     = "top_regions"
+)
+
+// GetSummary loads the dashboard sections concurrently, each over the date range:
+// country revenue, the first productLimit products, monthly sales and the top
+// regionCount regions. A failing
+// section is logged, left empty and reported in the summary's Errors instead of
+// failing the whole call; only an unknown currency or currencies that cannot be summed are
+// returned as an error. The boolean
+// reports whether the cache served every section.
+func (s *AnalyticsService) GetSummary(ctx context.Context, dateRange models.DateRange, productLimit, regionCount int, conversion models.Conversion) (*models.DashboardSummary, bool, error) {
+	if _, err := s.conversionRate(ctx, conversion); err != nil {
+		return nil, false, err
+	}
+
+	// sections start empty so a failed one still renders as [] rather than null
+	summary := models.DashboardSummary{
+		CountryRevenue: []models.CountryRevenue{},
+		TopProducts:    &models.ProductRevenuePage{Data: []models.ProductRevenue{}, Limit: productLimit},
+		MonthlySales:   []models.MonthlySales{},
+		TopRegions:     []models.RegionRevenue{},
+	}
+	var (
+		mu     sync.Mutex
+		allHit = true
+	)
+
+	// record collects a section's outcome; sections never fail the group so the others still complete
+	record := func(section string, hit bool, err error) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		allHit = allHit && hit
+		if err != nil {
+			log.Printf("Warning: dashboard summary section %s failed: %v", section, err)
+			summary.Errors = append(summary.Errors, models.SectionError{
+				Section: section,
+				Message: "Failed to load " + section,
+			})
+		}
+		return nil
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		data, hit, err := s.GetCountryRevenue(ctx, models.CountryFilter{DateRange: dateRange}, conversion)
+		if err == nil {
+			summary.CountryRevenue = data
+		}
+		return record(SectionCountryRevenue, hit, err)
+	})
+	g.Go(func() error {
+		data, hit, err := s.GetTopProducts(ctx, models.ProductFilter{DateRange: dateRange}, models.ProductSort{By: models.ProductSortRevenue}, productLimit, 0, conversion)
+		if err == nil {
+			summary.TopProducts = data
+		}
+		return record(SectionTopProducts, hit, err)
+	})
+	g.Go(func() error {
+		data, hit, err := s.GetMonthlySales(ctx, models.SalesFilter{DateRange: dateRange}, GranularityMonth, false, conversion)
+		if err == nil {
+			summary.MonthlySales = data
+		}
+		return record(SectionMonthlySales, hit, err)
+	})
+	g.Go(func() error {
+		data, hit, err := s.GetTopRegions(ctx, models.RegionFilter{DateRange: dateRange}, regionCount, conversion)
+		if err == nil {
+			summary.TopRegions = data
+		}
+		return record(SectionTopRegions, hit, err)
+	})
+	_ = g.Wait()
+
+	return &summary, allHit, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"abt-analytics/internal/models"
+)
+
+// summaryRepository returns a stub with data for every dashboard section
+func summaryRepository() *stubRepository {
+	return &stubRepository{
+		currencies:     []string{"USD"},
+		countryRevenue: []models.CountryRevenue{{Country: "US", Revenue: money("100"), Orders: 2}},
+		products: []models.ProductRevenue{
+			{Product: "Laptop", Units: 1, Revenue: money("90")},
+			{Product: "Mouse", Units: 1, Revenue: money("10")},
+		},
+		monthly: []models.MonthlySales{{Period: "2024-01", Revenue: money("100")}},
+		regions: []models.RegionRevenue{{Region: "California", Revenue: money("100"), Orders: 2}},
+	}
+}
+
+func TestGetSummaryPopulatesEverySection(t *testing.T) {
+	summary, _, err := newTestService(summaryRepository()).GetSummary(ctx, models.DateRange{}, 1, 5, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+
+	if len(summary.CountryRevenue) != 1 {
+		t.Errorf("country revenue = %+v", summary.CountryRevenue)
+	}
+	if summary.TopProducts == nil || len(summary.TopProducts.Data) != 1 || summary.TopProducts.Data[0].Product != "Laptop" {
+		t.Errorf("top products = %+v, want the first product only", summary.TopProducts)
+	}
+	if len(summary.MonthlySales) != 1 {
+		t.Errorf("monthly sales = %+v", summary.MonthlySales)
+	}
+	if len(summary.TopRegions) != 1 {
+		t.Errorf("top regions = %+v", summary.TopRegions)
+	}
+	if len(summary.Errors) != 0 {
+		t.Errorf("errors = %+v, want none", summary.Errors)
+	}
+}
+
+func TestGetSummaryReportsFailedSection(t *testing.T) {
+	repo := summaryRepository()
+	repo.errs = map[string]error{"GetTopRegions": errors.New("connection reset")}
+
+	summary, _, err := newTestService(repo).GetSummary(ctx, models.DateRange{}, 2, 5, models.Conversion{})
+	if err != nil {
+		t.Fatalf("GetSummary = %v, want a partial result", err)
+	}
+	if len(summary.Errors) != 1 || summary.Errors[0].Section != SectionTopRegions {
+		t.Errorf("errors = %+v, want only %s", summary.Errors, SectionTopRegions)
+	}
+	if len(summary.CountryRevenue) != 1 || len(summary.MonthlySales) != 1 || len(summary.TopProducts.Data) != 2 {
+		t.Error("the sections that succeeded are missing from the partial result")
+	}
+}
+
+func TestGetSummaryRendersFailedSectionsEmpty(t *testing.T) {
+	tests := []struct {
+		method  string
+		section string
+		// field picks the section's list out of its JSON
+		field func(section json.RawMessage) json.RawMessage
+	}{
+		{"GetCountryRevenue", SectionCountryRevenue, nil},
+		{"GetTopProducts", SectionTopProducts, func(section json.RawMessage) json.RawMessage {
+			var page struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(section, &page); err != nil {
+				t.Fatalf("decode top products: %v", err)
+			}
+			return page.Data
+		}},
+		{"GetMonthlySales", SectionMonthlySales, nil},
+		{"GetTopRegions", SectionTopRegions, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.section, func(t *testing.T) {
+			repo := summaryRepository()
+			repo.errs = map[string]error{tt.method: errors.New("connection reset")}
+
+			summary, _, err := newTestService(repo).GetSummary(ctx, models.DateRange{}, 2, 5, models.Conversion{})
+			if err != nil {
+				t.Fatalf("GetSummary = %v, want a partial result", err)
+			}
+			body, err := json.Marshal(summary)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			var sections map[string]json.RawMessage
+			if err := json.Unmarshal(body, &sections); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			got := sections[tt.section]
+			if tt.field != nil {
+				got = tt.field(got)
+			}
+			if string(got) != "[]" {
+				t.Errorf("%s = %s, want []; body %s", tt.section, got, body)
+			}
+		})
+	}
+}
+
+func TestGetSummaryQueriesSectionsConcurrently(t *testing.T) {
+	sections := map[string]bool{"GetCountryRevenue": true, "GetTopProducts": true, "GetMonthlySales": true, "GetTopRegions": true}
+	var entered sync.WaitGroup
+	entered.Add(len(sections))
+	allEntered := make(chan struct{})
+	go func() {
+		entered.Wait()
+		close(allEntered)
+	}()
+
+	// Every section query blocks until all four are running, which never
+	// happens if they run one after the other
+	repo := summaryRepository()
+	repo.onCall = func(method string) {
+		if !sections[method] {
+			return
+		}
+		entered.Done()
+		select {
+		case <-allEntered:
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	start := time.Now()
+	if _, _, err := newTestService(repo).GetSummary(ctx, models.DateRange{}, 2, 5, models.Conversion{}); err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	select {
+	case <-allEntered:
+	default:
+		t.Fatal("the section queries did not all run at once")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetSummary took %s, want the sections to overlap", elapsed)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/timing"
+)
+
+// timedRepository records the duration of every repository call in the request's
+// timing.Recorder, named after the method, for the Server-Timing header
+type timedRepository struct {
+	repo AnalyticsRepository
+}
+
+func (r timedRepository) Ping(ctx context.Context) error {
+	defer timing.Start(ctx, "Ping")()
+	return r.repo.Ping(ctx)
+}
+
+func (r timedRepository) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	defer timing.Start(ctx, "GetMigrationStatus")()
+	return r.repo.GetMigrationStatus(ctx)
+}
+
+func (r timedRepository) GetCountryRevenue(ctx context.Context, filter models.CountryFilter) ([]models.CountryRevenue, error) {
+	defer timing.Start(ctx, "GetCountryRevenue")()
+	return r.repo.GetCountryRevenue(ctx, filter)
+}
+
+func (r timedRepository) GetCategoryRevenue(ctx context.Context, dateRange models.DateRange) ([]models.CategoryRevenue, error) {
+	defer timing.Start(ctx, "GetCategoryRevenue")()
+	return r.repo.GetCategoryRevenue(ctx, dateRange)
+}
+
+func (r timedRepository) GetAverageOrderValue(ctx context.Context, dateRange models.DateRange) ([]models.CountryOrderValue, error) {
+	defer timing.Start(ctx, "GetAverageOrderValue")()
+	return r.repo.GetAverageOrderValue(ctx, dateRange)
+}
+
+func (r timedRepository) GetOrderValuePercentiles(ctx context.Context, dateRange models.DateRange) (*models.OrderValuePercentiles, error) {
+	defer timing.Start(ctx, "GetOrderValuePercentiles")()
+	return r.repo.GetOrderValuePercentiles(ctx, dateRange)
+}
+
+func (r timedRepository) GetPeriodTotals(ctx context.Context, dateRange models.DateRange) (*models.PeriodTotals, error) {
+	defer timing.Start(ctx, "GetPeriodTotals")()
+	return r.repo.GetPeriodTotals(ctx, dateRange)
+}
+
+func (r timedRepository) GetTopProducts(ctx context.Context, filter models.ProductFilter, sort models.ProductSort, limit, offset int) ([]models.ProductRevenue, int64, error) {
+	defer timing.Start(ctx, "GetTopProducts")()
+	return r.repo.GetTopProducts(ctx, filter, sort, limit, offset)
+}
+
+func (r timedRepository) GetRankedProducts(ctx context.Context, dateRange models.DateRange) ([]models.ProductRevenue, error) {
+	defer timing.Start(ctx, "GetRankedProducts")()
+	return r.repo.GetRankedProducts(ctx, dateRange)
+}
+
+func (r timedRepository) GetTopCustomers(ctx context.Context, dateRange models.DateRange, limit, offset int) ([]models.CustomerRevenue, int64, error) {
+	defer timing.Start(ctx, "GetTopCustomers")()
+	return r.repo.GetTopCustomers(ctx, dateRange, limit, offset)
+}
+
+func (r timedRepository) GetMonthlySales(ctx context.Context, filter models.SalesFilter) ([]models.MonthlySales, error) {
+	defer timing.Start(ctx, "GetMonthlySales")()
+	return r.repo.GetMonthlySales(ctx, filter)
+}
+
+func (r timedRepository) GetDailyRevenue(ctx context.Context, dateRange models.DateRange) ([]models.DailyRevenue, error) {
+	defer timing.Start(ctx, "GetDailyRevenue")()
+	return r.repo.GetDailyRevenue(ctx, dateRange)
+}
+
+func (r timedRepository) GetTopRegions(ctx context.Context, filter models.RegionFilter, n int) ([]models.RegionRevenue, error) {
+	defer timing.Start(ctx, "GetTopRegions")()
+	return r.repo.GetTopRegions(ctx, filter, n)
+}
+
+func (r timedRepository) GetRegionTrends(ctx context.Context, dateRange models.DateRange, n int) ([]models.RegionMonthRevenue, error) {
+	defer timing.Start(ctx, "GetRegionTrends")()
+	return r.repo.GetRegionTrends(ctx, dateRange, n)
+}
+
+func (r timedRepository) ListTransactions(ctx context.Context, filter models.TransactionFilter, after *models.TransactionCursor, limit, offset int) ([]models.Transaction, int64, error) {
+	defer timing.Start(ctx, "ListTransactions")()
+	return r.repo.ListTransactions(ctx, filter, after, limit, offset)
+}
+
+func (r timedRepository) InsertTransactions(ctx context.Context, transactions []models.Transaction) error {
+	defer timing.Start(ctx, "InsertTransactions")()
+	return r.repo.InsertTransactions(ctx, transactions)
+}
+
+func (r timedRepository) DeleteTransaction(ctx context.Context, id uint) error {
+	defer timing.Start(ctx, "DeleteTransaction")()
+	return r.repo.DeleteTransaction(ctx, id)
+}
+
+func (r timedRepository) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	defer timing.Start(ctx, "GetTransaction")()
+	return r.repo.GetTransaction(ctx, id)
+}
+
+func (r timedRepository) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(models.Transaction) error) error {
+	defer timing.Start(ctx, "StreamTransactions")()
+	return r.repo.StreamTransactions(ctx, filter, fn)
+}
+
+func (r timedRepository) GetDataMeta(ctx context.Context) (*models.DataMeta, error) {
+	defer timing.Start(ctx, "GetDataMeta")()
+	return r.repo.GetDataMeta(ctx)
+}
+
+func (r timedRepository) GetDimensions(ctx context.Context) (*models.Dimensions, error) {
+	defer timing.Start(ctx, "GetDimensions")()
+	return r.repo.GetDimensions(ctx)
+}
+
+func (r timedRepository) GetCurrencies(ctx context.Context) ([]string, error) {
+	defer timing.Start(ctx, "GetCurrencies")()
+	return r.repo.GetCurrencies(ctx)
+}
@@ -0,0 +1,65 @@
+// Package timing collects per-request durations of named operations, such as
+// database queries, for the Server-Timing response header.
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type contextKey struct{}
+
+// Entry is one timed operation
+type Entry struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Recorder accumulates the entries of one request. It is safe for concurrent
+// use, since some requests run their queries in parallel.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewContext returns a copy of ctx carrying r
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// Start begins timing the named operation and returns the function that ends it.
+// Both are no-ops when ctx carries no Recorder.
+func Start(ctx context.Context, name string) func() {
+	r, ok := ctx.Value(contextKey{}).(*Recorder)
+	if !ok {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.entries = append(r.entries, Entry{Name: name, Duration: time.Since(start)})
+	}
+}
+
+// Entries returns the operations recorded so far, in completion order
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Header formats the entries as a Server-Timing header value with millisecond
+// durations, e.g. "GetTopProducts;dur=1.42, GetTopRegions;dur=0.87"
+func (r *Recorder) Header() string {
+	entries := r.Entries()
+	metrics := make([]string, len(entries))
+	for i, entry := range entries {
+		metrics[i] = fmt.Sprintf("%s;dur=%.2f", entry.Name, float64(entry.Duration.Microseconds())/1000)
+	}
+	return strings.Join(metrics, ", ")
+}
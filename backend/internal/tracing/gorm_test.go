@@ -0,0 +1,82 @@
+package tracing_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"abt-analytics/internal/database"
+	"abt-analytics/internal/models"
+	"abt-analytics/internal/repository"
+	"abt-analytics/internal/tracing"
+)
+
+// recordSpans installs a tracer provider recording every span in memory for the
+// duration of the test
+func recordSpans(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder, provider
+}
+
+func TestGormPluginRecordsQuerySpans(t *testing.T) {
+	recorder, provider := recordSpans(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		t.Fatalf("install plugin: %v", err)
+	}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "GET /analytics/country-revenue")
+	repo := repository.NewAnalyticsRepository(db, nil)
+	if _, err := repo.GetCountryRevenue(ctx, models.CountryFilter{Countries: []string{"US"}}); err != nil {
+		t.Fatalf("GetCountryRevenue: %v", err)
+	}
+	parent.End()
+
+	var query sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if strings.HasPrefix(span.Name(), "gorm.") {
+			query = span
+		}
+	}
+	if query == nil {
+		t.Fatalf("no GORM span among %d recorded", len(recorder.Ended()))
+	}
+	if query.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("the query span is not a child of the request span")
+	}
+
+	attrs := make(map[string]string)
+	for _, attr := range query.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs[string(semconv.DBSystemKey)] != "sqlite" {
+		t.Errorf("db.system = %q, want sqlite", attrs[string(semconv.DBSystemKey)])
+	}
+	if statement := attrs[string(semconv.DBStatementKey)]; !strings.Contains(statement, "GROUP BY") || !strings.Contains(statement, "country") {
+		t.Errorf("db.statement = %q, want the country revenue query", statement)
+	}
+}